@@ -25,7 +25,7 @@ const ID = gurvy.BLS377
 var bCurveCoeff fp.Element
 
 // bTwistCurveCoeff b coeff of the twist (defined over Fp2) curve
-var bTwistCurveCoeff e2
+var bTwistCurveCoeff E2
 
 // generators of the r-torsion group, resp. in ker(pi-id), ker(Tr)
 var g1Gen G1Jac
@@ -54,26 +54,19 @@ var lambdaGLV big.Int
 // in ker((u,v)->u+vlambda[r]), and their determinant
 var glvBasis utils.Lattice
 
+// lambdaGT is the eigenvalue of the Frobenius on GT (p mod r), glvBasisGT the associated lattice
+var lambdaGT big.Int
+var glvBasisGT utils.Lattice
+
 // psi o pi o psi**-1, where psi:E->E' is the degree 6 iso defined over Fp12
 var endo struct {
-	u e2
-	v e2
+	u E2
+	v E2
 }
 
 // generator of the curve
 var xGen big.Int
 
-// expose the tower -- github.com/consensys/gnark uses it in a gnark circuit
-
-// E2 is a degree two finite field extension of fp.Element
-type E2 = e2
-
-// E6 is a degree three finite field extension of fp2
-type E6 = e6
-
-// E12 is a degree two finite field extension of fp6
-type E12 = e12
-
 func init() {
 
 	bCurveCoeff.SetUint64(1)
@@ -104,6 +97,8 @@ func init() {
 	lambdaGLV.SetString("91893752504881257701523279626832445440", 10) //(x**2-1)
 	_r := fr.Modulus()
 	utils.PrecomputeLattice(_r, &lambdaGLV, &glvBasis)
+	lambdaGT.Mod(fp.Modulus(), _r)
+	utils.PrecomputeLattice(_r, &lambdaGT, &glvBasisGT)
 
 	endo.u.A0.SetString("80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410946")
 	endo.v.A0.SetString("216465761340224619389371505802605247630151569547285782856803747159100223055385581585702401816380679166954762214499")
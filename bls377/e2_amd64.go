@@ -24,7 +24,7 @@ import (
 var supportAdx = cpu.X86.HasADX && cpu.X86.HasBMI2
 
 // q (modulus)
-var qe2 = [6]uint64{
+var qE2 = [6]uint64{
 	9586122913090633729,
 	1660523435060625408,
 	2230234197602682880,
@@ -34,16 +34,16 @@ var qe2 = [6]uint64{
 }
 
 // q'[0], see montgommery multiplication algorithm
-var qe2Inv0 uint64 = 9586122913090633727
+var qE2Inv0 uint64 = 9586122913090633727
 
 //go:noescape
-func addE2(res, x, y *e2)
+func addE2(res, x, y *E2)
 
 //go:noescape
-func subE2(res, x, y *e2)
+func subE2(res, x, y *E2)
 
 //go:noescape
-func doubleE2(res, x *e2)
+func doubleE2(res, x *E2)
 
 //go:noescape
-func negE2(res, x *e2)
+func negE2(res, x *E2)
@@ -0,0 +1,57 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gurvy DO NOT EDIT
+
+package fr
+
+import (
+	"encoding/binary"
+	"io"
+	"math/bits"
+)
+
+// SetRandomFrom sets z to a uniformly random element using the randomness read from r, and returns z
+//
+// Unlike SetRandom, the output is not biased: Bits bits are read from r until they encode a value
+// smaller than the modulus (rejection sampling). Using a seeded r makes the output reproducible.
+func (z *Element) SetRandomFrom(r io.Reader) (*Element, error) {
+	// the most significant limb holds topBits bits
+	const topBits = Bits - (Limbs-1)*64
+	const topMask = ^uint64(0) >> (64 - topBits)
+
+	var buf [Limbs * 8]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		for i := 0; i < Limbs; i++ {
+			z[i] = binary.LittleEndian.Uint64(buf[i*8:])
+		}
+		z[Limbs-1] &= topMask
+
+		if z.smallerThanModulus() {
+			return z, nil
+		}
+	}
+}
+
+// smallerThanModulus returns true if z < q, z being seen as an integer (not reduced)
+func (z *Element) smallerThanModulus() bool {
+	var b uint64
+	for i := 0; i < Limbs; i++ {
+		_, b = bits.Sub64(z[i], qElement[i], b)
+	}
+	return b == 1
+}
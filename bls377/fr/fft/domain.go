@@ -0,0 +1,291 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gurvy DO NOT EDIT
+
+// Package fft provides in-place discrete Fourier transforms on the 2-adic subgroups of fr and their cosets
+package fft
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gurvy/bls377/fr"
+	"github.com/consensys/gurvy/utils/parallel"
+)
+
+// maxOrderRoot is the 2-adicity of r-1: fr has roots of unity of order up to 2^maxOrderRoot
+const maxOrderRoot uint64 = 47
+
+// rootOfUnity is a primitive 2^maxOrderRoot-th root of unity of fr
+// cosetShift is not in any subgroup of order a power of 2, the cosets of the domains are cosetShift·<Generator>
+var rootOfUnity, cosetShift fr.Element
+
+func init() {
+	rootOfUnity.SetString("6924886788847882060123066508223519077232160750698452411071850219367055984476")
+	cosetShift.SetUint64(11)
+}
+
+// Domain is the subgroup of the n-th roots of unity of fr, n a power of 2, on which polynomials of
+// less than n coefficients are evaluated (FFT) and interpolated (FFTInverse)
+type Domain struct {
+	Cardinality    uint64
+	CardinalityInv fr.Element
+	Generator      fr.Element // primitive n-th root of unity
+	GeneratorInv   fr.Element
+	CosetShift     fr.Element // coset FFTs evaluate on CosetShift·<Generator>
+	CosetShiftInv  fr.Element
+
+	// twiddles[i] = Generator^i for i < n/2, the inverse FFT uses Generator^-i = -twiddles[n/2-i]
+	twiddles []fr.Element
+}
+
+// NewDomain returns the domain of the n-th roots of unity, n being the smallest power of 2 >= m
+func NewDomain(m uint64) (*Domain, error) {
+	logN := uint64(bits.Len64(m - 1))
+	if m <= 1 {
+		logN = 0
+	}
+	if logN > maxOrderRoot {
+		return nil, errors.New("domain size is larger than the largest power of 2 dividing r-1")
+	}
+
+	d := &Domain{Cardinality: uint64(1) << logN}
+	d.CardinalityInv.SetUint64(d.Cardinality).Inverse(&d.CardinalityInv)
+
+	// Generator = rootOfUnity^(2^(maxOrderRoot - logN))
+	d.Generator = rootOfUnity
+	for i := logN; i < maxOrderRoot; i++ {
+		d.Generator.Square(&d.Generator)
+	}
+	d.GeneratorInv.Inverse(&d.Generator)
+	d.CosetShift = cosetShift
+	d.CosetShiftInv.Inverse(&cosetShift)
+
+	d.twiddles = make([]fr.Element, d.Cardinality/2)
+	parallel.Execute(len(d.twiddles), func(start, end int) {
+		var w fr.Element
+		w.Exp(d.Generator, new(big.Int).SetUint64(uint64(start)))
+		for i := start; i < end; i++ {
+			d.twiddles[i] = w
+			w.Mul(&w, &d.Generator)
+		}
+	})
+
+	return d, nil
+}
+
+// Decimation selects the FFT variant, and hence the order of the input and output
+type Decimation uint8
+
+const (
+	// DIT (decimation in time) takes a bit-reversed input and returns a natural order output
+	DIT Decimation = iota
+	// DIF (decimation in frequency) takes a natural order input and returns a bit-reversed output
+	DIF
+)
+
+// fftBlockSize is the number of elements of the blocks on which the FFT layers are run one after the other
+// (fitting in the cache), larger layers are run one at a time on the whole input, split between the cpus
+const fftBlockSize = 1 << 12
+
+// FFT evaluates in place the polynomial of coefficients a on the domain (on its coset if coset is set):
+// a[i] is set to the value at Generator^i (CosetShift·Generator^i), the input and output orders
+// depending on decimation. len(a) must be the cardinality of the domain
+func (d *Domain) FFT(a []fr.Element, decimation Decimation, coset bool) {
+	d.checkSize(a)
+
+	if coset {
+		// a[i] *= CosetShift^i, i being bit-reversed in DIT
+		if decimation == DIT {
+			BitReverse(a)
+		}
+		scalePowers(a, &d.CosetShift, nil)
+		if decimation == DIT {
+			BitReverse(a)
+		}
+	}
+
+	d.run(a, decimation, false)
+}
+
+// FFTInverse interpolates in place the polynomial which values on the domain (on its coset if coset is set)
+// are a, the inverse of FFT with the same parameters. The input and output orders depend on decimation
+func (d *Domain) FFTInverse(a []fr.Element, decimation Decimation, coset bool) {
+	d.checkSize(a)
+
+	d.run(a, decimation, true)
+
+	if !coset {
+		scalePowers(a, nil, &d.CardinalityInv)
+		return
+	}
+
+	// a[i] *= CosetShift^-i / n, i being bit-reversed in DIF
+	if decimation == DIF {
+		BitReverse(a)
+	}
+	scalePowers(a, &d.CosetShiftInv, &d.CardinalityInv)
+	if decimation == DIF {
+		BitReverse(a)
+	}
+}
+
+func (d *Domain) checkSize(a []fr.Element) {
+	if uint64(len(a)) != d.Cardinality {
+		panic("fft: len(a) is not the cardinality of the domain")
+	}
+}
+
+// run runs the butterfly layers of the FFT (or of the inverse FFT, without the scaling by 1/n)
+//
+// the layers on blocks larger than fftBlockSize are parallelized over the butterflies, the smaller ones
+// over the blocks, each block going through all its layers at once
+func (d *Domain) run(a []fr.Element, decimation Decimation, inverse bool) {
+	n := len(a)
+	if n == 1 {
+		return
+	}
+	blockSize := n
+	if blockSize > fftBlockSize {
+		blockSize = fftBlockSize
+	}
+
+	largeLayer := func(m int) {
+		parallel.Execute(n/2, func(start, end int) {
+			d.layer(a, m, n/(2*m), start, end, decimation, inverse)
+		})
+	}
+	blocks := func() {
+		process := func(start, end int) {
+			for k := start; k < end; k++ {
+				block := a[k*blockSize : (k+1)*blockSize]
+				if decimation == DIF {
+					for m := blockSize / 2; m >= 1; m >>= 1 {
+						d.layer(block, m, n/(2*m), 0, blockSize/2, decimation, inverse)
+					}
+				} else {
+					for m := 1; m < blockSize; m <<= 1 {
+						d.layer(block, m, n/(2*m), 0, blockSize/2, decimation, inverse)
+					}
+				}
+			}
+		}
+		if blockSize == n {
+			process(0, 1)
+		} else {
+			parallel.Execute(n/blockSize, process)
+		}
+	}
+
+	if decimation == DIF {
+		for m := n / 2; m >= blockSize; m >>= 1 {
+			largeLayer(m)
+		}
+		blocks()
+	} else {
+		blocks()
+		for m := blockSize; m < n; m <<= 1 {
+			largeLayer(m)
+		}
+	}
+}
+
+// layer applies the butterflies [start, end) of the layer of half-size m to a, the butterflies being numbered
+// across the blocks of size 2m. The twiddle of the j-th butterfly of a block is Generator^(j·stride)
+//
+// DIF: (u, v) -> (u + v, (u - v)·w)
+// DIT: (u, v) -> (u + w·v, u - w·v)
+// the inverse FFT uses w^-1 = -twiddles[n/2 - j·stride]
+func (d *Domain) layer(a []fr.Element, m, stride, start, end int, decimation Decimation, inverse bool) {
+	half := len(d.twiddles)
+	j := start % m
+	i := (start/m)*2*m + j
+	var t fr.Element
+	for b := start; b < end; b++ {
+		u, v := &a[i], &a[i+m]
+		idx := j * stride
+		switch {
+		case idx == 0:
+			t = *v
+			v.Sub(u, &t)
+			u.Add(u, &t)
+		case decimation == DIF && !inverse:
+			t.Sub(u, v)
+			u.Add(u, v)
+			v.Mul(&t, &d.twiddles[idx])
+		case decimation == DIF && inverse:
+			t.Sub(v, u)
+			u.Add(u, v)
+			v.Mul(&t, &d.twiddles[half-idx])
+		case !inverse:
+			t.Mul(v, &d.twiddles[idx])
+			v.Sub(u, &t)
+			u.Add(u, &t)
+		default:
+			t.Mul(v, &d.twiddles[half-idx])
+			v.Add(u, &t)
+			u.Sub(u, &t)
+		}
+
+		j++
+		i++
+		if j == m {
+			j = 0
+			i += m
+		}
+	}
+}
+
+// scalePowers sets a[i] = a[i]·c·g^i, g or c being ignored if nil
+func scalePowers(a []fr.Element, g, c *fr.Element) {
+	if g == nil && c == nil {
+		return
+	}
+	parallel.Execute(len(a), func(start, end int) {
+		var acc fr.Element
+		if g != nil {
+			acc.Exp(*g, new(big.Int).SetUint64(uint64(start)))
+		} else {
+			acc.SetOne()
+		}
+		if c != nil {
+			acc.Mul(&acc, c)
+		}
+		for i := start; i < end; i++ {
+			a[i].Mul(&a[i], &acc)
+			if g != nil {
+				acc.Mul(&acc, g)
+			}
+		}
+	})
+}
+
+// BitReverse permutes a in place, a[i] being swapped with a[bitReverse(i)]. len(a) must be a power of 2
+func BitReverse(a []fr.Element) {
+	n := uint64(len(a))
+	if n <= 1 {
+		return
+	}
+	shift := 64 - uint64(bits.TrailingZeros64(n))
+	parallel.Execute(len(a), func(start, end int) {
+		for i := uint64(start); i < uint64(end); i++ {
+			iRev := bits.Reverse64(i) >> shift
+			if i < iRev {
+				a[i], a[iRev] = a[iRev], a[i]
+			}
+		}
+	})
+}
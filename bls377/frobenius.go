@@ -21,7 +21,7 @@ import "github.com/consensys/gurvy/bls377/fp"
 // Frobenius set z to Frobenius(x), return z
 func (z *GT) Frobenius(x *GT) *GT {
 	// Algorithm 28 from https://eprint.iacr.org/2010/354.pdf (beware typos!)
-	var t [6]e2
+	var t [6]E2
 
 	// Frobenius acts on fp2 by conjugation
 	t[0].Conjugate(&x.C0.B0)
@@ -50,7 +50,7 @@ func (z *GT) Frobenius(x *GT) *GT {
 // FrobeniusSquare set z to Frobenius^2(x), and return z
 func (z *GT) FrobeniusSquare(x *GT) *GT {
 	// Algorithm 29 from https://eprint.iacr.org/2010/354.pdf (beware typos!)
-	var t [6]e2
+	var t [6]E2
 
 	t[1].MulByNonResidue2Power2(&x.C0.B1)
 	t[2].MulByNonResidue2Power4(&x.C0.B2)
@@ -71,7 +71,7 @@ func (z *GT) FrobeniusSquare(x *GT) *GT {
 // FrobeniusCube set z to Frobenius^3(x), return z
 func (z *GT) FrobeniusCube(x *GT) *GT {
 	// Algorithm 30 from https://eprint.iacr.org/2010/354.pdf (beware typos!)
-	var t [6]e2
+	var t [6]E2
 
 	// Frobenius^3 acts on fp2 by conjugation
 	t[0].Conjugate(&x.C0.B0)
@@ -98,7 +98,7 @@ func (z *GT) FrobeniusCube(x *GT) *GT {
 }
 
 // MulByNonResidue1Power1 set z=x*(0,1)^(1*(p^1-1)/6) and return z
-func (z *e2) MulByNonResidue1Power1(x *e2) *e2 {
+func (z *E2) MulByNonResidue1Power1(x *E2) *E2 {
 	// 92949345220277864758624960506473182677953048909283248980960104381795901929519566951595905490535835115111760994353
 	b := fp.Element{
 		7981638599956744862,
@@ -114,7 +114,7 @@ func (z *e2) MulByNonResidue1Power1(x *e2) *e2 {
 }
 
 // MulByNonResidue1Power2 set z=x*(0,1)^(2*(p^1-1)/6) and return z
-func (z *e2) MulByNonResidue1Power2(x *e2) *e2 {
+func (z *E2) MulByNonResidue1Power2(x *E2) *E2 {
 	// 80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410946
 	b := fp.Element{
 		6382252053795993818,
@@ -130,7 +130,7 @@ func (z *e2) MulByNonResidue1Power2(x *e2) *e2 {
 }
 
 // MulByNonResidue1Power3 set z=x*(0,1)^(3*(p^1-1)/6) and return z
-func (z *e2) MulByNonResidue1Power3(x *e2) *e2 {
+func (z *E2) MulByNonResidue1Power3(x *E2) *E2 {
 	// 216465761340224619389371505802605247630151569547285782856803747159100223055385581585702401816380679166954762214499
 	b := fp.Element{
 		10965161018967488287,
@@ -146,7 +146,7 @@ func (z *e2) MulByNonResidue1Power3(x *e2) *e2 {
 }
 
 // MulByNonResidue1Power4 set z=x*(0,1)^(4*(p^1-1)/6) and return z
-func (z *e2) MulByNonResidue1Power4(x *e2) *e2 {
+func (z *E2) MulByNonResidue1Power4(x *E2) *E2 {
 	// 80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410945
 	b := fp.Element{
 		15766275933608376691,
@@ -162,7 +162,7 @@ func (z *e2) MulByNonResidue1Power4(x *e2) *e2 {
 }
 
 // MulByNonResidue1Power5 set z=x*(0,1)^(5*(p^1-1)/6) and return z
-func (z *e2) MulByNonResidue1Power5(x *e2) *e2 {
+func (z *E2) MulByNonResidue1Power5(x *E2) *E2 {
 	// 123516416119946754630746545296132064952198520638002533875843642777304321125866014634106496325844844051843001220146
 	b := fp.Element{
 		2983522419010743425,
@@ -178,7 +178,7 @@ func (z *e2) MulByNonResidue1Power5(x *e2) *e2 {
 }
 
 // MulByNonResidue2Power1 set z=x*(0,1)^(1*(p^2-1)/6) and return z
-func (z *e2) MulByNonResidue2Power1(x *e2) *e2 {
+func (z *E2) MulByNonResidue2Power1(x *E2) *E2 {
 	// 80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410946
 	b := fp.Element{
 		6382252053795993818,
@@ -194,7 +194,7 @@ func (z *e2) MulByNonResidue2Power1(x *e2) *e2 {
 }
 
 // MulByNonResidue2Power2 set z=x*(0,1)^(2*(p^2-1)/6) and return z
-func (z *e2) MulByNonResidue2Power2(x *e2) *e2 {
+func (z *E2) MulByNonResidue2Power2(x *E2) *E2 {
 	// 80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410945
 	b := fp.Element{
 		15766275933608376691,
@@ -210,7 +210,7 @@ func (z *e2) MulByNonResidue2Power2(x *e2) *e2 {
 }
 
 // MulByNonResidue2Power3 set z=x*(0,1)^(3*(p^2-1)/6) and return z
-func (z *e2) MulByNonResidue2Power3(x *e2) *e2 {
+func (z *E2) MulByNonResidue2Power3(x *E2) *E2 {
 	// 258664426012969094010652733694893533536393512754914660539884262666720468348340822774968888139573360124440321458176
 	b := fp.Element{
 		9384023879812382873,
@@ -226,7 +226,7 @@ func (z *e2) MulByNonResidue2Power3(x *e2) *e2 {
 }
 
 // MulByNonResidue2Power4 set z=x*(0,1)^(4*(p^2-1)/6) and return z
-func (z *e2) MulByNonResidue2Power4(x *e2) *e2 {
+func (z *E2) MulByNonResidue2Power4(x *E2) *E2 {
 	// 258664426012969093929703085429980814127835149614277183275038967946009968870203535512256352201271898244626862047231
 	b := fp.Element{
 		3203870859294639911,
@@ -242,7 +242,7 @@ func (z *e2) MulByNonResidue2Power4(x *e2) *e2 {
 }
 
 // MulByNonResidue2Power5 set z=x*(0,1)^(5*(p^2-1)/6) and return z
-func (z *e2) MulByNonResidue2Power5(x *e2) *e2 {
+func (z *E2) MulByNonResidue2Power5(x *E2) *E2 {
 	// 258664426012969093929703085429980814127835149614277183275038967946009968870203535512256352201271898244626862047232
 	b := fp.Element{
 		12266591053191808654,
@@ -258,7 +258,7 @@ func (z *e2) MulByNonResidue2Power5(x *e2) *e2 {
 }
 
 // MulByNonResidue3Power1 set z=x*(0,1)^(1*(p^3-1)/6) and return z
-func (z *e2) MulByNonResidue3Power1(x *e2) *e2 {
+func (z *E2) MulByNonResidue3Power1(x *E2) *E2 {
 	// 216465761340224619389371505802605247630151569547285782856803747159100223055385581585702401816380679166954762214499
 	b := fp.Element{
 		10965161018967488287,
@@ -274,7 +274,7 @@ func (z *e2) MulByNonResidue3Power1(x *e2) *e2 {
 }
 
 // MulByNonResidue3Power2 set z=x*(0,1)^(2*(p^3-1)/6) and return z
-func (z *e2) MulByNonResidue3Power2(x *e2) *e2 {
+func (z *E2) MulByNonResidue3Power2(x *E2) *E2 {
 	// 258664426012969094010652733694893533536393512754914660539884262666720468348340822774968888139573360124440321458176
 	b := fp.Element{
 		9384023879812382873,
@@ -290,7 +290,7 @@ func (z *e2) MulByNonResidue3Power2(x *e2) *e2 {
 }
 
 // MulByNonResidue3Power3 set z=x*(0,1)^(3*(p^3-1)/6) and return z
-func (z *e2) MulByNonResidue3Power3(x *e2) *e2 {
+func (z *E2) MulByNonResidue3Power3(x *E2) *E2 {
 	// 42198664672744474621281227892288285906241943207628877683080515507620245292955241189266486323192680957485559243678
 	b := fp.Element{
 		17067705967832697058,
@@ -306,14 +306,14 @@ func (z *e2) MulByNonResidue3Power3(x *e2) *e2 {
 }
 
 // MulByNonResidue3Power4 set z=x*(0,1)^(4*(p^3-1)/6) and return z
-func (z *e2) MulByNonResidue3Power4(x *e2) *e2 {
+func (z *E2) MulByNonResidue3Power4(x *E2) *E2 {
 	// 1
 	// nothing to do
 	return z
 }
 
 // MulByNonResidue3Power5 set z=x*(0,1)^(5*(p^3-1)/6) and return z
-func (z *e2) MulByNonResidue3Power5(x *e2) *e2 {
+func (z *E2) MulByNonResidue3Power5(x *E2) *E2 {
 	// 216465761340224619389371505802605247630151569547285782856803747159100223055385581585702401816380679166954762214499
 	b := fp.Element{
 		10965161018967488287,
@@ -17,26 +17,29 @@
 package bls377
 
 import (
+	"io"
 	"math/big"
+	"sync"
 
+	"github.com/consensys/gurvy/bls377/fp"
 	"github.com/consensys/gurvy/bls377/fr"
 	"github.com/consensys/gurvy/utils"
 	"github.com/consensys/gurvy/utils/parallel"
 )
 
-// G2Jac is a point with e2 coordinates
+// G2Jac is a point with E2 coordinates
 type G2Jac struct {
-	X, Y, Z e2
+	X, Y, Z E2
 }
 
 // G2Proj point in projective coordinates
 type G2Proj struct {
-	X, Y, Z e2
+	X, Y, Z E2
 }
 
 // G2Affine point in affine coordinates
 type G2Affine struct {
-	X, Y e2
+	X, Y E2
 }
 
 // AddAssign point addition in montgomery form
@@ -54,7 +57,7 @@ func (p *G2Jac) AddAssign(a *G2Jac) *G2Jac {
 		return p
 	}
 
-	var Z1Z1, Z2Z2, U1, U2, S1, S2, H, I, J, r, V e2
+	var Z1Z1, Z2Z2, U1, U2, S1, S2, H, I, J, r, V E2
 	Z1Z1.Square(&a.Z)
 	Z2Z2.Square(&p.Z)
 	U1.Mul(&a.X, &Z2Z2)
@@ -109,7 +112,7 @@ func (p *G2Jac) AddMixed(a *G2Affine) *G2Jac {
 	}
 
 	// get some Element from our pool
-	var Z1Z1, U2, S2, H, HH, I, J, r, V e2
+	var Z1Z1, U2, S2, H, HH, I, J, r, V E2
 	Z1Z1.Square(&p.Z)
 	U2.Mul(&a.X, &Z1Z1)
 	S2.Mul(&a.Y, &p.Z).
@@ -155,7 +158,7 @@ func (p *G2Jac) Double(q *G2Jac) *G2Jac {
 func (p *G2Jac) DoubleAssign() *G2Jac {
 
 	// get some Element from our pool
-	var XX, YY, YYYY, ZZ, S, M, T e2
+	var XX, YY, YYYY, ZZ, S, M, T E2
 
 	XX.Square(&p.X)
 	YY.Square(&p.Y)
@@ -189,6 +192,88 @@ func (p *G2Jac) ScalarMultiplication(a *G2Jac, s *big.Int) *G2Jac {
 	return p.mulGLV(a, s)
 }
 
+// ScalarMultiplicationCT computes and returns p = a*s in constant time with respect to s
+//
+// The sequence of operations and the memory access pattern don't depend on s:
+// s is converted to regular form with a fixed Montgomery reduction, split using GLV on
+// fixed-width integers (see utils.SplitScalarCT), and the resulting scalars are
+// recoded in signed odd digits (every digit is non-zero). Table lookups scan the whole table of odd
+// multiples, signs are applied with masks, and points are added and doubled in projective
+// coordinates with the complete formulas of Renes, Costello and Batina (https://eprint.iacr.org/2015/1060),
+// which have no special case for the point at infinity or equal inputs.
+//
+// The base point a is not secret (ScalarMultiplicationCT may branch on it being infinity), and the
+// timing of the field arithmetic is inherited from the fp package: constant time on amd64 (assembly
+// using conditional moves), but the generic Go code used on other platforms ends modular
+// reductions with conditional subtractions.
+func (p *G2Jac) ScalarMultiplicationCT(a *G2Jac, s *fr.Element) *G2Jac {
+	const nbScalars = 2
+	nbDigits := (glvBasis.SplitBitLen + ctWindow - 1) / ctWindow
+
+	var table [nbScalars][1 << (ctWindow - 1)]G2Proj
+	var k [nbScalars][fr.Limbs]uint64
+	var neg, even [nbScalars]uint64
+	e := frToRegularCT(s)
+	neg[0], neg[1] = utils.SplitScalarCT(k[0][:], k[1][:], e[:], &glvBasis)
+
+	// the recoding needs odd scalars: k is replaced by k+1 if even, which is corrected at the end
+	for i := 0; i < nbScalars; i++ {
+		even[i] = ^k[i][0] & 1
+		k[i][0] |= 1
+	}
+
+	// table[0] stores [a, 3a, 5a, ...], table[1] stores phi(table[0])
+	var a2 G2Proj
+	if a.Z.IsZero() {
+		table[0][0].Y.SetOne()
+	} else {
+		table[0][0].FromJacobian(a)
+	}
+	a2.doubleCT(&table[0][0])
+	for j := 1; j < len(table[0]); j++ {
+		table[0][j].addCT(&table[0][j-1], &a2)
+	}
+	for j := 0; j < len(table[1]); j++ {
+		table[1][j].phi(&table[0][j])
+	}
+
+	var res, tmp G2Proj
+
+	// most significant digits are positive and the accumulator is initialized with them
+	for i := 0; i < nbScalars; i++ {
+		idx, _ := signedDigitCT(k[i][:], nbDigits-1, true)
+		tmp.lookupCT(table[i][:], idx)
+		tmp.negCT(neg[i])
+		if i == 0 {
+			res.Set(&tmp)
+		} else {
+			res.addCT(&res, &tmp)
+		}
+	}
+
+	for d := nbDigits - 2; d >= 0; d-- {
+		for j := 0; j < ctWindow; j++ {
+			res.doubleCT(&res)
+		}
+		for i := 0; i < nbScalars; i++ {
+			idx, sign := signedDigitCT(k[i][:], d, false)
+			tmp.lookupCT(table[i][:], idx)
+			tmp.negCT(sign ^ neg[i])
+			res.addCT(&res, &tmp)
+		}
+	}
+
+	// subtract the base points for the scalars that were even
+	for i := 0; i < nbScalars; i++ {
+		tmp.Set(&table[i][0])
+		tmp.negCT(1 ^ neg[i])
+		tmp.addCT(&tmp, &res)
+		res.cmov(&tmp, even[i])
+	}
+
+	return p.fromProjCT(&res)
+}
+
 // Set set p to the provided point
 func (p *G2Jac) Set(a *G2Jac) *G2Jac {
 	p.X, p.Y, p.Z = a.X, a.Y, a.Z
@@ -241,7 +326,7 @@ func (p *G2Jac) SubAssign(a *G2Jac) *G2Jac {
 // FromJacobian rescale a point in Jacobian coord in z=1 plane
 func (p *G2Affine) FromJacobian(p1 *G2Jac) *G2Affine {
 
-	var a, b e2
+	var a, b E2
 
 	if p1.Z.IsZero() {
 		p.X.SetZero()
@@ -260,7 +345,7 @@ func (p *G2Affine) FromJacobian(p1 *G2Jac) *G2Affine {
 // FromJacobian converts a point from Jacobian to projective coordinates
 func (p *G2Proj) FromJacobian(Q *G2Jac) *G2Proj {
 	// memalloc
-	var buf e2
+	var buf E2
 	buf.Square(&Q.Z)
 
 	p.X.Mul(&Q.X, &Q.Z)
@@ -294,7 +379,7 @@ func (p *G2Jac) FromAffine(Q *G2Affine) *G2Jac {
 }
 
 func (p *G2Affine) String() string {
-	var x, y e2
+	var x, y E2
 	x.Set(&p.X)
 	y.Set(&p.Y)
 	return "E([" + x.String() + "," + y.String() + "]),"
@@ -307,7 +392,7 @@ func (p *G2Affine) IsInfinity() bool {
 
 // IsOnCurve returns true if p in on the curve
 func (p *G2Proj) IsOnCurve() bool {
-	var left, right, tmp e2
+	var left, right, tmp E2
 	left.Square(&p.Y).
 		Mul(&left, &p.Z)
 	right.Square(&p.X).
@@ -321,7 +406,7 @@ func (p *G2Proj) IsOnCurve() bool {
 
 // IsOnCurve returns true if p in on the curve
 func (p *G2Jac) IsOnCurve() bool {
-	var left, right, tmp e2
+	var left, right, tmp E2
 	left.Square(&p.Y)
 	right.Square(&p.X).Mul(&right, &p.X)
 	tmp.Square(&p.Z).
@@ -348,18 +433,15 @@ func (p *G2Affine) IsInSubGroup() bool {
 }
 
 // IsInSubGroup returns true if p is on the r-torsion, false otherwise.
-// Z[r,0]+Z[-lambdaG2, 1] is the kernel
-// of (u,v)->u+lambdaG2v mod r. Expressing r, lambdaG2 as
-// polynomials in x, a short vector of this Zmodule is
-// 1, x**2. So we check that p+x**2*phi(p)
-// is the infinity.
+// psi acts as the multiplication by p mod r = x mod r on the r-torsion, and
+// psi(p) == [x]p is a sufficient membership test
+// (cf https://eprint.iacr.org/2021/1130.pdf). It only needs one multiplication by x.
 func (p *G2Jac) IsInSubGroup() bool {
 
-	var res G2Jac
-	res.phi(p).
-		ScalarMultiplication(&res, &xGen).
-		ScalarMultiplication(&res, &xGen).
-		AddAssign(p)
+	var res, xp G2Jac
+	res.psi(p)
+	xp.mulWindowed(p, &xGen)
+	res.SubAssign(&xp)
 
 	return res.IsOnCurve() && res.Z.IsZero()
 
@@ -464,6 +546,147 @@ func (p *G2Jac) mulGLV(a *G2Jac, s *big.Int) *G2Jac {
 	return p
 }
 
+// Set sets p to the provided point
+func (p *G2Proj) Set(a *G2Proj) *G2Proj {
+	p.X, p.Y, p.Z = a.X, a.Y, a.Z
+	return p
+}
+
+// addCT sets p to a+b using the complete addition formula for a=0 curves
+// (https://eprint.iacr.org/2015/1060, algorithm 7), and returns p
+func (p *G2Proj) addCT(a, b *G2Proj) *G2Proj {
+	var t0, t1, t2, t3, t4, b3, X3, Y3, Z3 E2
+	b3.Double(&bTwistCurveCoeff).Add(&b3, &bTwistCurveCoeff)
+	t0.Mul(&a.X, &b.X)
+	t1.Mul(&a.Y, &b.Y)
+	t2.Mul(&a.Z, &b.Z)
+	t3.Add(&a.X, &a.Y)
+	t4.Add(&b.X, &b.Y)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(&a.Y, &a.Z)
+	X3.Add(&b.Y, &b.Z)
+	t4.Mul(&t4, &X3)
+	X3.Add(&t1, &t2)
+	t4.Sub(&t4, &X3)
+	X3.Add(&a.X, &a.Z)
+	Y3.Add(&b.X, &b.Z)
+	X3.Mul(&X3, &Y3)
+	Y3.Add(&t0, &t2)
+	Y3.Sub(&X3, &Y3)
+	X3.Double(&t0)
+	t0.Add(&X3, &t0)
+	t2.Mul(&b3, &t2)
+	Z3.Add(&t1, &t2)
+	t1.Sub(&t1, &t2)
+	Y3.Mul(&b3, &Y3)
+	X3.Mul(&t4, &Y3)
+	t2.Mul(&t3, &t1)
+	X3.Sub(&t2, &X3)
+	Y3.Mul(&Y3, &t0)
+	t1.Mul(&t1, &Z3)
+	Y3.Add(&t1, &Y3)
+	t0.Mul(&t0, &t3)
+	Z3.Mul(&Z3, &t4)
+	Z3.Add(&Z3, &t0)
+	p.X, p.Y, p.Z = X3, Y3, Z3
+	return p
+}
+
+// doubleCT sets p to 2a using the complete doubling formula for a=0 curves
+// (https://eprint.iacr.org/2015/1060, algorithm 9), and returns p
+func (p *G2Proj) doubleCT(a *G2Proj) *G2Proj {
+	var t0, t1, t2, b3, X3, Y3, Z3 E2
+	b3.Double(&bTwistCurveCoeff).Add(&b3, &bTwistCurveCoeff)
+	t0.Square(&a.Y)
+	Z3.Double(&t0).Double(&Z3).Double(&Z3)
+	t1.Mul(&a.Y, &a.Z)
+	t2.Square(&a.Z)
+	t2.Mul(&b3, &t2)
+	X3.Mul(&t2, &Z3)
+	Y3.Add(&t0, &t2)
+	Z3.Mul(&t1, &Z3)
+	t1.Double(&t2)
+	t2.Add(&t1, &t2)
+	t0.Sub(&t0, &t2)
+	Y3.Mul(&t0, &Y3)
+	Y3.Add(&X3, &Y3)
+	t1.Mul(&a.X, &a.Y)
+	X3.Mul(&t0, &t1)
+	X3.Double(&X3)
+	p.X, p.Y, p.Z = X3, Y3, Z3
+	return p
+}
+
+// phi assigns p to phi(a) where phi: (x,y)->(ux,y), and returns p
+func (p *G2Proj) phi(a *G2Proj) *G2Proj {
+	p.Set(a)
+	p.X.MulByElement(&p.X, &thirdRootOneG2)
+	return p
+}
+
+// fromProjCT sets p to a in Jacobian coordinates, (X*Z, Y*Z**2, Z), and returns p
+// the point at infinity (0:1:0) is mapped to (1,1,0) with a conditional move
+func (p *G2Jac) fromProjCT(a *G2Proj) *G2Jac {
+	var zz E2
+	zz.Square(&a.Z)
+	p.X.Mul(&a.X, &a.Z)
+	p.Y.Mul(&a.Y, &zz)
+	p.Z = a.Z
+
+	var inf G2Jac
+	inf.X.SetOne()
+	inf.Y.SetOne()
+	var acc uint64
+	for i := 0; i < fp.Limbs; i++ {
+		acc |= a.Z.A0[i] | a.Z.A1[i]
+	}
+	return p.cmov(&inf, ctEq(acc, 0))
+}
+
+// cmov sets p to a if c == 1 and leaves it unchanged if c == 0, in constant time
+func (p *G2Jac) cmov(a *G2Jac, c uint64) *G2Jac {
+	fpCmov(&p.X.A0, &a.X.A0, c)
+	fpCmov(&p.X.A1, &a.X.A1, c)
+	fpCmov(&p.Y.A0, &a.Y.A0, c)
+	fpCmov(&p.Y.A1, &a.Y.A1, c)
+	fpCmov(&p.Z.A0, &a.Z.A0, c)
+	fpCmov(&p.Z.A1, &a.Z.A1, c)
+	return p
+}
+
+// cmov sets p to a if c == 1 and leaves it unchanged if c == 0, in constant time
+func (p *G2Proj) cmov(a *G2Proj, c uint64) *G2Proj {
+	fpCmov(&p.X.A0, &a.X.A0, c)
+	fpCmov(&p.X.A1, &a.X.A1, c)
+	fpCmov(&p.Y.A0, &a.Y.A0, c)
+	fpCmov(&p.Y.A1, &a.Y.A1, c)
+	fpCmov(&p.Z.A0, &a.Z.A0, c)
+	fpCmov(&p.Z.A1, &a.Z.A1, c)
+	return p
+}
+
+// negCT sets p to -p if c == 1 and leaves it unchanged if c == 0, in constant time
+func (p *G2Proj) negCT(c uint64) *G2Proj {
+	// Neg branches on zero inputs, Sub doesn't
+	var n G2Proj
+	var zero E2
+	n.Set(p)
+	n.Y.Sub(&zero, &p.Y)
+	return p.cmov(&n, c)
+}
+
+// lookupCT sets p to table[idx], reading every entry of the table so that
+// the memory access pattern doesn't depend on idx
+func (p *G2Proj) lookupCT(table []G2Proj, idx uint64) *G2Proj {
+	p.Set(&table[0])
+	for i := 1; i < len(table); i++ {
+		p.cmov(&table[i], ctEq(uint64(i), idx))
+	}
+	return p
+}
+
 // BatchScalarMultiplicationG2 multiplies the same base (generator) by all scalars
 // and return resulting points in affine coordinates
 // uses a simple windowed-NAF like exponentiation algorithm
@@ -575,3 +798,153 @@ func BatchScalarMultiplicationG2(base *G2Affine, scalars []fr.Element) []G2Affin
 	return toReturn
 
 }
+
+// FixedBaseTableG2 stores precomputed multiples of a fixed base point for scalar multiplications
+// using signed windows of fixedBaseWindow bits: table[i][d-1] = d * 2^(fixedBaseWindow*i) * base, 1 <= d <= 2^(fixedBaseWindow-1)
+//
+// A scalar multiplication is then nbChunks mixed additions, without doublings.
+// The table is read only once built and can be shared between goroutines.
+type FixedBaseTableG2 struct {
+	table [][1 << (fixedBaseWindow - 1)]G2Affine
+}
+
+// NewFixedBaseTableG2 builds the fixed base table of base
+func NewFixedBaseTableG2(base *G2Affine) *FixedBaseTableG2 {
+	const nbChunks = fr.Limbs * 64 / fixedBaseWindow
+	t := &FixedBaseTableG2{
+		table: make([][1 << (fixedBaseWindow - 1)]G2Affine, nbChunks),
+	}
+
+	if base.IsInfinity() {
+		// all entries are the infinity symbol (0, 0)
+		return t
+	}
+
+	// bases[i] = 2^(fixedBaseWindow*i) * base
+	bases := make([]G2Jac, nbChunks)
+	bases[0].FromAffine(base)
+	for i := 1; i < nbChunks; i++ {
+		bases[i].Set(&bases[i-1])
+		for j := 0; j < fixedBaseWindow; j++ {
+			bases[i].DoubleAssign()
+		}
+	}
+
+	parallel.Execute(nbChunks, func(start, end int) {
+		var multiples [1 << (fixedBaseWindow - 1)]G2Jac
+		for i := start; i < end; i++ {
+			multiples[0].Set(&bases[i])
+			for j := 1; j < len(multiples); j++ {
+				multiples[j].Set(&multiples[j-1]).AddAssign(&bases[i])
+			}
+			for j := 0; j < len(multiples); j++ {
+				t.table[i][j].FromJacobian(&multiples[j])
+			}
+		}
+	})
+
+	return t
+}
+
+// Mul computes and returns s * base
+func (t *FixedBaseTableG2) Mul(s *big.Int) G2Jac {
+	var e big.Int
+	var k fr.Element
+	e.Mod(s, fr.Modulus())
+	k.SetBigInt(&e).FromMont()
+
+	var res G2Jac
+	digits := partitionScalars([]fr.Element{k}, fixedBaseWindow)
+	t.mul(&res, &digits[0])
+	return res
+}
+
+// MulBatch multiplies the base by all scalars (NOT in Montgomery form)
+// and returns resulting points in affine coordinates
+func (t *FixedBaseTableG2) MulBatch(scalars []fr.Element) []G2Affine {
+	digits := partitionScalars(scalars, fixedBaseWindow)
+	toReturn := make([]G2Affine, len(scalars))
+
+	parallel.Execute(len(digits), func(start, end int) {
+		var p G2Jac
+		for i := start; i < end; i++ {
+			t.mul(&p, &digits[i])
+			toReturn[i].FromJacobian(&p)
+		}
+	})
+	return toReturn
+}
+
+// mul sets p to the sum of the table entries selected by the signed digits
+// (as output by partitionScalars) and returns p
+func (t *FixedBaseTableG2) mul(p *G2Jac, digits *fr.Element) *G2Jac {
+	const mask = uint64((1 << fixedBaseWindow) - 1)
+	const msbWindow = uint64(1 << (fixedBaseWindow - 1))
+
+	p.Set(&g2Infinity)
+	for chunk := 0; chunk < len(t.table); chunk++ {
+		jc := chunk * fixedBaseWindow
+		bits := (digits[jc/64] >> uint(jc%64)) & mask
+
+		if bits == 0 {
+			continue
+		}
+
+		// if msbWindow bit is set, we need to substract
+		if bits&msbWindow == 0 {
+			p.AddMixed(&t.table[chunk][bits-1])
+		} else {
+			q := t.table[chunk][bits & ^msbWindow]
+			q.Neg(&q)
+			p.AddMixed(&q)
+		}
+	}
+	return p
+}
+
+var g2GenTable *FixedBaseTableG2
+var g2GenTableOnce sync.Once
+
+// G2GeneratorTable returns the fixed base table of the G2 generator, built on first call
+func G2GeneratorTable() *FixedBaseTableG2 {
+	g2GenTableOnce.Do(func() {
+		g2GenTable = NewFixedBaseTableG2(&g2GenAff)
+	})
+	return g2GenTable
+}
+
+// RandomG2 returns a uniformly random point in the r-torsion of G2, using the randomness read from r
+//
+// x is sampled until x**3+b is a square, y is one of its square roots selected with a random bit
+// and the cofactor of (x, y) is cleared. Using a seeded r makes the output reproducible.
+func RandomG2(r io.Reader) (G2Affine, error) {
+	var res G2Affine
+	var x, y E2
+	var sign [1]byte
+
+	for {
+		if _, err := x.SetRandomFrom(r); err != nil {
+			return res, err
+		}
+		y.Square(&x).Mul(&y, &x).Add(&y, &bTwistCurveCoeff)
+		if y.Legendre() == 1 {
+			break
+		}
+	}
+	y.Sqrt(&y)
+	if _, err := io.ReadFull(r, sign[:]); err != nil {
+		return res, err
+	}
+	// the output of Sqrt is not canonical, the random bit selects the lexicographically largest root or not
+	largest := y.lexicographicallyLargest()
+	if largest != (sign[0]&1 == 1) {
+		y.Neg(&y)
+	}
+	var p G2Jac
+	p.X, p.Y = x, y
+	p.Z.SetOne()
+	p.ClearCofactor(&p)
+	res.FromJacobian(&p)
+
+	return res, nil
+}
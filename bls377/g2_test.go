@@ -17,20 +17,26 @@
 package bls377
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"math/big"
 	"math/bits"
+	"math/rand"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/consensys/gurvy/bls377/fr"
 	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
 )
 
 // ------------------------------------------------------------
 // utils
-func fuzzJacobianG2(p *G2Jac, f *e2) G2Jac {
+func fuzzJacobianG2(p *G2Jac, f *E2) G2Jac {
 	var res G2Jac
 	res.X.Mul(&p.X, f).Mul(&res.X, f)
 	res.Y.Mul(&p.Y, f).Mul(&res.Y, f).Mul(&res.Y, f)
@@ -38,7 +44,7 @@ func fuzzJacobianG2(p *G2Jac, f *e2) G2Jac {
 	return res
 }
 
-func fuzzProjectiveG2(p *G2Proj, f *e2) G2Proj {
+func fuzzProjectiveG2(p *G2Proj, f *E2) G2Proj {
 	var res G2Proj
 	res.X.Mul(&p.X, f)
 	res.Y.Mul(&p.Y, f)
@@ -46,9 +52,9 @@ func fuzzProjectiveG2(p *G2Proj, f *e2) G2Proj {
 	return res
 }
 
-func fuzzExtendedJacobianG2(p *g2JacExtended, f *e2) g2JacExtended {
+func fuzzExtendedJacobianG2(p *g2JacExtended, f *E2) g2JacExtended {
 	var res g2JacExtended
-	var ff, fff e2
+	var ff, fff E2
 	ff.Square(f)
 	fff.Mul(&ff, f)
 	res.X.Mul(&p.X, &ff)
@@ -69,7 +75,7 @@ func TestG2IsOnCurve(t *testing.T) {
 	properties := gopter.NewProperties(parameters)
 	genFuzz1 := GenE2()
 	properties.Property("[BLS377] g2Gen (affine) should be on the curve", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			var op1, op2 G2Affine
 			op1.FromJacobian(&g2Gen)
 			op2.FromJacobian(&g2Gen)
@@ -80,7 +86,7 @@ func TestG2IsOnCurve(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] g2Gen (Jacobian) should be on the curve", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			var op1, op2, op3 G2Jac
 			op1.Set(&g2Gen)
 			op3.Set(&g2Gen)
@@ -93,7 +99,7 @@ func TestG2IsOnCurve(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] g2Gen (projective) should be on the curve", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			var op1, op2, op3 G2Proj
 			op1.FromJacobian(&g2Gen)
 			op2.FromJacobian(&g2Gen)
@@ -119,7 +125,7 @@ func TestG2Conversions(t *testing.T) {
 	genFuzz2 := GenE2()
 
 	properties.Property("[BLS377] Affine representation should be independent of the Jacobian representative", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			g := fuzzJacobianG2(&g2Gen, a)
 			var op1 G2Affine
 			op1.FromJacobian(&g)
@@ -129,7 +135,7 @@ func TestG2Conversions(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] Affine representation should be independent of a Extended Jacobian representative", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			var g g2JacExtended
 			g.X.Set(&g2Gen.X)
 			g.Y.Set(&g2Gen.Y)
@@ -145,13 +151,13 @@ func TestG2Conversions(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] Projective representation should be independent of a Jacobian representative", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 
 			g := fuzzJacobianG2(&g2Gen, a)
 
 			var op1 G2Proj
 			op1.FromJacobian(&g)
-			var u, v e2
+			var u, v E2
 			u.Mul(&g.X, &g.Z)
 			v.Square(&g.Z).Mul(&v, &g.Z)
 
@@ -161,13 +167,13 @@ func TestG2Conversions(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] Jacobian representation should be the same as the affine representative", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			var g G2Jac
 			var op1 G2Affine
 			op1.X.Set(&g2Gen.X)
 			op1.Y.Set(&g2Gen.Y)
 
-			var one e2
+			var one E2
 			one.SetOne()
 
 			g.FromAffine(&op1)
@@ -184,7 +190,7 @@ func TestG2Conversions(t *testing.T) {
 			g.Y.SetZero()
 			var op1 G2Jac
 			op1.FromAffine(&g)
-			var one, zero e2
+			var one, zero E2
 			one.SetOne()
 			return op1.X.Equal(&one) && op1.Y.Equal(&one) && op1.Z.Equal(&zero)
 		},
@@ -194,7 +200,7 @@ func TestG2Conversions(t *testing.T) {
 		func() bool {
 			var g G2Affine
 			var op1 g2JacExtended
-			var zero e2
+			var zero E2
 			op1.X.Set(&g2Gen.X)
 			op1.Y.Set(&g2Gen.Y)
 			g.fromJacExtended(&op1)
@@ -206,7 +212,7 @@ func TestG2Conversions(t *testing.T) {
 		func() bool {
 			var g G2Jac
 			var op1 g2JacExtended
-			var zero, one e2
+			var zero, one E2
 			one.SetOne()
 			op1.X.Set(&g2Gen.X)
 			op1.Y.Set(&g2Gen.Y)
@@ -216,7 +222,7 @@ func TestG2Conversions(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] [Jacobian] Two representatives of the same class should be equal", prop.ForAll(
-		func(a, b *e2) bool {
+		func(a, b *E2) bool {
 			op1 := fuzzJacobianG2(&g2Gen, a)
 			op2 := fuzzJacobianG2(&g2Gen, b)
 			return op1.Equal(&op2)
@@ -240,7 +246,7 @@ func TestG2Ops(t *testing.T) {
 	genScalar := GenFr()
 
 	properties.Property("[BLS377] [Jacobian] Add should call double when having adding the same point", prop.ForAll(
-		func(a, b *e2) bool {
+		func(a, b *E2) bool {
 			fop1 := fuzzJacobianG2(&g2Gen, a)
 			fop2 := fuzzJacobianG2(&g2Gen, b)
 			var op1, op2 G2Jac
@@ -253,7 +259,7 @@ func TestG2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] [Jacobian] Adding the opposite of a point to itself should output inf", prop.ForAll(
-		func(a, b *e2) bool {
+		func(a, b *E2) bool {
 			fop1 := fuzzJacobianG2(&g2Gen, a)
 			fop2 := fuzzJacobianG2(&g2Gen, b)
 			fop2.Neg(&fop2)
@@ -265,7 +271,7 @@ func TestG2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] [Jacobian] Adding the inf to a point should not modify the point", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			fop1 := fuzzJacobianG2(&g2Gen, a)
 			fop1.AddAssign(&g2Infinity)
 			var op2 G2Jac
@@ -277,7 +283,7 @@ func TestG2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] [Jacobian Extended] mAdd (-G) should equal mSub(G)", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			fop1 := fuzzJacobianG2(&g2Gen, a)
 			var p1, p1Neg G2Affine
 			p1.FromJacobian(&fop1)
@@ -296,7 +302,7 @@ func TestG2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] [Jacobian Extended] double (-G) should equal doubleNeg(G)", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			fop1 := fuzzJacobianG2(&g2Gen, a)
 			var p1, p1Neg G2Affine
 			p1.FromJacobian(&fop1)
@@ -315,7 +321,7 @@ func TestG2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS377] [Jacobian] Addmix the negation to itself should output 0", prop.ForAll(
-		func(a *e2) bool {
+		func(a *E2) bool {
 			fop1 := fuzzJacobianG2(&g2Gen, a)
 			fop1.Neg(&fop1)
 			var op2 G2Affine
@@ -394,6 +400,52 @@ func TestG2Ops(t *testing.T) {
 		genScalar,
 	))
 
+	properties.Property("[BLS377] constant time scalar multiplication should output the same result as ScalarMultiplication", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var r big.Int
+			var sNeg fr.Element
+			var op1, op2, op3, op4 G2Jac
+			s.ToBigIntRegular(&r)
+			op1.ScalarMultiplication(&g2Gen, &r)
+			op2.ScalarMultiplicationCT(&g2Gen, &s)
+			sNeg.Neg(&s)
+			op3.Neg(&op1)
+			op4.ScalarMultiplicationCT(&g2Gen, &sNeg)
+			return op1.Equal(&op2) && op3.Equal(&op4)
+
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS377] constant time scalar multiplication should handle small scalars, r-1 and the point at infinity", prop.ForAll(
+		func() bool {
+
+			var s fr.Element
+			var r big.Int
+			var op1, op2 G2Jac
+			for _, v := range []uint64{0, 1, 2, 3, 16, 17} {
+				s.SetUint64(v)
+				r.SetUint64(v)
+				op1.ScalarMultiplication(&g2Gen, &r)
+				op2.ScalarMultiplicationCT(&g2Gen, &s)
+				if !op1.Equal(&op2) {
+					return false
+				}
+			}
+			s.SetOne().Neg(&s)
+			op1.Neg(&g2Gen)
+			op2.ScalarMultiplicationCT(&g2Gen, &s)
+			if !op1.Equal(&op2) {
+				return false
+			}
+			s.SetUint64(5)
+			op2.ScalarMultiplicationCT(&g2Infinity, &s)
+			return op2.Z.IsZero()
+
+		},
+	))
+
 	// note : this test is here as we expect to have a different multiExp than the above bucket method
 	// for small number of points
 	properties.Property("[BLS377] Multi exponentation (<50points) should be consistant with sum of square", prop.ForAll(
@@ -460,6 +512,42 @@ func TestG2MultiExp(t *testing.T) {
 	scalar.Mul(&scalar, new(big.Int).SetInt64(2*nbSamples+1))
 	scalar.Div(&scalar, new(big.Int).SetInt64(6))
 
+	properties.Property("[BLS377] Multi exponentation with batch affine buckets should handle repeated, opposite and infinity points", prop.ForAll(
+		func(mixer fr.Element) bool {
+
+			// few distinct points, so that buckets receive equal and opposite points
+			const nbPoints = 1000
+			points := make([]G2Affine, nbPoints)
+			scalars := make([]fr.Element, nbPoints)
+			for i := 0; i < nbPoints; i++ {
+				switch i % 4 {
+				case 0:
+					points[i] = samplePoints[0]
+				case 1:
+					points[i].Neg(&samplePoints[0])
+				case 2:
+					points[i] = samplePoints[i%7]
+				case 3:
+					// infinity
+				}
+				scalars[i].SetUint64(uint64(i % 13)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			var result, expected G2Jac
+			opt := NewMultiExpOptions(runtime.NumCPU())
+			scalars = partitionScalars(scalars, 16)
+			opt.lock.Lock()
+			result.msmC16(points, scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
+			opt.lock.Lock()
+			expected.msmC16(points, scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			return result.Equal(&expected)
+		},
+		genScalar,
+	))
+
 	properties.Property("[BLS377] Multi exponentation (c=4) should be consistant with sum of square", prop.ForAll(
 		func(mixer fr.Element) bool {
 
@@ -478,14 +566,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 4)
-			result.msmC4(samplePoints[:], scalars, opt)
+			result.msmC4(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC4(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -508,14 +600,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 5)
-			result.msmC5(samplePoints[:], scalars, opt)
+			result.msmC5(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC5(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -538,14 +634,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 6)
-			result.msmC6(samplePoints[:], scalars, opt)
+			result.msmC6(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC6(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -568,14 +668,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 7)
-			result.msmC7(samplePoints[:], scalars, opt)
+			result.msmC7(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC7(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -598,14 +702,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 8)
-			result.msmC8(samplePoints[:], scalars, opt)
+			result.msmC8(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC8(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -628,14 +736,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 9)
-			result.msmC9(samplePoints[:], scalars, opt)
+			result.msmC9(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC9(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -658,14 +770,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 10)
-			result.msmC10(samplePoints[:], scalars, opt)
+			result.msmC10(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC10(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -688,14 +804,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 11)
-			result.msmC11(samplePoints[:], scalars, opt)
+			result.msmC11(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC11(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -718,14 +838,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 12)
-			result.msmC12(samplePoints[:], scalars, opt)
+			result.msmC12(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC12(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -748,14 +872,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 13)
-			result.msmC13(samplePoints[:], scalars, opt)
+			result.msmC13(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC13(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -778,14 +906,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 14)
-			result.msmC14(samplePoints[:], scalars, opt)
+			result.msmC14(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC14(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -808,14 +940,18 @@ func TestG2MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 15)
-			result.msmC15(samplePoints[:], scalars, opt)
+			result.msmC15(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G2Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC15(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -840,14 +976,18 @@ func TestG2MultiExp(t *testing.T) {
 				opt := NewMultiExpOptions(runtime.NumCPU())
 				opt.lock.Lock()
 				scalars := partitionScalars(sampleScalars[:], 16)
-				result.msmC16(samplePoints[:], scalars, opt)
+				result.msmC16(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+				var resultBatchAffine G2Jac
+				opt.lock.Lock()
+				resultBatchAffine.msmC16(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
 				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 				expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-				return result.Equal(&expected)
+				return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 			},
 			genScalar,
 		))
@@ -874,14 +1014,18 @@ func TestG2MultiExp(t *testing.T) {
 				opt := NewMultiExpOptions(runtime.NumCPU())
 				opt.lock.Lock()
 				scalars := partitionScalars(sampleScalars[:], 20)
-				result.msmC20(samplePoints[:], scalars, opt)
+				result.msmC20(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+				var resultBatchAffine G2Jac
+				opt.lock.Lock()
+				resultBatchAffine.msmC20(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
 				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 				expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-				return result.Equal(&expected)
+				return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 			},
 			genScalar,
 		))
@@ -908,14 +1052,18 @@ func TestG2MultiExp(t *testing.T) {
 				opt := NewMultiExpOptions(runtime.NumCPU())
 				opt.lock.Lock()
 				scalars := partitionScalars(sampleScalars[:], 21)
-				result.msmC21(samplePoints[:], scalars, opt)
+				result.msmC21(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+				var resultBatchAffine G2Jac
+				opt.lock.Lock()
+				resultBatchAffine.msmC21(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
 				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 				expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-				return result.Equal(&expected)
+				return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 			},
 			genScalar,
 		))
@@ -942,14 +1090,18 @@ func TestG2MultiExp(t *testing.T) {
 				opt := NewMultiExpOptions(runtime.NumCPU())
 				opt.lock.Lock()
 				scalars := partitionScalars(sampleScalars[:], 22)
-				result.msmC22(samplePoints[:], scalars, opt)
+				result.msmC22(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+				var resultBatchAffine G2Jac
+				opt.lock.Lock()
+				resultBatchAffine.msmC22(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
 				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 				expected.ScalarMultiplication(&g2Gen, &finalScalar)
 
-				return result.Equal(&expected)
+				return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 			},
 			genScalar,
 		))
@@ -959,171 +1111,936 @@ func TestG2MultiExp(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
-func TestG2CofactorCleaning(t *testing.T) {
+func TestG2MultiExpSmallScalars(t *testing.T) {
 
 	parameters := gopter.DefaultTestParameters()
-	parameters.MinSuccessfulTests = 10
+	parameters.MinSuccessfulTests = 5
 
 	properties := gopter.NewProperties(parameters)
 
-	properties.Property("[BLS377] Clearing the cofactor of a random point should set it in the r-torsion", prop.ForAll(
-		func() bool {
-			var a, x, b e2
-			a.SetRandom()
+	// enough points for the batch affine sums of msmTernary
+	const nbSamples = batchAffineMinPoints + 100
+
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		// repeated points, so that the accumulators see doublings and opposite points
+		if i%100 == 0 {
+			g.Set(&g2Gen)
+		}
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne).FromMont()
+
+	properties.Property("[BLS377] Multi exponentation with scalars in {-1, 0, 1} should output the sum of the points", prop.ForAll(
+		func(seed int64, signed bool) bool {
+			rnd := rand.New(rand.NewSource(seed))
+			scalars := make([]fr.Element, nbSamples)
+			var expected G2Jac
+			expected.Set(&g2Infinity)
+			for i := 0; i < nbSamples; i++ {
+				var q G2Jac
+				q.FromAffine(&samplePoints[i])
+				switch rnd.Intn(3) {
+				case 1:
+					scalars[i].SetOne().FromMont()
+					expected.AddAssign(&q)
+				case 2:
+					if signed {
+						scalars[i] = minusOne
+						expected.SubAssign(&q)
+					}
+				}
+			}
+			if nbBits, ternary := scalarsBitLen(scalars); !ternary || (!signed && nbBits > 1) {
+				return false
+			}
+
+			var result, resultSmall G2Jac
+			result.MultiExp(samplePoints, scalars)
+			resultSmall.MultiExp(samplePoints[:1000], scalars[:1000])
 
-			x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
-			for x.Legendre() != 1 {
-				a.SetRandom()
-				x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
+			expectedSmall := msmNaiveG2(samplePoints[:1000], scalars[:1000])
+			return result.Equal(&expected) && resultSmall.Equal(&expectedSmall)
+		},
+		gen.Int64(),
+		gen.Bool(),
+	))
+
+	properties.Property("[BLS377] Multi exponentation with 32-bit scalars should output the naive multi exponentiation", prop.ForAll(
+		func(seed int64) bool {
+			const nbPoints = 200
+			rnd := rand.New(rand.NewSource(seed))
+			scalars := make([]fr.Element, nbPoints)
+			for i := 0; i < nbPoints; i++ {
+				scalars[i].SetUint64(uint64(rnd.Uint32())).FromMont()
+			}
+			if nbBits, ternary := scalarsBitLen(scalars); ternary || nbBits > 32 {
+				return false
 			}
 
-			b.Sqrt(&x)
-			var point, pointCleared, infinity G2Jac
-			point.X.Set(&a)
-			point.Y.Set(&b)
-			point.Z.SetOne()
-			pointCleared.ClearCofactor(&point)
-			infinity.Set(&g2Infinity)
-			return point.IsOnCurve() && pointCleared.IsInSubGroup() && !pointCleared.Equal(&infinity)
+			var result G2Jac
+			result.MultiExp(samplePoints[:nbPoints], scalars)
+			expected := msmNaiveG2(samplePoints[:nbPoints], scalars)
+			return result.Equal(&expected)
 		},
+		gen.Int64(),
 	))
+
+	properties.Property("[BLS377] scalarsBitLen should not consider -1 as a small scalar with larger scalars", prop.ForAll(
+		func(a uint64) bool {
+			scalars := []fr.Element{minusOne, {}}
+			scalars[1].SetUint64(a | 2).FromMont()
+			nbBits, ternary := scalarsBitLen(scalars)
+			return !ternary && nbBits == fr.Bits
+		},
+		gen.UInt64(),
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
 
+func TestG2MultiExpBestC(t *testing.T) {
+	// small scalars need less windows, which makes larger windows cheaper
+	const nbPoints = 1 << 16
+	small, large := msmBestCG2(nbPoints, 8), msmBestCG2(nbPoints, fr.Bits)
+	if small >= large {
+		t.Fatalf("8-bit scalars should use smaller windows than %d-bit scalars, got c=%d and c=%d", fr.Bits, small, large)
+	}
+	if c := msmBestCG2(1<<24, fr.Bits); c < large {
+		t.Fatal("more points should not use smaller windows")
+	}
 }
 
-func TestG2BatchScalarMultiplication(t *testing.T) {
+// msmNaiveG2 returns Σ scalars[i]·points[i] with one scalar multiplication per point
+func msmNaiveG2(points []G2Affine, scalars []fr.Element) G2Jac {
+	var res, q G2Jac
+	res.Set(&g2Infinity)
+	for i := 0; i < len(scalars); i++ {
+		var s big.Int
+		scalars[i].ToBigInt(&s)
+		q.ScalarMultiplication(new(G2Jac).FromAffine(&points[i]), &s)
+		res.AddAssign(&q)
+	}
+	return res
+}
+
+func TestG2MultiExpBatchAffineSkewed(t *testing.T) {
+	// most of the points target the same few buckets: the conflicts must neither be requeued
+	// indefinitely nor make the batch affine accumulation slower than the jacobian one
+	const nbPoints = batchAffineMinPoints
+	points := make([]G2Affine, nbPoints)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbPoints; i++ {
+		points[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	var mixer fr.Element
+	mixer.SetRandom()
+	constant := make([]fr.Element, nbPoints)
+	repeated := make([]fr.Element, nbPoints)
+	for i := 0; i < nbPoints; i++ {
+		constant[i] = mixer
+		repeated[i].SetUint64(uint64(i%3+1)).Mul(&repeated[i], &mixer)
+	}
+
+	for name, scalars := range map[string][]fr.Element{"constant": constant, "repeated": repeated} {
+		for i := 0; i < nbPoints; i++ {
+			scalars[i].FromMont()
+		}
+		scalars = partitionScalars(scalars, 16)
+		opt := NewMultiExpOptions(runtime.NumCPU())
+
+		var result, expected G2Jac
+		start := time.Now()
+		opt.lock.Lock()
+		expected.msmC16(points, scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+		tJac := time.Since(start)
+
+		start = time.Now()
+		opt.lock.Lock()
+		result.msmC16(points, scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
+		tBatchAffine := time.Since(start)
+
+		if !result.Equal(&expected) {
+			t.Fatalf("%s scalars: batch affine and jacobian multi exponentiations differ", name)
+		}
+		if tBatchAffine > 10*tJac {
+			t.Fatalf("%s scalars: batch affine multi exponentiation took %s, jacobian %s", name, tBatchAffine, tJac)
+		}
+	}
+}
+
+func TestMultiExpManyG2(t *testing.T) {
 
 	parameters := gopter.DefaultTestParameters()
-	parameters.MinSuccessfulTests = 10
+	parameters.MinSuccessfulTests = 5
 
 	properties := gopter.NewProperties(parameters)
 
-	genScalar := GenFr()
-
-	// size of the multiExps
 	const nbSamples = 500
 
-	properties.Property("[BLS377] BatchScalarMultiplication should be consistant with individual scalar multiplications", prop.ForAll(
-		func(mixer fr.Element) bool {
-			// mixer ensures that all the words of a fpElement are set
-			var sampleScalars [nbSamples]fr.Element
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
 
-			for i := 1; i <= nbSamples; i++ {
-				sampleScalars[i-1].SetUint64(uint64(i)).
-					MulAssign(&mixer).
-					FromMont()
+	properties.Property("[BLS377] MultiExpMany should output the same results as MultiExp on each vector", prop.ForAll(
+		func(seed int64) bool {
+			rnd := rand.New(rand.NewSource(seed))
+			scalars := make([][]fr.Element, 4)
+			for v := 0; v < len(scalars); v++ {
+				scalars[v] = make([]fr.Element, nbSamples)
+				for i := 0; i < nbSamples; i++ {
+					switch v {
+					case 0:
+						scalars[v][i].SetRandom().FromMont()
+					case 1:
+						// small scalars
+						scalars[v][i].SetUint64(uint64(rnd.Uint32())).FromMont()
+					case 2:
+						// zeros
+					case 3:
+						scalars[v][i].SetUint64(uint64(i)).Neg(&scalars[v][i]).FromMont()
+					}
+				}
 			}
 
-			result := BatchScalarMultiplicationG2(&g2GenAff, sampleScalars[:])
-
-			if len(result) != len(sampleScalars) {
+			results, err := MultiExpManyG2(samplePoints, scalars, NewMultiExpOptions(runtime.NumCPU()))
+			if err != nil || len(results) != len(scalars) {
 				return false
 			}
-
-			for i := 0; i < len(result); i++ {
-				var expectedJac G2Jac
-				var expected G2Affine
-				var b big.Int
-				expectedJac.mulGLV(&g2Gen, sampleScalars[i].ToBigInt(&b))
-				expected.FromJacobian(&expectedJac)
-				if !result[i].Equal(&expected) {
+			for v := 0; v < len(scalars); v++ {
+				var expected G2Jac
+				expected.MultiExp(samplePoints, scalars[v])
+				if !results[v].Equal(&expected) {
 					return false
 				}
 			}
 			return true
 		},
-		genScalar,
+		gen.Int64(),
 	))
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
-}
-
-// ------------------------------------------------------------
-// benches
-
-func BenchmarkG2BatchScalarMul(b *testing.B) {
-	// ensure every words of the scalars are filled
-	var mixer fr.Element
-	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
-
-	const pow = 15
-	const nbSamples = 1 << pow
-
-	var sampleScalars [nbSamples]fr.Element
 
-	for i := 1; i <= nbSamples; i++ {
-		sampleScalars[i-1].SetUint64(uint64(i)).
-			Mul(&sampleScalars[i-1], &mixer).
-			FromMont()
+	if results, err := MultiExpManyG2(samplePoints, nil); err != nil || len(results) != 0 {
+		t.Fatal("MultiExpMany with no vector should output no result")
 	}
 
-	for i := 5; i <= pow; i++ {
-		using := 1 << i
+	// ternary vectors with -1
+	var minusOne, one fr.Element
+	minusOne.SetOne().Neg(&minusOne).FromMont()
+	one.SetOne().FromMont()
+	ternary := [][]fr.Element{
+		{minusOne, one, one},
+		{one, minusOne, minusOne},
+		{one, one, one},
+	}
+	results, err := MultiExpManyG2(samplePoints[:3], ternary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 0; v < len(ternary); v++ {
+		var expected G2Jac
+		expected.MultiExp(samplePoints[:3], ternary[v])
+		if !results[v].Equal(&expected) {
+			t.Fatal("MultiExpMany should output the same results as MultiExp on scalars in {-1, 0, 1}")
+		}
+	}
 
-		b.Run(fmt.Sprintf("%d points", using), func(b *testing.B) {
-			b.ResetTimer()
-			for j := 0; j < b.N; j++ {
-				_ = BatchScalarMultiplicationG2(&g2GenAff, sampleScalars[:using])
-			}
-		})
+	scalars := [][]fr.Element{make([]fr.Element, nbSamples), make([]fr.Element, nbSamples-1)}
+	if _, err := MultiExpManyG2(samplePoints, scalars); err == nil {
+		t.Fatal("MultiExpMany should fail when a vector doesn't have one scalar per point")
 	}
 }
 
-func BenchmarkG2ScalarMul(b *testing.B) {
-
-	var scalar big.Int
-	r := fr.Modulus()
-	scalar.SetString("5243587517512619047944770508185965837690552500527637822603658699938581184513", 10)
-	scalar.Add(&scalar, r)
+func TestG2MultiExpReaderAt(t *testing.T) {
 
-	var doubleAndAdd G2Jac
+	const nbSamples = 500
 
-	b.Run("double and add", func(b *testing.B) {
-		b.ResetTimer()
-		for j := 0; j < b.N; j++ {
-			doubleAndAdd.ScalarMultiplication(&g2Gen, &scalar)
+	samplePoints := make([]G2Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		if i%50 != 7 {
+			// and a few points at infinity
+			samplePoints[i].FromJacobian(&g)
 		}
-	})
+		g.AddAssign(&g2Gen)
+		sampleScalars[i].SetRandom().FromMont()
+	}
 
-	var glv G2Jac
-	b.Run("GLV", func(b *testing.B) {
-		b.ResetTimer()
-		for j := 0; j < b.N; j++ {
-			glv.mulGLV(&g2Gen, &scalar)
+	var expected G2Jac
+	expected.MultiExp(samplePoints, sampleScalars)
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, RawEncoding())
+	if err := enc.Encode(samplePoints); err != nil {
+		t.Fatal(err)
+	}
+	// skip the length prefix of the slice
+	r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 4, int64(buf.Len()-4))
+
+	const memoryPerPoint = 3*SizeOfG2AffineUncompressed + SizeOfFr
+	for _, segmentSize := range []int{1, 77, 250, nbSamples, 2 * nbSamples} {
+		var result G2Jac
+		if _, err := result.MultiExpReaderAt(context.Background(), r, sampleScalars, segmentSize*memoryPerPoint); err != nil {
+			t.Fatal(err)
 		}
-	})
+		if !result.Equal(&expected) {
+			t.Fatalf("MultiExpReaderAt with segments of %d points and MultiExp results differ", segmentSize)
+		}
+	}
 
-}
+	var result G2Jac
+	if _, err := result.MultiExpReaderAt(context.Background(), r, sampleScalars, memoryPerPoint-1); err == nil {
+		t.Fatal("MultiExpReaderAt should fail when a point doesn't fit in the memory budget")
+	}
 
-func BenchmarkG2CofactorClearing(b *testing.B) {
-	var a G2Jac
-	a.Set(&g2Gen)
-	for i := 0; i < b.N; i++ {
-		a.ClearCofactor(&a)
+	truncated := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 4, int64(buf.Len()-5))
+	if _, err := result.MultiExpReaderAt(context.Background(), truncated, sampleScalars, 77*memoryPerPoint); err == nil {
+		t.Fatal("MultiExpReaderAt should fail on a truncated input")
 	}
-}
 
-func BenchmarkG2Add(b *testing.B) {
-	var a G2Jac
-	a.Double(&g2Gen)
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		a.AddAssign(&g2Gen)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := result.MultiExpReaderAt(ctx, r, sampleScalars, 77*memoryPerPoint); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
 	}
 }
 
-func BenchmarkG2mAdd(b *testing.B) {
-	var a g2JacExtended
-	a.double(&g2GenAff)
+func TestG2MultiExpContext(t *testing.T) {
 
-	var c G2Affine
-	c.FromJacobian(&g2Gen)
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		a.mAdd(&c)
+	const nbSamples = 500
+
+	var samplePoints [nbSamples]G2Affine
+	var sampleScalars [nbSamples]fr.Element
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 1; i <= nbSamples; i++ {
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			MulAssign(&mixer).
+			FromMont()
 	}
 
-}
+	var expected G2Jac
+	expected.MultiExp(samplePoints[:], sampleScalars[:])
+
+	// progress is reported once per chunk, and the result matches MultiExp
+	var result G2Jac
+	var nbCalls, lastDone, lastTotal int
+	opt := NewMultiExpOptions(runtime.NumCPU())
+	opt.Progress = func(done, total int) {
+		nbCalls++
+		lastDone, lastTotal = done, total
+	}
+	if _, err := result.MultiExpContext(context.Background(), samplePoints[:], sampleScalars[:], opt); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(&expected) {
+		t.Fatal("MultiExpContext and MultiExp results differ")
+	}
+	if nbCalls == 0 || nbCalls != lastDone || lastDone != lastTotal {
+		t.Fatalf("unexpected progress: %d calls, last reported %d/%d", nbCalls, lastDone, lastTotal)
+	}
+
+	// mismatched lengths
+	if _, err := result.MultiExpContext(context.Background(), samplePoints[:], sampleScalars[:nbSamples-1]); err == nil {
+		t.Fatal("MultiExpContext should fail on mismatched lengths")
+	}
+
+	// cancelled before the start
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result.Set(&g2Gen)
+	if _, err := result.MultiExpContext(ctx, samplePoints[:], sampleScalars[:]); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !result.Equal(&g2Gen) {
+		t.Fatal("a cancelled MultiExpContext should not modify its receiver")
+	}
+
+	// cancelled after the first chunk, with chunks processed one at a time
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	opt = NewMultiExpOptions(1)
+	opt.Progress = func(done, total int) {
+		cancel()
+	}
+	if _, err := result.MultiExpContext(ctx, samplePoints[:], sampleScalars[:], opt); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// the semaphore is released by cancelled multi exponentiations
+	opt.Progress = nil
+	if _, err := result.MultiExpContext(context.Background(), samplePoints[:], sampleScalars[:], opt); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(&expected) {
+		t.Fatal("MultiExpContext and MultiExp results differ")
+	}
+
+	// cancelled while the tokens of the shared options are held by another call
+	var ternaryScalars [nbSamples]fr.Element
+	for i := 0; i < nbSamples; i += 2 {
+		ternaryScalars[i].SetOne().FromMont()
+	}
+	for _, scalars := range [][]fr.Element{sampleScalars[:], ternaryScalars[:]} {
+		<-opt.chCpus
+		ctx, cancel := context.WithCancel(context.Background())
+		chErr := make(chan error, 1)
+		go func(scalars []fr.Element) {
+			var r G2Jac
+			_, err := r.MultiExpContext(ctx, samplePoints[:], scalars, opt)
+			chErr <- err
+		}(scalars)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		select {
+		case err := <-chErr:
+			if err != context.Canceled {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("a cancelled MultiExpContext should not wait for the cpu tokens")
+		}
+		opt.chCpus <- struct{}{}
+	}
+}
+
+func TestG2PrecomputedBases(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	const nbSamples = 50
+
+	var samplePoints [nbSamples]G2Affine
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 1; i <= nbSamples; i++ {
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	properties.Property("[BLS377] MultiExpPrecomputed should output the same result as MultiExp", prop.ForAll(
+		func(mixer fr.Element) bool {
+			var sampleScalars [nbSamples]fr.Element
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			var expected G2Jac
+			expected.MultiExp(samplePoints[:], sampleScalars[:])
+
+			for _, factor := range []int{1, 2, 4, 7} {
+				bases, err := NewPrecomputedBasesG2(samplePoints[:], factor)
+				if err != nil {
+					return false
+				}
+				var result G2Jac
+				if _, err := result.MultiExpPrecomputed(bases, sampleScalars[:]); err != nil || !result.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS377] PrecomputedBases should be unchanged by WriteTo (or WriteRawTo) then ReadFrom", prop.ForAll(
+		func(mixer fr.Element, raw bool) bool {
+			var sampleScalars [nbSamples]fr.Element
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			const factor = 3
+			bases, err := NewPrecomputedBasesG2(samplePoints[:], factor)
+			if err != nil {
+				return false
+			}
+			var buf bytes.Buffer
+			var written int64
+			pointSize := SizeOfG2AffineCompressed
+			if raw {
+				written, err = bases.WriteRawTo(&buf)
+				pointSize = SizeOfG2AffineUncompressed
+			} else {
+				written, err = bases.WriteTo(&buf)
+			}
+			// factor, slice length and points
+			if err != nil || written != int64(buf.Len()) || written != int64(4+4+factor*nbSamples*pointSize) {
+				return false
+			}
+			var decoded PrecomputedBasesG2
+			read, err := decoded.ReadFrom(&buf)
+			if err != nil || read != written || decoded.Factor() != factor || decoded.Len() != nbSamples {
+				return false
+			}
+
+			var result, expected G2Jac
+			if _, err := result.MultiExpPrecomputed(&decoded, sampleScalars[:]); err != nil {
+				return false
+			}
+			if _, err := expected.MultiExpPrecomputed(bases, sampleScalars[:]); err != nil {
+				return false
+			}
+			return result.Equal(&expected)
+		},
+		genScalar,
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	if _, err := NewPrecomputedBasesG2(samplePoints[:], 0); err == nil {
+		t.Fatal("NewPrecomputedBases should reject a factor of 0")
+	}
+	var invalid bytes.Buffer
+	invalid.Write([]byte{0, 0, 0, 0})
+	var decoded PrecomputedBasesG2
+	if _, err := decoded.ReadFrom(&invalid); err == nil {
+		t.Fatal("ReadFrom should reject a factor of 0")
+	}
+
+	bases, err := NewPrecomputedBasesG2(samplePoints[:], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result G2Jac
+	result.Set(&g2Gen)
+	if _, err := result.MultiExpPrecomputed(bases, make([]fr.Element, nbSamples-1)); err == nil {
+		t.Fatal("MultiExpPrecomputed should fail when there isn't one scalar per base")
+	}
+	if _, err := result.MultiExpPrecomputed(&PrecomputedBasesG2{}, nil); err == nil {
+		t.Fatal("MultiExpPrecomputed should reject uninitialized bases")
+	}
+	if !result.Equal(&g2Gen) {
+		t.Fatal("a failed MultiExpPrecomputed should not modify its receiver")
+	}
+}
+
+func TestG2CofactorCleaning(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[BLS377] Clearing the cofactor of a random point should set it in the r-torsion", prop.ForAll(
+		func() bool {
+			var pointCleared, infinity G2Jac
+			point := randomOnCurveG2()
+			pointCleared.ClearCofactor(&point)
+			infinity.Set(&g2Infinity)
+			return point.IsOnCurve() && !point.IsInSubGroup() && pointCleared.IsInSubGroup() && !pointCleared.Equal(&infinity) && infinity.IsInSubGroup()
+		},
+	))
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+}
+
+func TestG2IsInSubGroup(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	// inSubGroup is the definition of the membership test
+	inSubGroup := func(p *G2Jac) bool {
+		var rp G2Jac
+		rp.mulWindowed(p, fr.Modulus())
+		return rp.Z.IsZero()
+	}
+
+	properties.Property("[BLS377] IsInSubGroup should match [r]p == 0 on points with a non trivial cofactor component", prop.ForAll(
+		func() bool {
+			// t = [r]p is a non zero point of the cofactor torsion, q is in the r-torsion
+			var q, t, qt G2Jac
+			p := randomOnCurveG2()
+			q.ClearCofactor(&p)
+			t.mulWindowed(&p, fr.Modulus())
+			qt.Set(&q).AddAssign(&t)
+			if t.Z.IsZero() {
+				return false
+			}
+			for _, a := range []*G2Jac{&p, &q, &t, &qt} {
+				if a.IsInSubGroup() != inSubGroup(a) {
+					return false
+				}
+			}
+			return q.IsInSubGroup() && !t.IsInSubGroup() && !qt.IsInSubGroup()
+		},
+	))
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+}
+
+// randomOnCurveG2 returns a random point on the curve, which is not in the r-torsion
+// with overwhelming probability
+func randomOnCurveG2() G2Jac {
+	var a, x, b E2
+	a.SetRandom()
+	x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
+	for x.Legendre() != 1 {
+		a.SetRandom()
+		x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
+	}
+	b.Sqrt(&x)
+	var point G2Jac
+	point.X.Set(&a)
+	point.Y.Set(&b)
+	point.Z.SetOne()
+	return point
+}
+
+func TestG2BatchScalarMultiplication(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	// size of the multiExps
+	const nbSamples = 500
+
+	properties.Property("[BLS377] BatchScalarMultiplication should be consistant with individual scalar multiplications", prop.ForAll(
+		func(mixer fr.Element) bool {
+			// mixer ensures that all the words of a fpElement are set
+			var sampleScalars [nbSamples]fr.Element
+
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			result := BatchScalarMultiplicationG2(&g2GenAff, sampleScalars[:])
+
+			if len(result) != len(sampleScalars) {
+				return false
+			}
+
+			for i := 0; i < len(result); i++ {
+				var expectedJac G2Jac
+				var expected G2Affine
+				var b big.Int
+				expectedJac.mulGLV(&g2Gen, sampleScalars[i].ToBigInt(&b))
+				expected.FromJacobian(&expectedJac)
+				if !result[i].Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestG2FixedBaseTable(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	const nbSamples = 100
+
+	properties.Property("[BLS377] FixedBaseTable.Mul should output the same result as ScalarMultiplication", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var r big.Int
+			var expected G2Jac
+			s.ToBigIntRegular(&r)
+			expected.ScalarMultiplication(&g2Gen, &r)
+			res := G2GeneratorTable().Mul(&r)
+
+			var a G2Affine
+			a.Neg(&g2GenAff)
+			table := NewFixedBaseTableG2(&a)
+			res2 := table.Mul(&r)
+			res2.Neg(&res2)
+
+			return res.Equal(&expected) && res2.Equal(&expected)
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS377] FixedBaseTable.MulBatch should be consistant with BatchScalarMultiplication", prop.ForAll(
+		func(mixer fr.Element) bool {
+			// mixer ensures that all the words of a fpElement are set
+			var sampleScalars [nbSamples]fr.Element
+
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+			sampleScalars[0].SetZero()
+
+			result := G2GeneratorTable().MulBatch(sampleScalars[:])
+			expected := BatchScalarMultiplicationG2(&g2GenAff, sampleScalars[:])
+
+			if len(result) != len(expected) {
+				return false
+			}
+			for i := 0; i < len(result); i++ {
+				if !result[i].Equal(&expected[i]) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS377] FixedBaseTable of infinity should output infinity", prop.ForAll(
+		func(s fr.Element) bool {
+			var r big.Int
+			var inf G2Affine
+			s.ToBigIntRegular(&r)
+			res := NewFixedBaseTableG2(&inf).Mul(&r)
+			return res.Z.IsZero()
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// the top window of r-1 leaves room for a carry, which partitionScalars would drop
+	var rMinusOne big.Int
+	rMinusOne.Sub(fr.Modulus(), big.NewInt(1))
+	if top := new(big.Int).Rsh(&rMinusOne, fr.Limbs*64-fixedBaseWindow); top.Int64() >= (1<<(fixedBaseWindow-1))-1 {
+		t.Fatal("the top window of the fixed base tables may carry")
+	}
+
+	// the largest scalars, r-1 and r-2
+	for _, k := range []int64{1, 2} {
+		var s big.Int
+		s.Sub(fr.Modulus(), big.NewInt(k))
+		var expected G2Jac
+		expected.ScalarMultiplication(&g2Gen, &s)
+		res := G2GeneratorTable().Mul(&s)
+		if !res.Equal(&expected) {
+			t.Fatalf("FixedBaseTable.Mul(r-%d) should output the same result as ScalarMultiplication", k)
+		}
+
+		var sFr fr.Element
+		sFr.SetBigInt(&s).FromMont()
+		batch := G2GeneratorTable().MulBatch([]fr.Element{sFr})
+		var expectedAff G2Affine
+		expectedAff.FromJacobian(&expected)
+		if !batch[0].Equal(&expectedAff) {
+			t.Fatalf("FixedBaseTable.MulBatch(r-%d) should output the same result as ScalarMultiplication", k)
+		}
+	}
+}
+
+func TestRandomG2(t *testing.T) {
+
+	r1 := rand.New(rand.NewSource(42))
+	r2 := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 10; i++ {
+		p1, err := RandomG2(r1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2, err := RandomG2(r2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p1.Equal(&p2) {
+			t.Fatal("RandomG2 should be deterministic for a given source")
+		}
+		if p1.IsInfinity() || !p1.IsInSubGroup() {
+			t.Fatal("RandomG2 should output a point in the r-torsion")
+		}
+	}
+
+	if _, err := RandomG2(bytes.NewReader(nil)); err != io.EOF {
+		t.Fatal("RandomG2 should forward the reader error, got", err)
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkG2BatchScalarMul(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const pow = 15
+	const nbSamples = 1 << pow
+
+	var sampleScalars [nbSamples]fr.Element
+
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+	}
+
+	for i := 5; i <= pow; i++ {
+		using := 1 << i
+
+		b.Run(fmt.Sprintf("%d points", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				_ = BatchScalarMultiplicationG2(&g2GenAff, sampleScalars[:using])
+			}
+		})
+	}
+}
+
+func BenchmarkG2FixedBaseTable(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 10
+
+	var sampleScalars [nbSamples]fr.Element
+
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+	}
+
+	var scalar big.Int
+	sampleScalars[nbSamples-1].ToBigInt(&scalar)
+
+	b.Run("build", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			_ = NewFixedBaseTableG2(&g2GenAff)
+		}
+	})
+
+	table := G2GeneratorTable()
+
+	b.Run("Mul", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			_ = table.Mul(&scalar)
+		}
+	})
+
+	b.Run(fmt.Sprintf("MulBatch %d points", nbSamples), func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			_ = table.MulBatch(sampleScalars[:])
+		}
+	})
+}
+
+func BenchmarkG2ScalarMul(b *testing.B) {
+
+	var scalar big.Int
+	r := fr.Modulus()
+	scalar.SetString("5243587517512619047944770508185965837690552500527637822603658699938581184513", 10)
+	scalar.Add(&scalar, r)
+
+	var doubleAndAdd G2Jac
+
+	b.Run("double and add", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			doubleAndAdd.ScalarMultiplication(&g2Gen, &scalar)
+		}
+	})
+
+	var glv G2Jac
+	b.Run("GLV", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			glv.mulGLV(&g2Gen, &scalar)
+		}
+	})
+
+	var ct G2Jac
+	var scalarFr fr.Element
+	scalarFr.SetBigInt(&scalar)
+	b.Run("constant time", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			ct.ScalarMultiplicationCT(&g2Gen, &scalarFr)
+		}
+	})
+
+}
+
+func BenchmarkG2CofactorClearing(b *testing.B) {
+	var a G2Jac
+	a.Set(&g2Gen)
+	for i := 0; i < b.N; i++ {
+		a.ClearCofactor(&a)
+	}
+}
+
+func BenchmarkG2IsInSubGroup(b *testing.B) {
+	var a G2Jac
+	a.Set(&g2Gen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.IsInSubGroup()
+	}
+}
+
+func BenchmarkG2Add(b *testing.B) {
+	var a G2Jac
+	a.Double(&g2Gen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.AddAssign(&g2Gen)
+	}
+}
+
+func BenchmarkG2mAdd(b *testing.B) {
+	var a g2JacExtended
+	a.double(&g2GenAff)
+
+	var c G2Affine
+	c.FromJacobian(&g2Gen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.mAdd(&c)
+	}
+
+}
 
 func BenchmarkG2AddMixed(b *testing.B) {
 	var a G2Jac
@@ -1179,3 +2096,209 @@ func BenchmarkG2MultiExpG2(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkG2MultiExpBatchAffine(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const pow = 16
+	const nbSamples = 1 << pow
+
+	samplePoints := make([]G2Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+
+	// distinct points, as batch affine additions are slow for doublings
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	var testPoint G2Jac
+
+	for i := 12; i <= pow; i += 2 {
+		using := 1 << i
+
+		b.Run(fmt.Sprintf("%d points extended Jacobian", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using])
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d points batch affine", using), func(b *testing.B) {
+			opt := NewMultiExpOptions(runtime.NumCPU())
+			opt.BatchAffine = true
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using], opt)
+			}
+		})
+	}
+}
+
+func BenchmarkG2MultiExpPrecomputed(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 14
+
+	samplePoints := make([]G2Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	var testPoint G2Jac
+
+	b.Run("no precomputation", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			testPoint.MultiExp(samplePoints, sampleScalars)
+		}
+	})
+
+	for _, factor := range []int{2, 4, 8} {
+		bases, _ := NewPrecomputedBasesG2(samplePoints, factor)
+		b.Run(fmt.Sprintf("factor %d", factor), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpPrecomputed(bases, sampleScalars)
+			}
+		})
+	}
+}
+
+func BenchmarkG2MultiExpSmallScalars(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne).FromMont()
+
+	rnd := rand.New(rand.NewSource(0))
+	for _, nbBits := range []int{1, 0, 32, 64, fr.Bits} {
+		scalars := make([]fr.Element, nbSamples)
+		name := fmt.Sprintf("%d bits", nbBits)
+		for i := 0; i < nbSamples; i++ {
+			switch nbBits {
+			case 0:
+				// {-1, 0, 1}
+				name = "ternary"
+				if r := rnd.Intn(3); r == 2 {
+					scalars[i] = minusOne
+				} else {
+					scalars[i].SetUint64(uint64(r)).FromMont()
+				}
+			case fr.Bits:
+				scalars[i].SetRandom().FromMont()
+			default:
+				scalars[i].SetUint64(rnd.Uint64() >> (64 - nbBits)).FromMont()
+			}
+		}
+
+		var testPoint G2Jac
+		b.Run(name, func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints, scalars)
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpManyG2(b *testing.B) {
+	const nbSamples = 1 << 16
+	const nbVectors = 4
+
+	samplePoints := make([]G2Affine, nbSamples)
+	scalars := make([][]fr.Element, nbVectors)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+	for v := 0; v < nbVectors; v++ {
+		scalars[v] = make([]fr.Element, nbSamples)
+		for i := 0; i < nbSamples; i++ {
+			scalars[v][i].SetRandom().FromMont()
+		}
+	}
+
+	b.Run(fmt.Sprintf("%d x MultiExp", nbVectors), func(b *testing.B) {
+		var testPoint G2Jac
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			for v := 0; v < nbVectors; v++ {
+				testPoint.MultiExp(samplePoints, scalars[v])
+			}
+		}
+	})
+
+	b.Run("MultiExpMany", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			MultiExpManyG2(samplePoints, scalars)
+		}
+	})
+}
+
+func BenchmarkG2MultiExpReaderAt(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]G2Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+		sampleScalars[i].SetRandom().FromMont()
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, RawEncoding())
+	if err := enc.Encode(samplePoints); err != nil {
+		b.Fatal(err)
+	}
+	r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 4, int64(buf.Len()-4))
+
+	var testPoint G2Jac
+	b.Run("in memory", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			testPoint.MultiExp(samplePoints, sampleScalars)
+		}
+	})
+
+	const memoryPerPoint = 3*SizeOfG2AffineUncompressed + SizeOfFr
+	for _, nbSegments := range []int{4, 16} {
+		b.Run(fmt.Sprintf("%d segments", nbSegments), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpReaderAt(context.Background(), r, sampleScalars, nbSamples/nbSegments*memoryPerPoint)
+			}
+		})
+	}
+}
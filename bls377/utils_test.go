@@ -24,34 +24,34 @@ func GenFp() gopter.Gen {
 	}
 }
 
-// GenE2 generates an e2 elmt
+// GenE2 generates an E2 elmt
 func GenE2() gopter.Gen {
 	return gopter.CombineGens(
 		GenFp(),
 		GenFp(),
-	).Map(func(values []interface{}) *e2 {
-		return &e2{values[0].(fp.Element), values[1].(fp.Element)}
+	).Map(func(values []interface{}) *E2 {
+		return &E2{values[0].(fp.Element), values[1].(fp.Element)}
 	})
 }
 
-// GenE6 generates an e6 elmt
+// GenE6 generates an E6 elmt
 func GenE6() gopter.Gen {
 	return gopter.CombineGens(
 		GenE2(),
 		GenE2(),
 		GenE2(),
-	).Map(func(values []interface{}) *e6 {
-		return &e6{*values[0].(*e2), *values[1].(*e2), *values[2].(*e2)}
+	).Map(func(values []interface{}) *E6 {
+		return &E6{*values[0].(*E2), *values[1].(*E2), *values[2].(*E2)}
 	})
 }
 
-// GenE12 generates an e6 elmt
+// GenE12 generates an E6 elmt
 func GenE12() gopter.Gen {
 	return gopter.CombineGens(
 		GenE6(),
 		GenE6(),
-	).Map(func(values []interface{}) *e12 {
-		return &e12{*values[0].(*e6), *values[1].(*e6)}
+	).Map(func(values []interface{}) *E12 {
+		return &E12{*values[0].(*E6), *values[1].(*E6)}
 	})
 }
 
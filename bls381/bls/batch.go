@@ -0,0 +1,265 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"runtime"
+
+	"github.com/consensys/gurvy/bls381"
+	"github.com/consensys/gurvy/bls381/fr"
+	"github.com/consensys/gurvy/utils/parallel"
+)
+
+// Batch verification of independent signatures
+//
+// To verify the signatures sigs[i] of msgs[i] by pks[i], we sample random 64-bit scalars r[i] and check
+// 		e(-g1, Σ r[i]·sigs[i]) · ∏ e(r[i]·pks[i], H(msgs[i])) == 1
+// with a G2 MultiExp and a single multi-pairing (the Miller loops share their squarings and the
+// final exponentiation). In the minimal-signature-size variant, the signatures are combined with a G1 MultiExp
+// and the random scalars multiply the hashed messages instead of the public keys. An invalid signature passes the check with probability at most 2^-64.
+// If the check fails, the batch is split in halves which are checked recursively to find the invalid signatures.
+
+// BatchVerify verifies the signatures sigs[i] of msgs[i] by pks[i] and returns the sorted indices of the invalid
+// ones (nil if all the signatures are valid). The random scalars are read from crypto/rand
+func BatchVerify(pks []PublicKey, msgs [][]byte, sigs []Signature) ([]int, error) {
+	return BatchVerifyFrom(rand.Reader, pks, msgs, sigs)
+}
+
+// BatchVerifyFrom is BatchVerify with the random scalars read from rnd, which must be unpredictable
+// by the signers
+func BatchVerifyFrom(rnd io.Reader, pks []PublicKey, msgs [][]byte, sigs []Signature) ([]int, error) {
+	n := len(pks)
+	if len(msgs) != n || len(sigs) != n {
+		return nil, errors.New("pks, msgs and sigs must have the same length")
+	}
+
+	hashes := make([]bls381.G2Affine, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			var err error
+			if hashes[i], err = bls381.HashToCurveG2(msgs[i], []byte(DSTSignature)); err != nil {
+				// the domain separation tag is valid
+				panic(err)
+			}
+		}
+	})
+
+	b := batchMinPk{rnd: rnd, pks: pks, hashes: hashes, sigs: sigs}
+	return bisect(b.check, 0, n, nil)
+}
+
+// batchMinPk holds the inputs of BatchVerifyFrom, with the hashed messages
+type batchMinPk struct {
+	rnd    io.Reader
+	pks    []PublicKey
+	hashes []bls381.G2Affine
+	sigs   []Signature
+}
+
+// check returns true if the signatures in [start, end) are valid (with a random linear combination if there are several)
+func (b *batchMinPk) check(start, end int) (bool, error) {
+	n := end - start
+	for i := start; i < end; i++ {
+		// KeyValidate, the subgroup membership is checked when decoding
+		if b.pks[i].p.X.IsZero() && b.pks[i].p.Y.IsZero() {
+			return false, nil
+		}
+	}
+
+	P := make([]bls381.G1Affine, n+1)
+	Q := make([]bls381.G2Affine, n+1)
+	copy(Q, b.hashes[start:end])
+
+	if n == 1 {
+		P[0] = b.pks[start].p
+		P[1], Q[1] = g1GenNeg, b.sigs[start].p
+	} else {
+		scalars, err := randomScalars(b.rnd, n)
+		if err != nil {
+			return false, err
+		}
+
+		// r[i]·pks[i]
+		scaled := make([]bls381.G1Jac, n)
+		parallel.Execute(n, func(s, e int) {
+			var r big.Int
+			for i := s; i < e; i++ {
+				r.SetUint64(scalars[i][0])
+				scaled[i].FromAffine(&b.pks[start+i].p)
+				scaled[i].ScalarMultiplication(&scaled[i], &r)
+			}
+		})
+		bls381.BatchJacobianToAffineG1(scaled, P[:n])
+
+		// Σ r[i]·sigs[i]
+		sigs := make([]bls381.G2Affine, n)
+		for i := 0; i < n; i++ {
+			sigs[i] = b.sigs[start+i].p
+		}
+		var combined bls381.G2Jac
+		combined.MultiExp(sigs, scalars, bls381.NewMultiExpOptions(runtime.NumCPU()))
+		P[n] = g1GenNeg
+		Q[n].FromJacobian(&combined)
+	}
+
+	f, err := bls381.MillerLoop(P, Q)
+	if err != nil {
+		return false, err
+	}
+	f = bls381.FinalExponentiation(&f)
+	var one bls381.GT
+	one.SetOne()
+	return f.Equal(&one), nil
+}
+
+// bisect appends to res the indices in [start, end) of the invalid signatures, check(start, end)
+// returning true if all the signatures in [start, end) are valid
+func bisect(check func(start, end int) (bool, error), start, end int, res []int) ([]int, error) {
+	if start == end {
+		return res, nil
+	}
+	ok, err := check(start, end)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return res, nil
+	}
+	if end-start == 1 {
+		return append(res, start), nil
+	}
+	mid := start + (end-start)/2
+	if res, err = bisect(check, start, mid, res); err != nil {
+		return nil, err
+	}
+	return bisect(check, mid, end, res)
+}
+
+// randomScalars returns n non zero 64-bit scalars (NOT in Montgomery form) read from rnd
+func randomScalars(rnd io.Reader, n int) ([]fr.Element, error) {
+	buf := make([]byte, 8*n)
+	if _, err := io.ReadFull(rnd, buf); err != nil {
+		return nil, err
+	}
+	scalars := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		scalars[i][0] = binary.BigEndian.Uint64(buf[8*i:])
+		if scalars[i][0] == 0 {
+			scalars[i][0] = 1
+		}
+	}
+	return scalars, nil
+}
+
+// BatchVerifyMinSig verifies the signatures sigs[i] of msgs[i] by pks[i] and returns the sorted indices of the invalid
+// ones (nil if all the signatures are valid). The random scalars are read from crypto/rand
+func BatchVerifyMinSig(pks []PublicKeyMinSig, msgs [][]byte, sigs []SignatureMinSig) ([]int, error) {
+	return BatchVerifyMinSigFrom(rand.Reader, pks, msgs, sigs)
+}
+
+// BatchVerifyMinSigFrom is BatchVerifyMinSig with the random scalars read from rnd, which must be unpredictable
+// by the signers
+func BatchVerifyMinSigFrom(rnd io.Reader, pks []PublicKeyMinSig, msgs [][]byte, sigs []SignatureMinSig) ([]int, error) {
+	n := len(pks)
+	if len(msgs) != n || len(sigs) != n {
+		return nil, errors.New("pks, msgs and sigs must have the same length")
+	}
+
+	hashes := make([]bls381.G1Affine, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			var err error
+			if hashes[i], err = bls381.HashToCurveG1(msgs[i], []byte(DSTSignatureMinSig)); err != nil {
+				// the domain separation tag is valid
+				panic(err)
+			}
+		}
+	})
+
+	b := batchMinSig{rnd: rnd, pks: pks, hashes: hashes, sigs: sigs}
+	return bisect(b.check, 0, n, nil)
+}
+
+// batchMinSig holds the inputs of BatchVerifyMinSigFrom, with the hashed messages
+type batchMinSig struct {
+	rnd    io.Reader
+	pks    []PublicKeyMinSig
+	hashes []bls381.G1Affine
+	sigs   []SignatureMinSig
+}
+
+// check returns true if the signatures in [start, end) are valid (with a random linear combination if there are several)
+// e(Σ r[i]·sigs[i], -g2) · ∏ e(r[i]·H(msgs[i]), pks[i]) == 1, the random scalars multiply the hashes (in G1)
+func (b *batchMinSig) check(start, end int) (bool, error) {
+	n := end - start
+	for i := start; i < end; i++ {
+		// KeyValidate, the subgroup membership is checked when decoding
+		if b.pks[i].p.X.IsZero() && b.pks[i].p.Y.IsZero() {
+			return false, nil
+		}
+	}
+
+	P := make([]bls381.G1Affine, n+1)
+	Q := make([]bls381.G2Affine, n+1)
+	for i := 0; i < n; i++ {
+		Q[i] = b.pks[start+i].p
+	}
+
+	if n == 1 {
+		P[0] = b.hashes[start]
+		P[1], Q[1] = b.sigs[start].p, g2GenNeg
+	} else {
+		scalars, err := randomScalars(b.rnd, n)
+		if err != nil {
+			return false, err
+		}
+
+		// r[i]·H(msgs[i])
+		scaled := make([]bls381.G1Jac, n)
+		parallel.Execute(n, func(s, e int) {
+			var r big.Int
+			for i := s; i < e; i++ {
+				r.SetUint64(scalars[i][0])
+				scaled[i].FromAffine(&b.hashes[start+i])
+				scaled[i].ScalarMultiplication(&scaled[i], &r)
+			}
+		})
+		bls381.BatchJacobianToAffineG1(scaled, P[:n])
+
+		// Σ r[i]·sigs[i]
+		sigs := make([]bls381.G1Affine, n)
+		for i := 0; i < n; i++ {
+			sigs[i] = b.sigs[start+i].p
+		}
+		var combined bls381.G1Jac
+		combined.MultiExp(sigs, scalars, bls381.NewMultiExpOptions(runtime.NumCPU()))
+		P[n].FromJacobian(&combined)
+		Q[n] = g2GenNeg
+	}
+
+	f, err := bls381.MillerLoop(P, Q)
+	if err != nil {
+		return false, err
+	}
+	f = bls381.FinalExponentiation(&f)
+	var one bls381.GT
+	one.SetOne()
+	return f.Equal(&one), nil
+}
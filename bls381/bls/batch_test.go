@@ -0,0 +1,117 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBatchVerify(t *testing.T) {
+	const n = 16
+	sks := testKeys(t, n)
+	pks := make([]PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([]Signature, n)
+	for i := 0; i < n; i++ {
+		pks[i] = sks[i].PublicKey()
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		sigs[i] = sks[i].Sign(msgs[i])
+	}
+
+	invalid, err := BatchVerify(pks, msgs, sigs)
+	if err != nil || invalid != nil {
+		t.Fatalf("all signatures should be valid, got %v, %v", invalid, err)
+	}
+
+	// corrupt a few entries
+	sigs[3] = sigs[4]
+	msgs[11] = []byte("other message")
+	pks[14] = PublicKey{}
+	invalid, err = BatchVerify(pks, msgs, sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(invalid, []int{3, 11, 14}) {
+		t.Fatalf("wrong invalid signatures: %v", invalid)
+	}
+
+	if _, err := BatchVerify(pks, msgs[1:], sigs); err == nil {
+		t.Fatal("BatchVerify should fail on mismatched lengths")
+	}
+	if _, err := BatchVerifyFrom(bytes.NewReader(nil), pks, msgs, sigs); err == nil {
+		t.Fatal("BatchVerifyFrom should forward the errors of the random source")
+	}
+	if invalid, err := BatchVerify(nil, nil, nil); err != nil || invalid != nil {
+		t.Fatal("an empty batch should be valid")
+	}
+}
+
+func TestBatchVerifyMinSig(t *testing.T) {
+	const n = 16
+	sks := testKeys(t, n)
+	pks := make([]PublicKeyMinSig, n)
+	msgs := make([][]byte, n)
+	sigs := make([]SignatureMinSig, n)
+	for i := 0; i < n; i++ {
+		pks[i] = sks[i].PublicKeyMinSig()
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		sigs[i] = sks[i].SignMinSig(msgs[i])
+	}
+
+	invalid, err := BatchVerifyMinSig(pks, msgs, sigs)
+	if err != nil || invalid != nil {
+		t.Fatalf("all signatures should be valid, got %v, %v", invalid, err)
+	}
+
+	sigs[0] = sigs[1]
+	msgs[7] = []byte("other message")
+	pks[15] = PublicKeyMinSig{}
+	invalid, err = BatchVerifyMinSig(pks, msgs, sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(invalid, []int{0, 7, 15}) {
+		t.Fatalf("wrong invalid signatures: %v", invalid)
+	}
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	const n = 128
+	pks := make([]PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([]Signature, n)
+	for i := 0; i < n; i++ {
+		sk, _ := KeyGen(bytes.Repeat([]byte{byte(i)}, 32))
+		pks[i] = sk.PublicKey()
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		sigs[i] = sk.Sign(msgs[i])
+	}
+
+	b.Run(fmt.Sprintf("%d x Verify", n), func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			for i := 0; i < n; i++ {
+				pks[i].Verify(msgs[i], &sigs[i])
+			}
+		}
+	})
+	b.Run("BatchVerify", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			BatchVerify(pks, msgs, sigs)
+		}
+	})
+}
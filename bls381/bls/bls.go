@@ -0,0 +1,177 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bls implements BLS signatures over BLS12-381, following
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05 with the proof of possession scheme
+//
+// Two variants are provided:
+// * minimal-pubkey-size (PublicKey in G1, Signature in G2), which is the one used by the Ethereum consensus layer,
+// * minimal-signature-size (PublicKeyMinSig in G2, SignatureMinSig in G1).
+//
+// Public keys and signatures are encoded in compressed form (see bls381.G1Affine.Bytes), and their decoding checks
+// that they are in the correct subgroup. Public keys at infinity are rejected.
+//
+// Secret keys can be split in shares for t-of-n threshold signing (see Split and Recover).
+//
+// note: verifications are NOT constant time, signing uses a constant time scalar multiplication
+package bls
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+
+	"github.com/consensys/gurvy/bls381"
+	"github.com/consensys/gurvy/bls381/fr"
+)
+
+// domain separation tags of the proof of possession ciphersuites
+const (
+	// DSTSignature is the domain separation tag of the messages signed with Sign
+	DSTSignature = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	// DSTPossession is the domain separation tag of the public keys signed with ProvePossession
+	DSTPossession = "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	// DSTSignatureMinSig is the domain separation tag of the messages signed with SignMinSig
+	DSTSignatureMinSig = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+	// DSTPossessionMinSig is the domain separation tag of the public keys signed with ProvePossessionMinSig
+	DSTPossessionMinSig = "BLS_POP_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+// SizeOfSecretKey is the size in bytes of the binary representation of a SecretKey
+const SizeOfSecretKey = fr.Limbs * 8
+
+// SecretKey is a BLS secret key, shared by the two variants
+type SecretKey struct {
+	s fr.Element // in Montgomery form, non zero
+}
+
+// KeyGen derives a secret key from the secret input keying material ikm (at least 32 bytes)
+// and an optional keyInfo, as specified in section 2.3 of the draft
+func KeyGen(ikm []byte, keyInfo ...[]byte) (*SecretKey, error) {
+	if len(ikm) < 32 {
+		return nil, errors.New("ikm must be at least 32 bytes long")
+	}
+	var info []byte
+	if len(keyInfo) > 0 {
+		info = keyInfo[0]
+	}
+
+	// L = ceil((3 * ceil(log2(r))) / 16)
+	const L = (3*fr.Bits + 15) / 16
+
+	ikm0 := make([]byte, len(ikm)+1)
+	copy(ikm0, ikm)
+	infoL := make([]byte, len(info)+2)
+	copy(infoL, info)
+	infoL[len(info)+1] = L
+
+	var sk SecretKey
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	for sk.s.IsZero() {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		okm := hkdfExpand(hkdfExtract(salt, ikm0), infoL, L)
+		reduceBytes(&sk.s, okm)
+	}
+	return &sk, nil
+}
+
+// Bytes returns the big-endian binary representation of sk
+func (sk *SecretKey) Bytes() (res [SizeOfSecretKey]byte) {
+	s := sk.s.ToRegular()
+	for i := 0; i < fr.Limbs; i++ {
+		binary.BigEndian.PutUint64(res[SizeOfSecretKey-8*(i+1):], s[i])
+	}
+	return
+}
+
+// SetBytes sets sk from its big-endian binary representation, which must be
+// SizeOfSecretKey bytes long, and reduced and non zero modulo r
+func (sk *SecretKey) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfSecretKey {
+		return errors.New("invalid secret key size")
+	}
+	var s fr.Element
+	for i := 0; i < fr.Limbs; i++ {
+		s[i] = binary.BigEndian.Uint64(buf[SizeOfSecretKey-8*(i+1):])
+	}
+	// s <= r-1
+	var borrow uint64
+	for i := 0; i < fr.Limbs; i++ {
+		_, borrow = bits.Sub64(frModulusMinusOne[i], s[i], borrow)
+	}
+	if s.IsZero() || borrow != 0 {
+		return errors.New("invalid secret key: must be in [1, r)")
+	}
+	sk.s = *s.ToMont()
+	return nil
+}
+
+// frModulusMinusOne is r-1, in regular form
+var frModulusMinusOne = func() fr.Element {
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	return minusOne.ToRegular()
+}()
+
+// frTwo128 is 2^128, in Montgomery form
+var frTwo128 = *(&fr.Element{0, 0, 1, 0}).ToMont()
+
+// reduceBytes sets z to the big-endian integer b (at most 48 bytes long) modulo r, with field
+// operations only: b is split in 128-bit words, which are smaller than r, and z is computed with
+// Horner's rule in 2^128
+func reduceBytes(z *fr.Element, b []byte) {
+	var buf [48]byte
+	copy(buf[len(buf)-len(b):], b)
+	z.SetZero()
+	for i := 0; i < len(buf); i += 16 {
+		w := fr.Element{binary.BigEndian.Uint64(buf[i+8:]), binary.BigEndian.Uint64(buf[i:])}
+		z.Mul(z, &frTwo128).Add(z, w.ToMont())
+	}
+}
+
+// hkdfExtract is HKDF-Extract (RFC 5869) with SHA-256
+func hkdfExtract(salt, ikm []byte) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// hkdfExpand is HKDF-Expand (RFC 5869) with SHA-256, length must be at most 255*32
+func hkdfExpand(prk, info []byte, length int) []byte {
+	mac := hmac.New(sha256.New, prk)
+	res := make([]byte, 0, length+sha256.Size)
+	var t []byte
+	for i := byte(1); len(res) < length; i++ {
+		mac.Reset()
+		mac.Write(t)
+		mac.Write(info)
+		mac.Write([]byte{i})
+		t = mac.Sum(nil)
+		res = append(res, t...)
+	}
+	return res[:length]
+}
+
+// g1GenNeg and g2GenNeg are the opposites of the generators of G1 and G2
+var g1Gen, g1GenNeg bls381.G1Affine
+var g2Gen, g2GenNeg bls381.G2Affine
+
+func init() {
+	_, _, g1Gen, g2Gen = bls381.Generators()
+	g1GenNeg.Neg(&g1Gen)
+	g2GenNeg.Neg(&g2Gen)
+}
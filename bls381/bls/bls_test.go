@@ -0,0 +1,361 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gurvy/bls381"
+	"github.com/consensys/gurvy/bls381/fp"
+	"github.com/consensys/gurvy/bls381/fr"
+)
+
+// Ethereum consensus layer test vector (bls/sign)
+func TestSignTestVector(t *testing.T) {
+	skBytes, _ := hex.DecodeString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	expected, _ := hex.DecodeString("b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55")
+	msg := make([]byte, 32)
+
+	var sk SecretKey
+	if err := sk.SetBytes(skBytes); err != nil {
+		t.Fatal(err)
+	}
+	sig := sk.Sign(msg)
+	b := sig.Bytes()
+	if !bytes.Equal(b[:], expected) {
+		t.Fatal("wrong signature")
+	}
+	pk := sk.PublicKey()
+	if !pk.Verify(msg, &sig) {
+		t.Fatal("signature should verify")
+	}
+}
+
+// testKeys returns n secret keys derived from distinct ikm
+func testKeys(t *testing.T, n int) []SecretKey {
+	sks := make([]SecretKey, n)
+	for i := 0; i < n; i++ {
+		ikm := bytes.Repeat([]byte{byte(i + 1)}, 32)
+		sk, err := KeyGen(ikm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sks[i] = *sk
+	}
+	return sks
+}
+
+func TestKeyGen(t *testing.T) {
+	if _, err := KeyGen(make([]byte, 31)); err == nil {
+		t.Fatal("KeyGen should reject short ikm")
+	}
+	ikm := bytes.Repeat([]byte{42}, 32)
+	sk1, _ := KeyGen(ikm)
+	sk2, _ := KeyGen(ikm)
+	sk3, _ := KeyGen(ikm, []byte("key info"))
+	if sk1.Bytes() != sk2.Bytes() {
+		t.Fatal("KeyGen should be deterministic")
+	}
+	if sk1.Bytes() == sk3.Bytes() {
+		t.Fatal("KeyGen should depend on the key info")
+	}
+
+	var decoded SecretKey
+	b := sk1.Bytes()
+	if err := decoded.SetBytes(b[:]); err != nil || decoded.Bytes() != b {
+		t.Fatal("secret key encoding round trip failed")
+	}
+	if err := decoded.SetBytes(make([]byte, SizeOfSecretKey)); err == nil {
+		t.Fatal("SetBytes should reject a zero secret key")
+	}
+	if err := decoded.SetBytes(bytes.Repeat([]byte{0xff}, SizeOfSecretKey)); err == nil {
+		t.Fatal("SetBytes should reject a secret key larger than r")
+	}
+	var r, rMinusOne [SizeOfSecretKey]byte
+	fr.Modulus().FillBytes(r[:])
+	new(big.Int).Sub(fr.Modulus(), big.NewInt(1)).FillBytes(rMinusOne[:])
+	if err := decoded.SetBytes(r[:]); err == nil {
+		t.Fatal("SetBytes should reject r")
+	}
+	if err := decoded.SetBytes(rMinusOne[:]); err != nil || decoded.Bytes() != rMinusOne {
+		t.Fatal("SetBytes should accept r-1")
+	}
+}
+
+func TestReduceBytes(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	for i := 0; i < 100; i++ {
+		b := make([]byte, 48)
+		rnd.Read(b)
+		if i == 0 {
+			b = bytes.Repeat([]byte{0xff}, 48)
+		}
+		var z fr.Element
+		reduceBytes(&z, b)
+
+		var expected, actual big.Int
+		expected.SetBytes(b).Mod(&expected, fr.Modulus())
+		if z.ToBigIntRegular(&actual).Cmp(&expected) != 0 {
+			t.Fatalf("reduceBytes(%x) = %s, expected %s", b, actual.String(), expected.String())
+		}
+	}
+}
+
+func TestMinPk(t *testing.T) {
+	sks := testKeys(t, 4)
+	msg := []byte("message")
+
+	pks := make([]PublicKey, len(sks))
+	sigs := make([]Signature, len(sks))
+	msgs := make([][]byte, len(sks))
+	for i := range sks {
+		pks[i] = sks[i].PublicKey()
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		sigs[i] = sks[i].Sign(msgs[i])
+	}
+
+	// single signatures
+	sig := sks[0].Sign(msg)
+	if !pks[0].Verify(msg, &sig) {
+		t.Fatal("signature should verify")
+	}
+	if pks[1].Verify(msg, &sig) || pks[0].Verify([]byte("other message"), &sig) {
+		t.Fatal("signature should not verify with another key or message")
+	}
+
+	// encodings
+	var pk PublicKey
+	var decodedSig Signature
+	b := pks[0].Bytes()
+	if err := pk.SetBytes(b[:]); err != nil || !pk.Equal(&pks[0]) {
+		t.Fatal("public key encoding round trip failed")
+	}
+	bs := sig.Bytes()
+	if err := decodedSig.SetBytes(bs[:]); err != nil || !decodedSig.Equal(&sig) {
+		t.Fatal("signature encoding round trip failed")
+	}
+	var infinity bls381.G1Affine
+	b = infinity.Bytes()
+	if err := pk.SetBytes(b[:]); err == nil {
+		t.Fatal("SetBytes should reject the public key at infinity")
+	}
+	if (&PublicKey{}).Verify(msg, &sig) {
+		t.Fatal("the public key at infinity should not verify")
+	}
+	if err := pk.SetBytes(notInSubgroupG1()); err == nil {
+		t.Fatal("SetBytes should reject a public key not in G1")
+	}
+	if err := decodedSig.SetBytes(notInSubgroupG2()); err == nil {
+		t.Fatal("SetBytes should reject a signature not in G2")
+	}
+
+	// proofs of possession
+	proof := sks[0].ProvePossession()
+	if !pks[0].VerifyPossession(&proof) {
+		t.Fatal("proof of possession should verify")
+	}
+	if pks[1].VerifyPossession(&proof) {
+		t.Fatal("proof of possession should not verify with another key")
+	}
+	// a signature of the public key with the message DST is not a proof of possession
+	b = pks[0].Bytes()
+	sig = sks[0].Sign(b[:])
+	if pks[0].VerifyPossession(&sig) {
+		t.Fatal("proofs of possession and signatures should use distinct domain separation tags")
+	}
+
+	// aggregation
+	aggSig, err := Aggregate(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !AggregateVerify(pks, msgs, &aggSig) {
+		t.Fatal("aggregate signature should verify")
+	}
+	msgs[0], msgs[1] = msgs[1], msgs[0]
+	if AggregateVerify(pks, msgs, &aggSig) || AggregateVerify(pks[1:], msgs[1:], &aggSig) {
+		t.Fatal("aggregate signature should not verify with other messages")
+	}
+	if _, err := Aggregate(nil); err == nil {
+		t.Fatal("Aggregate should fail with no signature")
+	}
+
+	for i := range sks {
+		sigs[i] = sks[i].Sign(msg)
+	}
+	aggSig, _ = Aggregate(sigs)
+	if !FastAggregateVerify(pks, msg, &aggSig) {
+		t.Fatal("fast aggregate signature should verify")
+	}
+	if FastAggregateVerify(pks[1:], msg, &aggSig) || FastAggregateVerify(nil, msg, &aggSig) {
+		t.Fatal("fast aggregate signature should not verify with other keys")
+	}
+}
+
+func TestMinSig(t *testing.T) {
+	sks := testKeys(t, 4)
+	msg := []byte("message")
+
+	pks := make([]PublicKeyMinSig, len(sks))
+	sigs := make([]SignatureMinSig, len(sks))
+	msgs := make([][]byte, len(sks))
+	for i := range sks {
+		pks[i] = sks[i].PublicKeyMinSig()
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		sigs[i] = sks[i].SignMinSig(msgs[i])
+	}
+
+	// single signatures
+	sig := sks[0].SignMinSig(msg)
+	if !pks[0].Verify(msg, &sig) {
+		t.Fatal("signature should verify")
+	}
+	if pks[1].Verify(msg, &sig) || pks[0].Verify([]byte("other message"), &sig) {
+		t.Fatal("signature should not verify with another key or message")
+	}
+
+	// encodings
+	var pk PublicKeyMinSig
+	var decodedSig SignatureMinSig
+	b := pks[0].Bytes()
+	if err := pk.SetBytes(b[:]); err != nil || !pk.Equal(&pks[0]) {
+		t.Fatal("public key encoding round trip failed")
+	}
+	bs := sig.Bytes()
+	if err := decodedSig.SetBytes(bs[:]); err != nil || !decodedSig.Equal(&sig) {
+		t.Fatal("signature encoding round trip failed")
+	}
+	var infinity bls381.G2Affine
+	b = infinity.Bytes()
+	if err := pk.SetBytes(b[:]); err == nil {
+		t.Fatal("SetBytes should reject the public key at infinity")
+	}
+	if (&PublicKeyMinSig{}).Verify(msg, &sig) {
+		t.Fatal("the public key at infinity should not verify")
+	}
+	if err := pk.SetBytes(notInSubgroupG2()); err == nil {
+		t.Fatal("SetBytes should reject a public key not in G2")
+	}
+	if err := decodedSig.SetBytes(notInSubgroupG1()); err == nil {
+		t.Fatal("SetBytes should reject a signature not in G1")
+	}
+
+	// proofs of possession
+	proof := sks[0].ProvePossessionMinSig()
+	if !pks[0].VerifyPossession(&proof) {
+		t.Fatal("proof of possession should verify")
+	}
+	if pks[1].VerifyPossession(&proof) {
+		t.Fatal("proof of possession should not verify with another key")
+	}
+
+	// aggregation
+	aggSig, err := AggregateMinSig(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !AggregateVerifyMinSig(pks, msgs, &aggSig) {
+		t.Fatal("aggregate signature should verify")
+	}
+	msgs[0], msgs[1] = msgs[1], msgs[0]
+	if AggregateVerifyMinSig(pks, msgs, &aggSig) {
+		t.Fatal("aggregate signature should not verify with other messages")
+	}
+
+	for i := range sks {
+		sigs[i] = sks[i].SignMinSig(msg)
+	}
+	aggSig, _ = AggregateMinSig(sigs)
+	if !FastAggregateVerifyMinSig(pks, msg, &aggSig) {
+		t.Fatal("fast aggregate signature should verify")
+	}
+	if FastAggregateVerifyMinSig(pks[1:], msg, &aggSig) {
+		t.Fatal("fast aggregate signature should not verify with other keys")
+	}
+}
+
+// notInSubgroupG1 returns the compressed encoding of a point on E not in G1
+func notInSubgroupG1() []byte {
+	var p bls381.G1Affine
+	var x, y fp.Element
+	for x.SetUint64(1); ; x.Add(&x, new(fp.Element).SetOne()) {
+		y.Square(&x).Mul(&y, &x).Add(&y, new(fp.Element).SetUint64(4))
+		if y.Sqrt(&y) != nil {
+			p.X, p.Y = x, y
+			if !p.IsInSubGroup() {
+				break
+			}
+		}
+	}
+	b := p.Bytes()
+	return b[:]
+}
+
+// notInSubgroupG2 returns the compressed encoding of a point on Etwist not in G2
+func notInSubgroupG2() []byte {
+	var p bls381.G2Affine
+	var x, y, b bls381.E2
+	b.A0.SetUint64(4)
+	b.A1.SetUint64(4)
+	for x.A0.SetUint64(1); ; x.A0.Add(&x.A0, new(fp.Element).SetOne()) {
+		y.Square(&x).Mul(&y, &x).Add(&y, &b)
+		if y.Legendre() == 1 {
+			y.Sqrt(&y)
+			p.X, p.Y = x, y
+			if !p.IsInSubGroup() {
+				break
+			}
+		}
+	}
+	buf := p.Bytes()
+	return buf[:]
+}
+
+func BenchmarkSign(b *testing.B) {
+	sk, _ := KeyGen(make([]byte, 32))
+	msg := []byte("message")
+	b.Run("min pk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sk.Sign(msg)
+		}
+	})
+	b.Run("min sig", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sk.SignMinSig(msg)
+		}
+	})
+}
+
+func BenchmarkVerify(b *testing.B) {
+	sk, _ := KeyGen(make([]byte, 32))
+	msg := []byte("message")
+	pk, sig := sk.PublicKey(), sk.Sign(msg)
+	pkMinSig, sigMinSig := sk.PublicKeyMinSig(), sk.SignMinSig(msg)
+	b.Run("min pk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			pk.Verify(msg, &sig)
+		}
+	})
+	b.Run("min sig", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			pkMinSig.Verify(msg, &sigMinSig)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"errors"
+
+	"github.com/consensys/gurvy/bls381"
+)
+
+// minimal-pubkey-size variant: public keys in G1, signatures in G2
+
+// SizeOfPublicKey and SizeOfSignature are the sizes in bytes of the (compressed) binary
+// representations of a PublicKey and a Signature
+const (
+	SizeOfPublicKey = bls381.SizeOfG1AffineCompressed
+	SizeOfSignature = bls381.SizeOfG2AffineCompressed
+)
+
+// PublicKey is a BLS public key in G1 (minimal-pubkey-size variant)
+type PublicKey struct {
+	p bls381.G1Affine
+}
+
+// Signature is a BLS signature in G2 (minimal-pubkey-size variant)
+type Signature struct {
+	p bls381.G2Affine
+}
+
+// PublicKey returns the public key [sk]g1 associated with sk
+func (sk *SecretKey) PublicKey() PublicKey {
+	var p bls381.G1Jac
+	p.FromAffine(&g1Gen).ScalarMultiplicationCT(&p, &sk.s)
+	var pk PublicKey
+	pk.p.FromJacobian(&p)
+	return pk
+}
+
+// Sign returns the signature of msg: [sk]H(msg), H hashing to G2 with DSTSignature
+func (sk *SecretKey) Sign(msg []byte) Signature {
+	return sk.signG2(msg, DSTSignature)
+}
+
+// ProvePossession returns a proof of possession of sk: the signature of the public key with DSTPossession
+func (sk *SecretKey) ProvePossession() Signature {
+	pk := sk.PublicKey()
+	b := pk.Bytes()
+	return sk.signG2(b[:], DSTPossession)
+}
+
+func (sk *SecretKey) signG2(msg []byte, dst string) Signature {
+	h, err := bls381.HashToCurveG2(msg, []byte(dst))
+	if err != nil {
+		// the domain separation tags are valid
+		panic(err)
+	}
+	var p bls381.G2Jac
+	p.FromAffine(&h).ScalarMultiplicationCT(&p, &sk.s)
+	var sig Signature
+	sig.p.FromJacobian(&p)
+	return sig
+}
+
+// Bytes returns the compressed binary representation of pk
+func (pk *PublicKey) Bytes() [SizeOfPublicKey]byte {
+	return pk.p.Bytes()
+}
+
+// SetBytes sets pk from its compressed binary representation. It returns an error if the
+// point is not in G1 or is the point at infinity (KeyValidate)
+func (pk *PublicKey) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfPublicKey {
+		return errors.New("invalid public key size")
+	}
+	var p bls381.G1Affine
+	if _, err := p.SetBytes(buf); err != nil {
+		return err
+	}
+	if p.X.IsZero() && p.Y.IsZero() {
+		return errors.New("invalid public key: point at infinity")
+	}
+	pk.p = p
+	return nil
+}
+
+// Equal returns true if pk and other are the same public key
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	return pk.p.Equal(&other.p)
+}
+
+// Bytes returns the compressed binary representation of sig
+func (sig *Signature) Bytes() [SizeOfSignature]byte {
+	return sig.p.Bytes()
+}
+
+// SetBytes sets sig from its compressed binary representation. It returns an error if the point is not in G2
+func (sig *Signature) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfSignature {
+		return errors.New("invalid signature size")
+	}
+	var p bls381.G2Affine
+	if _, err := p.SetBytes(buf); err != nil {
+		return err
+	}
+	sig.p = p
+	return nil
+}
+
+// Equal returns true if sig and other are the same signature
+func (sig *Signature) Equal(other *Signature) bool {
+	return sig.p.Equal(&other.p)
+}
+
+// Verify returns true if sig is a valid signature of msg by pk
+func (pk *PublicKey) Verify(msg []byte, sig *Signature) bool {
+	return AggregateVerify([]PublicKey{*pk}, [][]byte{msg}, sig)
+}
+
+// VerifyPossession returns true if proof is a valid proof of possession of the secret key of pk
+func (pk *PublicKey) VerifyPossession(proof *Signature) bool {
+	b := pk.Bytes()
+	return coreAggregateVerifyG2([]PublicKey{*pk}, [][]byte{b[:]}, proof, DSTPossession)
+}
+
+// Aggregate returns the aggregation (sum) of the signatures
+func Aggregate(sigs []Signature) (Signature, error) {
+	if len(sigs) == 0 {
+		return Signature{}, errors.New("no signature to aggregate")
+	}
+	var acc bls381.G2Jac
+	acc.FromAffine(&sigs[0].p)
+	for i := 1; i < len(sigs); i++ {
+		acc.AddMixed(&sigs[i].p)
+	}
+	var res Signature
+	res.p.FromJacobian(&acc)
+	return res, nil
+}
+
+// AggregatePublicKeys returns the aggregation (sum) of the public keys
+// the public keys must come with verified proofs of possession (see VerifyPossession)
+func AggregatePublicKeys(pks []PublicKey) (PublicKey, error) {
+	if len(pks) == 0 {
+		return PublicKey{}, errors.New("no public key to aggregate")
+	}
+	var acc bls381.G1Jac
+	acc.FromAffine(&pks[0].p)
+	for i := 1; i < len(pks); i++ {
+		acc.AddMixed(&pks[i].p)
+	}
+	var res PublicKey
+	res.p.FromJacobian(&acc)
+	return res, nil
+}
+
+// AggregateVerify returns true if sig is the aggregation of the signatures of msgs[i] by pks[i]
+// the public keys must come with verified proofs of possession (see VerifyPossession)
+func AggregateVerify(pks []PublicKey, msgs [][]byte, sig *Signature) bool {
+	return coreAggregateVerifyG2(pks, msgs, sig, DSTSignature)
+}
+
+// FastAggregateVerify returns true if sig is the aggregation of the signatures of msg by all the pks
+// the public keys must come with verified proofs of possession (see VerifyPossession)
+func FastAggregateVerify(pks []PublicKey, msg []byte, sig *Signature) bool {
+	for i := 0; i < len(pks); i++ {
+		if pks[i].p.X.IsZero() && pks[i].p.Y.IsZero() {
+			return false
+		}
+	}
+	pk, err := AggregatePublicKeys(pks)
+	if err != nil {
+		return false
+	}
+	// the aggregated key may be at infinity, the verification then fails
+	return pk.Verify(msg, sig)
+}
+
+// coreAggregateVerifyG2 checks that ∏ e(pks[i], H(msgs[i])) == e(g1, sig), H hashing to G2 with dst
+func coreAggregateVerifyG2(pks []PublicKey, msgs [][]byte, sig *Signature, dst string) bool {
+	if len(pks) == 0 || len(pks) != len(msgs) {
+		return false
+	}
+	P := make([]bls381.G1Affine, len(pks)+1)
+	Q := make([]bls381.G2Affine, len(pks)+1)
+	for i := 0; i < len(pks); i++ {
+		// KeyValidate, the subgroup membership is checked when decoding
+		if pks[i].p.X.IsZero() && pks[i].p.Y.IsZero() {
+			return false
+		}
+		h, err := bls381.HashToCurveG2(msgs[i], []byte(dst))
+		if err != nil {
+			return false
+		}
+		P[i], Q[i] = pks[i].p, h
+	}
+	P[len(pks)], Q[len(pks)] = g1GenNeg, sig.p
+
+	ok, err := bls381.PairingCheck(P, Q)
+	return err == nil && ok
+}
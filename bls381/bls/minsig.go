@@ -0,0 +1,212 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"errors"
+
+	"github.com/consensys/gurvy/bls381"
+)
+
+// minimal-signature-size variant: public keys in G2, signatures in G1
+
+// SizeOfPublicKeyMinSig and SizeOfSignatureMinSig are the sizes in bytes of the (compressed) binary
+// representations of a PublicKeyMinSig and a SignatureMinSig
+const (
+	SizeOfPublicKeyMinSig = bls381.SizeOfG2AffineCompressed
+	SizeOfSignatureMinSig = bls381.SizeOfG1AffineCompressed
+)
+
+// PublicKeyMinSig is a BLS public key in G2 (minimal-signature-size variant)
+type PublicKeyMinSig struct {
+	p bls381.G2Affine
+}
+
+// SignatureMinSig is a BLS signature in G1 (minimal-signature-size variant)
+type SignatureMinSig struct {
+	p bls381.G1Affine
+}
+
+// PublicKeyMinSig returns the public key [sk]g2 associated with sk
+func (sk *SecretKey) PublicKeyMinSig() PublicKeyMinSig {
+	var p bls381.G2Jac
+	p.FromAffine(&g2Gen).ScalarMultiplicationCT(&p, &sk.s)
+	var pk PublicKeyMinSig
+	pk.p.FromJacobian(&p)
+	return pk
+}
+
+// SignMinSig returns the signature of msg: [sk]H(msg), H hashing to G1 with DSTSignatureMinSig
+func (sk *SecretKey) SignMinSig(msg []byte) SignatureMinSig {
+	return sk.signG1(msg, DSTSignatureMinSig)
+}
+
+// ProvePossessionMinSig returns a proof of possession of sk: the signature of the public key with DSTPossessionMinSig
+func (sk *SecretKey) ProvePossessionMinSig() SignatureMinSig {
+	pk := sk.PublicKeyMinSig()
+	b := pk.Bytes()
+	return sk.signG1(b[:], DSTPossessionMinSig)
+}
+
+func (sk *SecretKey) signG1(msg []byte, dst string) SignatureMinSig {
+	h, err := bls381.HashToCurveG1(msg, []byte(dst))
+	if err != nil {
+		// the domain separation tags are valid
+		panic(err)
+	}
+	var p bls381.G1Jac
+	p.FromAffine(&h).ScalarMultiplicationCT(&p, &sk.s)
+	var sig SignatureMinSig
+	sig.p.FromJacobian(&p)
+	return sig
+}
+
+// Bytes returns the compressed binary representation of pk
+func (pk *PublicKeyMinSig) Bytes() [SizeOfPublicKeyMinSig]byte {
+	return pk.p.Bytes()
+}
+
+// SetBytes sets pk from its compressed binary representation. It returns an error if the
+// point is not in G2 or is the point at infinity (KeyValidate)
+func (pk *PublicKeyMinSig) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfPublicKeyMinSig {
+		return errors.New("invalid public key size")
+	}
+	var p bls381.G2Affine
+	if _, err := p.SetBytes(buf); err != nil {
+		return err
+	}
+	if p.X.IsZero() && p.Y.IsZero() {
+		return errors.New("invalid public key: point at infinity")
+	}
+	pk.p = p
+	return nil
+}
+
+// Equal returns true if pk and other are the same public key
+func (pk *PublicKeyMinSig) Equal(other *PublicKeyMinSig) bool {
+	return pk.p.Equal(&other.p)
+}
+
+// Bytes returns the compressed binary representation of sig
+func (sig *SignatureMinSig) Bytes() [SizeOfSignatureMinSig]byte {
+	return sig.p.Bytes()
+}
+
+// SetBytes sets sig from its compressed binary representation. It returns an error if the point is not in G1
+func (sig *SignatureMinSig) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfSignatureMinSig {
+		return errors.New("invalid signature size")
+	}
+	var p bls381.G1Affine
+	if _, err := p.SetBytes(buf); err != nil {
+		return err
+	}
+	sig.p = p
+	return nil
+}
+
+// Equal returns true if sig and other are the same signature
+func (sig *SignatureMinSig) Equal(other *SignatureMinSig) bool {
+	return sig.p.Equal(&other.p)
+}
+
+// Verify returns true if sig is a valid signature of msg by pk
+func (pk *PublicKeyMinSig) Verify(msg []byte, sig *SignatureMinSig) bool {
+	return AggregateVerifyMinSig([]PublicKeyMinSig{*pk}, [][]byte{msg}, sig)
+}
+
+// VerifyPossession returns true if proof is a valid proof of possession of the secret key of pk
+func (pk *PublicKeyMinSig) VerifyPossession(proof *SignatureMinSig) bool {
+	b := pk.Bytes()
+	return coreAggregateVerifyG1([]PublicKeyMinSig{*pk}, [][]byte{b[:]}, proof, DSTPossessionMinSig)
+}
+
+// AggregateMinSig returns the aggregation (sum) of the signatures
+func AggregateMinSig(sigs []SignatureMinSig) (SignatureMinSig, error) {
+	if len(sigs) == 0 {
+		return SignatureMinSig{}, errors.New("no signature to aggregate")
+	}
+	var acc bls381.G1Jac
+	acc.FromAffine(&sigs[0].p)
+	for i := 1; i < len(sigs); i++ {
+		acc.AddMixed(&sigs[i].p)
+	}
+	var res SignatureMinSig
+	res.p.FromJacobian(&acc)
+	return res, nil
+}
+
+// AggregatePublicKeysMinSig returns the aggregation (sum) of the public keys
+// the public keys must come with verified proofs of possession (see VerifyPossession)
+func AggregatePublicKeysMinSig(pks []PublicKeyMinSig) (PublicKeyMinSig, error) {
+	if len(pks) == 0 {
+		return PublicKeyMinSig{}, errors.New("no public key to aggregate")
+	}
+	var acc bls381.G2Jac
+	acc.FromAffine(&pks[0].p)
+	for i := 1; i < len(pks); i++ {
+		acc.AddMixed(&pks[i].p)
+	}
+	var res PublicKeyMinSig
+	res.p.FromJacobian(&acc)
+	return res, nil
+}
+
+// AggregateVerifyMinSig returns true if sig is the aggregation of the signatures of msgs[i] by pks[i]
+// the public keys must come with verified proofs of possession (see VerifyPossession)
+func AggregateVerifyMinSig(pks []PublicKeyMinSig, msgs [][]byte, sig *SignatureMinSig) bool {
+	return coreAggregateVerifyG1(pks, msgs, sig, DSTSignatureMinSig)
+}
+
+// FastAggregateVerifyMinSig returns true if sig is the aggregation of the signatures of msg by all the pks
+// the public keys must come with verified proofs of possession (see VerifyPossession)
+func FastAggregateVerifyMinSig(pks []PublicKeyMinSig, msg []byte, sig *SignatureMinSig) bool {
+	for i := 0; i < len(pks); i++ {
+		if pks[i].p.X.IsZero() && pks[i].p.Y.IsZero() {
+			return false
+		}
+	}
+	pk, err := AggregatePublicKeysMinSig(pks)
+	if err != nil {
+		return false
+	}
+	// the aggregated key may be at infinity, the verification then fails
+	return pk.Verify(msg, sig)
+}
+
+// coreAggregateVerifyG1 checks that ∏ e(H(msgs[i]), pks[i]) == e(sig, g2), H hashing to G1 with dst
+func coreAggregateVerifyG1(pks []PublicKeyMinSig, msgs [][]byte, sig *SignatureMinSig, dst string) bool {
+	if len(pks) == 0 || len(pks) != len(msgs) {
+		return false
+	}
+	P := make([]bls381.G1Affine, len(pks)+1)
+	Q := make([]bls381.G2Affine, len(pks)+1)
+	for i := 0; i < len(pks); i++ {
+		// KeyValidate, the subgroup membership is checked when decoding
+		if pks[i].p.X.IsZero() && pks[i].p.Y.IsZero() {
+			return false
+		}
+		h, err := bls381.HashToCurveG1(msgs[i], []byte(dst))
+		if err != nil {
+			return false
+		}
+		P[i], Q[i] = h, pks[i].p
+	}
+	P[len(pks)], Q[len(pks)] = sig.p, g2GenNeg
+
+	ok, err := bls381.PairingCheck(P, Q)
+	return err == nil && ok
+}
@@ -0,0 +1,251 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/consensys/gurvy/bls381"
+	"github.com/consensys/gurvy/bls381/fr"
+)
+
+// Threshold signatures
+//
+// A secret key sk is split in n shares f(1), ..., f(n) of a random polynomial f of degree t-1 with f(0) = sk
+// (Shamir secret sharing). Any t partial signatures [f(i)]H(msg) are recombined in the signature [sk]H(msg)
+// by interpolating at 0: Σ λ_i·[f(i)]H(msg) with the Lagrange coefficients λ_i = ∏_{j≠i} x_j / (x_j - x_i).
+// The recovered signature is a regular signature, verified with the public key of sk.
+
+// SecretKeyShare is the share of index Index of a secret key
+type SecretKeyShare struct {
+	Index     uint64 // non zero
+	SecretKey SecretKey
+}
+
+// PublicKeyShare is the public key of the secret key share of index Index (minimal-pubkey-size variant)
+type PublicKeyShare struct {
+	Index     uint64
+	PublicKey PublicKey
+}
+
+// SignatureShare is a partial signature by the secret key share of index Index (minimal-pubkey-size variant)
+type SignatureShare struct {
+	Index     uint64
+	Signature Signature
+}
+
+// PublicKeyShareMinSig is the public key of the secret key share of index Index (minimal-signature-size variant)
+type PublicKeyShareMinSig struct {
+	Index     uint64
+	PublicKey PublicKeyMinSig
+}
+
+// SignatureShareMinSig is a partial signature by the secret key share of index Index (minimal-signature-size variant)
+type SignatureShareMinSig struct {
+	Index     uint64
+	Signature SignatureMinSig
+}
+
+// Split splits sk in n shares of indices 1, ..., n, such that any t of them recover sk.
+// The coefficients of the sharing polynomial are read from crypto/rand
+func Split(sk *SecretKey, t, n int) ([]SecretKeyShare, error) {
+	return SplitFrom(rand.Reader, sk, t, n)
+}
+
+// SplitFrom is Split with the coefficients of the sharing polynomial read from rnd, which must be kept secret
+func SplitFrom(rnd io.Reader, sk *SecretKey, t, n int) ([]SecretKeyShare, error) {
+	if t < 1 || t > n {
+		return nil, errors.New("the threshold must be in [1, n]")
+	}
+	if sk.s.IsZero() {
+		return nil, errors.New("invalid secret key")
+	}
+
+	// f(x) = sk + Σ coeffs[i-1]·x^i
+	coeffs := make([]fr.Element, t-1)
+	shares := make([]SecretKeyShare, n)
+	for {
+		for i := 0; i < len(coeffs); i++ {
+			if _, err := coeffs[i].SetRandomFrom(rnd); err != nil {
+				return nil, err
+			}
+		}
+
+		valid := true
+		for i := 0; i < n; i++ {
+			var x fr.Element
+			x.SetUint64(uint64(i + 1))
+
+			// Horner
+			var y fr.Element
+			for j := len(coeffs) - 1; j >= 0; j-- {
+				y.Add(&y, &coeffs[j]).Mul(&y, &x)
+			}
+			y.Add(&y, &sk.s)
+
+			shares[i].Index = uint64(i + 1)
+			shares[i].SecretKey.s = y
+			valid = valid && !y.IsZero()
+		}
+		// a share is zero with negligible probability, we resample the polynomial to keep secret keys non zero
+		if valid {
+			return shares, nil
+		}
+	}
+}
+
+// PublicKeyShare returns the public key of the share (minimal-pubkey-size variant)
+func (s *SecretKeyShare) PublicKeyShare() PublicKeyShare {
+	return PublicKeyShare{Index: s.Index, PublicKey: s.SecretKey.PublicKey()}
+}
+
+// Sign returns the partial signature of msg by the share (minimal-pubkey-size variant)
+func (s *SecretKeyShare) Sign(msg []byte) SignatureShare {
+	return SignatureShare{Index: s.Index, Signature: s.SecretKey.Sign(msg)}
+}
+
+// PublicKeyShareMinSig returns the public key of the share (minimal-signature-size variant)
+func (s *SecretKeyShare) PublicKeyShareMinSig() PublicKeyShareMinSig {
+	return PublicKeyShareMinSig{Index: s.Index, PublicKey: s.SecretKey.PublicKeyMinSig()}
+}
+
+// SignMinSig returns the partial signature of msg by the share (minimal-signature-size variant)
+func (s *SecretKeyShare) SignMinSig(msg []byte) SignatureShareMinSig {
+	return SignatureShareMinSig{Index: s.Index, Signature: s.SecretKey.SignMinSig(msg)}
+}
+
+// Verify returns true if sig is a valid partial signature of msg by the share of pk
+func (pk *PublicKeyShare) Verify(msg []byte, sig *SignatureShare) bool {
+	return pk.Index == sig.Index && pk.PublicKey.Verify(msg, &sig.Signature)
+}
+
+// Verify returns true if sig is a valid partial signature of msg by the share of pk
+func (pk *PublicKeyShareMinSig) Verify(msg []byte, sig *SignatureShareMinSig) bool {
+	return pk.Index == sig.Index && pk.PublicKey.Verify(msg, &sig.Signature)
+}
+
+// Recover interpolates the partial signatures at 0. If they are valid (see PublicKeyShare.Verify)
+// and there are at least t of them, the result is the signature by the shared secret key.
+// It returns an error if an index is zero or appears twice
+func Recover(sigs []SignatureShare) (Signature, error) {
+	indices := make([]uint64, len(sigs))
+	points := make([]bls381.G2Affine, len(sigs))
+	for i := 0; i < len(sigs); i++ {
+		indices[i] = sigs[i].Index
+		points[i] = sigs[i].Signature.p
+	}
+	lambdas, err := lagrangeCoefficients(indices)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	var p bls381.G2Jac
+	p.MultiExp(points, lambdas)
+	var res Signature
+	res.p.FromJacobian(&p)
+	return res, nil
+}
+
+// RecoverMinSig interpolates the partial signatures at 0. If they are valid (see PublicKeyShareMinSig.Verify)
+// and there are at least t of them, the result is the signature by the shared secret key.
+// It returns an error if an index is zero or appears twice
+func RecoverMinSig(sigs []SignatureShareMinSig) (SignatureMinSig, error) {
+	indices := make([]uint64, len(sigs))
+	points := make([]bls381.G1Affine, len(sigs))
+	for i := 0; i < len(sigs); i++ {
+		indices[i] = sigs[i].Index
+		points[i] = sigs[i].Signature.p
+	}
+	lambdas, err := lagrangeCoefficients(indices)
+	if err != nil {
+		return SignatureMinSig{}, err
+	}
+
+	var p bls381.G1Jac
+	p.MultiExp(points, lambdas)
+	var res SignatureMinSig
+	res.p.FromJacobian(&p)
+	return res, nil
+}
+
+// lagrangeCoefficients returns the Lagrange coefficients at 0 of the (non zero and distinct) indices,
+// in regular form (NOT Montgomery), ready to be used as MultiExp scalars
+//
+// λ_i = N / d_i with N = ∏ x_j and d_i = x_i·∏_{j≠i} (x_j - x_i), the d_i being inverted at once
+func lagrangeCoefficients(indices []uint64) ([]fr.Element, error) {
+	if len(indices) == 0 {
+		return nil, errors.New("no share to recover from")
+	}
+	seen := make(map[uint64]struct{}, len(indices))
+	for _, index := range indices {
+		if index == 0 {
+			return nil, errors.New("invalid share index: 0")
+		}
+		if _, ok := seen[index]; ok {
+			return nil, errors.New("invalid share index: duplicate")
+		}
+		seen[index] = struct{}{}
+	}
+
+	x := make([]fr.Element, len(indices))
+	var N fr.Element
+	N.SetOne()
+	for i := 0; i < len(indices); i++ {
+		x[i].SetUint64(indices[i])
+		N.Mul(&N, &x[i])
+	}
+
+	d := make([]fr.Element, len(indices))
+	for i := 0; i < len(indices); i++ {
+		d[i] = x[i]
+		for j := 0; j < len(indices); j++ {
+			if j == i {
+				continue
+			}
+			var diff fr.Element
+			diff.Sub(&x[j], &x[i])
+			d[i].Mul(&d[i], &diff)
+		}
+	}
+
+	lambdas := batchInvert(d)
+	for i := 0; i < len(lambdas); i++ {
+		lambdas[i].Mul(&lambdas[i], &N).FromMont()
+	}
+	return lambdas, nil
+}
+
+// batchInvert returns the inverses of the (non zero) elements of a, with a single inversion (Montgomery's trick)
+func batchInvert(a []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(a))
+
+	// res[i] = a[0]·...·a[i-1]
+	var acc fr.Element
+	acc.SetOne()
+	for i := 0; i < len(a); i++ {
+		res[i] = acc
+		acc.Mul(&acc, &a[i])
+	}
+
+	acc.Inverse(&acc)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		res[i].Mul(&res[i], &acc)
+		acc.Mul(&acc, &a[i])
+	}
+	return res
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gurvy/bls381/fr"
+)
+
+func TestThreshold(t *testing.T) {
+	const threshold, n = 3, 5
+	sk := testKeys(t, 1)[0]
+	pk := sk.PublicKey()
+	msg := []byte("message")
+
+	if _, err := Split(&sk, 0, n); err == nil {
+		t.Fatal("Split should reject a zero threshold")
+	}
+	if _, err := Split(&sk, n+1, n); err == nil {
+		t.Fatal("Split should reject a threshold larger than n")
+	}
+
+	shares, err := Split(&sk, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sigs := make([]SignatureShare, n)
+	for i := range shares {
+		if shares[i].Index != uint64(i+1) {
+			t.Fatal("wrong share index")
+		}
+		sigs[i] = shares[i].Sign(msg)
+		pki := shares[i].PublicKeyShare()
+		if !pki.Verify(msg, &sigs[i]) {
+			t.Fatal("valid partial signature should verify")
+		}
+		if i > 0 && pki.Verify(msg, &sigs[i-1]) {
+			t.Fatal("partial signature of another share should not verify")
+		}
+	}
+
+	// any threshold shares recover the signature
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		partial := make([]SignatureShare, len(subset))
+		for i, j := range subset {
+			partial[i] = sigs[j]
+		}
+		sig, err := Recover(partial)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pk.Verify(msg, &sig) {
+			t.Fatalf("recovered signature from %v should verify", subset)
+		}
+	}
+
+	// fewer shares don't
+	sig, err := Recover(sigs[:threshold-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.Verify(msg, &sig) {
+		t.Fatal("signature recovered from less than threshold shares should not verify")
+	}
+
+	if _, err := Recover(nil); err == nil {
+		t.Fatal("Recover should reject an empty set of shares")
+	}
+	if _, err := Recover([]SignatureShare{sigs[0], sigs[1], sigs[0]}); err == nil {
+		t.Fatal("Recover should reject duplicate indices")
+	}
+	zero := sigs[2]
+	zero.Index = 0
+	if _, err := Recover([]SignatureShare{sigs[0], sigs[1], zero}); err == nil {
+		t.Fatal("Recover should reject index 0")
+	}
+
+	// the shares are reproducible with a deterministic source
+	seed := bytes.Repeat([]byte{7}, 1024)
+	s1, _ := SplitFrom(bytes.NewReader(seed), &sk, threshold, n)
+	s2, _ := SplitFrom(bytes.NewReader(seed), &sk, threshold, n)
+	for i := range s1 {
+		if s1[i].SecretKey.Bytes() != s2[i].SecretKey.Bytes() {
+			t.Fatal("SplitFrom should be deterministic")
+		}
+	}
+}
+
+func TestThresholdMinSig(t *testing.T) {
+	const threshold, n = 2, 4
+	sk := testKeys(t, 1)[0]
+	pk := sk.PublicKeyMinSig()
+	msg := []byte("message")
+
+	shares, err := Split(&sk, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs := make([]SignatureShareMinSig, n)
+	for i := range shares {
+		sigs[i] = shares[i].SignMinSig(msg)
+		pki := shares[i].PublicKeyShareMinSig()
+		if !pki.Verify(msg, &sigs[i]) {
+			t.Fatal("valid partial signature should verify")
+		}
+	}
+
+	sig, err := RecoverMinSig([]SignatureShareMinSig{sigs[3], sigs[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Verify(msg, &sig) {
+		t.Fatal("recovered signature should verify")
+	}
+	if _, err := RecoverMinSig([]SignatureShareMinSig{sigs[1], sigs[1]}); err == nil {
+		t.Fatal("RecoverMinSig should reject duplicate indices")
+	}
+}
+
+func TestLagrangeCoefficients(t *testing.T) {
+	// Σ λ_i·f(x_i) == f(0) for f(x) = 5 + 3x + x^2
+	indices := []uint64{2, 7, 11, 1}
+	lambdas, err := lagrangeCoefficients(indices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum fr.Element
+	for i, index := range indices {
+		var y, l fr.Element
+		y.SetUint64(index*index + 3*index + 5)
+		l = lambdas[i]
+		l.ToMont()
+		y.Mul(&y, &l)
+		sum.Add(&sum, &y)
+	}
+	var five fr.Element
+	five.SetUint64(5)
+	if !sum.Equal(&five) {
+		t.Fatal("wrong Lagrange coefficients")
+	}
+}
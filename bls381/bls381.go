@@ -25,7 +25,7 @@ const ID = gurvy.BLS381
 var bCurveCoeff fp.Element
 
 // bTwistCurveCoeff b coeff of the twist (defined over Fp2) curve
-var bTwistCurveCoeff e2
+var bTwistCurveCoeff E2
 
 // generators of the r-torsion group, resp. in ker(pi-id), ker(Tr)
 var g1Gen G1Jac
@@ -54,10 +54,14 @@ var lambdaGLV big.Int
 // in ker((u,v)->u+vlambda[r]), and their determinant
 var glvBasis utils.Lattice
 
+// lambdaGT is the eigenvalue of the Frobenius on GT (p mod r), glvBasisGT the associated lattice
+var lambdaGT big.Int
+var glvBasisGT utils.Lattice
+
 // psi o pi o psi**-1, where psi:E->E' is the degree 6 iso defined over Fp12
 var endo struct {
-	u e2
-	v e2
+	u E2
+	v E2
 }
 
 // generator of the curve
@@ -94,6 +98,8 @@ func init() {
 	lambdaGLV.SetString("228988810152649578064853576960394133503", 10) //(x**2-1)
 	_r := fr.Modulus()
 	utils.PrecomputeLattice(_r, &lambdaGLV, &glvBasis)
+	lambdaGT.Mod(fp.Modulus(), _r)
+	utils.PrecomputeLattice(_r, &lambdaGT, &glvBasisGT)
 
 	endo.u.A0.SetString("0")
 	endo.u.A1.SetString("4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939437")
@@ -17,106 +17,142 @@
 package bls381
 
 import (
+	"errors"
+	"io"
 	"math/big"
 
 	"github.com/consensys/gurvy/bls381/fp"
 )
 
-// e2 is a degree two finite field extension of fp.Element
-type e2 struct {
+// SizeOfE2 is the size in bytes of an E2 element, in regular form
+const SizeOfE2 = 2 * SizeOfFp
+
+// E2 is a degree two finite field extension of fp.Element
+type E2 struct {
 	A0, A1 fp.Element
 }
 
 // Equal returns true if z equals x, fasle otherwise
-func (z *e2) Equal(x *e2) bool {
+func (z *E2) Equal(x *E2) bool {
 	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1)
 }
 
-// SetString sets a e2 element from strings
-func (z *e2) SetString(s1, s2 string) *e2 {
+// SetString sets a E2 element from strings
+func (z *E2) SetString(s1, s2 string) *E2 {
 	z.A0.SetString(s1)
 	z.A1.SetString(s2)
 	return z
 }
 
-// SetZero sets an e2 elmt to zero
-func (z *e2) SetZero() *e2 {
+// SetZero sets an E2 elmt to zero
+func (z *E2) SetZero() *E2 {
 	z.A0.SetZero()
 	z.A1.SetZero()
 	return z
 }
 
-// Set sets an e2 from x
-func (z *e2) Set(x *e2) *e2 {
+// Set sets an E2 from x
+func (z *E2) Set(x *E2) *E2 {
 	z.A0 = x.A0
 	z.A1 = x.A1
 	return z
 }
 
 // SetOne sets z to 1 in Montgomery form and returns z
-func (z *e2) SetOne() *e2 {
+func (z *E2) SetOne() *E2 {
 	z.A0.SetOne()
 	z.A1.SetZero()
 	return z
 }
 
 // SetRandom sets a0 and a1 to random values
-func (z *e2) SetRandom() *e2 {
+func (z *E2) SetRandom() *E2 {
 	z.A0.SetRandom()
 	z.A1.SetRandom()
 	return z
 }
 
-// IsZero returns true if the two elements are equal, fasle otherwise
-func (z *e2) IsZero() bool {
+// SetRandomFrom sets a0 and a1 to uniformly random values using the randomness read from r
+func (z *E2) SetRandomFrom(r io.Reader) (*E2, error) {
+	if _, err := z.A0.SetRandomFrom(r); err != nil {
+		return nil, err
+	}
+	if _, err := z.A1.SetRandomFrom(r); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E2) IsZero() bool {
 	return z.A0.IsZero() && z.A1.IsZero()
 }
 
-// Add adds two elements of e2
-func (z *e2) Add(x, y *e2) *e2 {
+// Bytes returns the regular (non montgomery) value of z as a big-endian byte array
+// A1 | A0
+func (z *E2) Bytes() (res [SizeOfE2]byte) {
+	putFp(res[:SizeOfFp], &z.A1)
+	putFp(res[SizeOfFp:], &z.A0)
+	return
+}
+
+// SetBytes interprets buf as the big-endian encoding A1 | A0 of an E2 element, and sets z to it
+// It returns an error if buf is not SizeOfE2 bytes long, or if a coordinate is not reduced modulo q
+func (z *E2) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfE2 {
+		return errors.New("invalid buffer size")
+	}
+	if err := readFp(&z.A1, buf[:SizeOfFp]); err != nil {
+		return err
+	}
+	return readFp(&z.A0, buf[SizeOfFp:])
+}
+
+// Add adds two elements of E2
+func (z *E2) Add(x, y *E2) *E2 {
 	addE2(z, x, y)
 	return z
 }
 
-// Sub two elements of e2
-func (z *e2) Sub(x, y *e2) *e2 {
+// Sub two elements of E2
+func (z *E2) Sub(x, y *E2) *E2 {
 	subE2(z, x, y)
 	return z
 }
 
-// Double doubles an e2 element
-func (z *e2) Double(x *e2) *e2 {
+// Double doubles an E2 element
+func (z *E2) Double(x *E2) *E2 {
 	doubleE2(z, x)
 	return z
 }
 
-// Neg negates an e2 element
-func (z *e2) Neg(x *e2) *e2 {
+// Neg negates an E2 element
+func (z *E2) Neg(x *E2) *E2 {
 	negE2(z, x)
 	return z
 }
 
 // String implements Stringer interface for fancy printing
-func (z *e2) String() string {
+func (z *E2) String() string {
 	return (z.A0.String() + "+" + z.A1.String() + "*u")
 }
 
 // ToMont converts to mont form
-func (z *e2) ToMont() *e2 {
+func (z *E2) ToMont() *E2 {
 	z.A0.ToMont()
 	z.A1.ToMont()
 	return z
 }
 
 // FromMont converts from mont form
-func (z *e2) FromMont() *e2 {
+func (z *E2) FromMont() *E2 {
 	z.A0.FromMont()
 	z.A1.FromMont()
 	return z
 }
 
-// MulByElement multiplies an element in e2 by an element in fp
-func (z *e2) MulByElement(x *e2, y *fp.Element) *e2 {
+// MulByElement multiplies an element in E2 by an element in fp
+func (z *E2) MulByElement(x *E2, y *fp.Element) *E2 {
 	var yCopy fp.Element
 	yCopy.Set(y)
 	z.A0.Mul(&x.A0, &yCopy)
@@ -124,23 +160,23 @@ func (z *e2) MulByElement(x *e2, y *fp.Element) *e2 {
 	return z
 }
 
-// Conjugate conjugates an element in e2
-func (z *e2) Conjugate(x *e2) *e2 {
+// Conjugate conjugates an element in E2
+func (z *E2) Conjugate(x *E2) *E2 {
 	z.A0 = x.A0
 	z.A1.Neg(&x.A1)
 	return z
 }
 
 // Legendre returns the Legendre symbol of z
-func (z *e2) Legendre() int {
+func (z *E2) Legendre() int {
 	var n fp.Element
 	z.norm(&n)
 	return n.Legendre()
 }
 
 // Exp sets z=x**e and returns it
-func (z *e2) Exp(x *e2, e big.Int) *e2 {
-	var res e2
+func (z *E2) Exp(x *E2, e big.Int) *E2 {
+	var res E2
 	res.SetOne()
 	b := e.Bytes()
 	for i := range b {
@@ -163,9 +199,9 @@ func (z *e2) Exp(x *e2, e big.Int) *e2 {
 // exists or not, it's up to the caller to call
 // Legendre beforehand.
 // cf https://eprint.iacr.org/2012/685.pdf (algo 9)
-func (z *e2) Sqrt(x *e2) *e2 {
+func (z *E2) Sqrt(x *E2) *E2 {
 
-	var a1, alpha, b, x0, minusone e2
+	var a1, alpha, b, x0, minusone E2
 	var e big.Int
 
 	minusone.SetOne().Neg(&minusone)
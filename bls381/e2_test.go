@@ -39,8 +39,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	genfp := GenFp()
 
 	properties.Property("[BLS381] Having the receiver as operand (addition) should output the same result", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Set(a)
 			c.Add(a, b)
 			a.Add(a, b)
@@ -52,8 +52,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (sub) should output the same result", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Set(a)
 			c.Sub(a, b)
 			a.Sub(a, b)
@@ -65,8 +65,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (mul) should output the same result", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Set(a)
 			c.Mul(a, b)
 			a.Mul(a, b)
@@ -78,8 +78,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (square) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Square(a)
 			a.Square(a)
 			return a.Equal(&b)
@@ -88,8 +88,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (neg) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Neg(a)
 			a.Neg(a)
 			return a.Equal(&b)
@@ -98,8 +98,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (double) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Double(a)
 			a.Double(a)
 			return a.Equal(&b)
@@ -108,8 +108,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.MulByNonResidue(a)
 			a.MulByNonResidue(a)
 			return a.Equal(&b)
@@ -118,8 +118,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (mul by non residue inverse) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.MulByNonResidueInv(a)
 			a.MulByNonResidueInv(a)
 			return a.Equal(&b)
@@ -128,8 +128,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Inverse(a)
 			a.Inverse(a)
 			return a.Equal(&b)
@@ -138,8 +138,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (Conjugate) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Conjugate(a)
 			a.Conjugate(a)
 			return a.Equal(&b)
@@ -148,8 +148,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (mul by element) should output the same result", prop.ForAll(
-		func(a *e2, b fp.Element) bool {
-			var c e2
+		func(a *E2, b fp.Element) bool {
+			var c E2
 			c.MulByElement(a, &b)
 			a.MulByElement(a, &b)
 			return a.Equal(&c)
@@ -159,8 +159,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Having the receiver as operand (Sqrt) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b, c, d, s e2
+		func(a *E2) bool {
+			var b, c, d, s E2
 
 			s.Square(a)
 			a.Set(&s)
@@ -191,8 +191,8 @@ func TestE2Ops(t *testing.T) {
 	genfp := GenFp()
 
 	properties.Property("[BLS381] sub & add should leave an element invariant", prop.ForAll(
-		func(a, b *e2) bool {
-			var c e2
+		func(a, b *E2) bool {
+			var c E2
 			c.Set(a)
 			c.Add(&c, b).Sub(&c, b)
 			return c.Equal(a)
@@ -202,8 +202,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] mul & inverse should leave an element invariant", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Inverse(b)
 			c.Set(a)
 			c.Mul(&c, b).Mul(&c, &d)
@@ -214,8 +214,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] inverse twice should leave an element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Inverse(a).Inverse(&b)
 			return a.Equal(&b)
 		},
@@ -223,8 +223,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] neg twice should leave an element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Neg(a).Neg(&b)
 			return a.Equal(&b)
 		},
@@ -232,8 +232,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] square and mul should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b, c e2
+		func(a *E2) bool {
+			var b, c E2
 			b.Mul(a, a)
 			c.Square(a)
 			return b.Equal(&c)
@@ -242,8 +242,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] MulByElement MulByElement inverse should leave an element invariant", prop.ForAll(
-		func(a *e2, b fp.Element) bool {
-			var c e2
+		func(a *E2, b fp.Element) bool {
+			var c E2
 			var d fp.Element
 			d.Inverse(&b)
 			c.MulByElement(a, &b).MulByElement(&c, &d)
@@ -254,8 +254,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Double and mul by 2 should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			var c fp.Element
 			c.SetUint64(2)
 			b.Double(a)
@@ -266,8 +266,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Mulbynonres mulbynonresinv should leave the element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.MulByNonResidue(a).MulByNonResidueInv(&b)
 			return a.Equal(&b)
 		},
@@ -275,8 +275,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] a + pi(a), a-pi(a) should be real", prop.ForAll(
-		func(a *e2) bool {
-			var b, c, d e2
+		func(a *E2) bool {
+			var b, c, d E2
 			var e, f fp.Element
 			b.Conjugate(a)
 			c.Add(a, &b)
@@ -289,8 +289,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] Legendre on square should output 1", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Square(a)
 			c := b.Legendre()
 			return c == 1
@@ -299,8 +299,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BLS381] square(sqrt) should leave an element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b, c, d, e e2
+		func(a *E2) bool {
+			var b, c, d, e E2
 			b.Square(a)
 			c.Sqrt(&b)
 			d.Square(&c)
@@ -310,6 +310,32 @@ func TestE2Ops(t *testing.T) {
 		genA,
 	))
 
+	properties.Property("[BLS381] Bytes and SetBytes should leave an element invariant", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[BLS381] a - a should be zero, a + 1 should not", prop.ForAll(
+		func(a *E2) bool {
+			var b, one E2
+			one.SetOne()
+			b.Sub(a, a)
+			if !b.IsZero() {
+				return false
+			}
+			b.Add(&b, &one)
+			return !b.IsZero()
+		},
+		genA,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
@@ -317,7 +343,7 @@ func TestE2Ops(t *testing.T) {
 // benches
 
 func BenchmarkE2Add(b *testing.B) {
-	var a, c e2
+	var a, c E2
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -327,7 +353,7 @@ func BenchmarkE2Add(b *testing.B) {
 }
 
 func BenchmarkE2Sub(b *testing.B) {
-	var a, c e2
+	var a, c E2
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -337,7 +363,7 @@ func BenchmarkE2Sub(b *testing.B) {
 }
 
 func BenchmarkE2Mul(b *testing.B) {
-	var a, c e2
+	var a, c E2
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -347,7 +373,7 @@ func BenchmarkE2Mul(b *testing.B) {
 }
 
 func BenchmarkE2MulByElement(b *testing.B) {
-	var a e2
+	var a E2
 	var c fp.Element
 	c.SetRandom()
 	a.SetRandom()
@@ -358,7 +384,7 @@ func BenchmarkE2MulByElement(b *testing.B) {
 }
 
 func BenchmarkE2Square(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -367,7 +393,7 @@ func BenchmarkE2Square(b *testing.B) {
 }
 
 func BenchmarkE2Inverse(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -376,7 +402,7 @@ func BenchmarkE2Inverse(b *testing.B) {
 }
 
 func BenchmarkE2MulNonRes(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -385,7 +411,7 @@ func BenchmarkE2MulNonRes(b *testing.B) {
 }
 
 func BenchmarkE2MulNonResInv(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -394,7 +420,7 @@ func BenchmarkE2MulNonResInv(b *testing.B) {
 }
 
 func BenchmarkE2Conjugate(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
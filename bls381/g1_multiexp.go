@@ -17,11 +17,18 @@
 package bls381
 
 import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
 	"math"
 	"runtime"
+	"sync"
 
 	"github.com/consensys/gurvy/bls381/fp"
 	"github.com/consensys/gurvy/bls381/fr"
+	"github.com/consensys/gurvy/utils/parallel"
 )
 
 // MultiExp implements section 4 of https://eprint.iacr.org/2012/549.pdf
@@ -29,6 +36,39 @@ import (
 // enabling to set
 // * max number of cpus to use
 func (p *G1Jac) MultiExp(points []G1Affine, scalars []fr.Element, opts ...*MultiExpOptions) *G1Jac {
+	var opt *MultiExpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewMultiExpOptions(runtime.NumCPU())
+	}
+
+	if _, err := p.multiExp(context.Background(), points, scalars, opt, fr.Limbs*64); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// MultiExpContext computes the same multi exponentiation as MultiExp, but returns an error
+// instead of panicking, and stops when ctx is cancelled; p is modified only if no error is returned.
+// MultiExpOptions.Progress, if set, is called as the chunks are processed
+func (p *G1Jac) MultiExpContext(ctx context.Context, points []G1Affine, scalars []fr.Element, opts ...*MultiExpOptions) (*G1Jac, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("len(points) != len(scalars)")
+	}
+	var opt *MultiExpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewMultiExpOptions(runtime.NumCPU())
+	}
+
+	return p.multiExp(ctx, points, scalars, opt, fr.Limbs*64)
+}
+
+// multiExp computes the multi exponentiation of points by scalars (NOT in Montgomery form)
+// which are known to be smaller than 2^nbBits, allowing to skip the most significant windows
+func (p *G1Jac) multiExp(ctx context.Context, points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, nbBits int) (*G1Jac, error) {
 	// note:
 	// each of the msmCX method is the same, except for the c constant it declares
 	// duplicating (through template generation) these methods allows to declare the buckets on the stack
@@ -55,102 +95,501 @@ func (p *G1Jac) MultiExp(points []G1Affine, scalars []fr.Element, opts ...*Multi
 	// step 3
 	// reduce the buckets weigthed sums into our result (msmReduceChunk)
 
-	var opt *MultiExpOptions
-	if len(opts) > 0 {
-		opt = opts[0]
-	} else {
-		opt = NewMultiExpOptions(runtime.NumCPU())
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	if opt.c == 0 {
-		nbPoints := len(points)
-
-		// implemented msmC methods (the c we use must be in this slice)
-		implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 20, 21}
-
-		// approximate cost (in group operations)
-		// cost = bits/c * (nbPoints + 2^{c-1})
-		// this needs to be verified empirically.
-		// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
-		min := math.MaxFloat64
-		for _, c := range implementedCs {
-			cc := fr.Limbs * 64 * (nbPoints + (1 << (c - 1)))
-			cost := float64(cc) / float64(c)
-			if cost < min {
-				min = cost
-				opt.c = c
-			}
-		}
-
-		// empirical, needs to be tuned.
-
-		if opt.c > 16 && nbPoints < 1<<23 {
-			opt.c = 16
-		}
-
+	// small scalars need less windows, and scalars in {-1, 0, 1} none at all
+	scalarsBits, ternary := scalarsBitLen(scalars)
+	if ternary {
+		return p.msmTernary(ctx, points, scalars, opt)
+	}
+	if scalarsBits < nbBits {
+		nbBits = scalarsBits
 	}
 
+	// the window size is chosen for this call only: opt may be shared by multi exponentiations
+	// of other sizes
+	c := msmBestCG1(len(points), nbBits)
+
 	// take all the cpus to ourselves
 	opt.lock.Lock()
 
 	// partition the scalars
 	// note: we do that before the actual chunk processing, as for each c-bit window (starting from LSW)
 	// if it's larger than 2^{c-1}, we have a carry we need to propagate up to the higher window
-	scalars = partitionScalars(scalars, opt.c)
+	scalars = partitionScalars(scalars, c)
+
+	// batch affine additions pay off when the buckets receive many points
+	batchAffine := opt.BatchAffine && len(points) >= batchAffineMinPoints
+
+	params := msmParams{
+		batchAffine: batchAffine,
+		nbBits:      nbBits,
+		done:        ctx.Done(),
+		progress:    newMsmProgress(opt.Progress),
+	}
 
-	switch opt.c {
+	var res G1Jac
+	switch c {
 
 	case 4:
-		return p.msmC4(points, scalars, opt)
+		res.msmC4(points, scalars, opt, params)
 
 	case 5:
-		return p.msmC5(points, scalars, opt)
+		res.msmC5(points, scalars, opt, params)
 
 	case 6:
-		return p.msmC6(points, scalars, opt)
+		res.msmC6(points, scalars, opt, params)
 
 	case 7:
-		return p.msmC7(points, scalars, opt)
+		res.msmC7(points, scalars, opt, params)
 
 	case 8:
-		return p.msmC8(points, scalars, opt)
+		res.msmC8(points, scalars, opt, params)
 
 	case 9:
-		return p.msmC9(points, scalars, opt)
+		res.msmC9(points, scalars, opt, params)
 
 	case 10:
-		return p.msmC10(points, scalars, opt)
+		res.msmC10(points, scalars, opt, params)
 
 	case 11:
-		return p.msmC11(points, scalars, opt)
+		res.msmC11(points, scalars, opt, params)
 
 	case 12:
-		return p.msmC12(points, scalars, opt)
+		res.msmC12(points, scalars, opt, params)
 
 	case 13:
-		return p.msmC13(points, scalars, opt)
+		res.msmC13(points, scalars, opt, params)
 
 	case 14:
-		return p.msmC14(points, scalars, opt)
+		res.msmC14(points, scalars, opt, params)
 
 	case 15:
-		return p.msmC15(points, scalars, opt)
+		res.msmC15(points, scalars, opt, params)
 
 	case 16:
-		return p.msmC16(points, scalars, opt)
+		res.msmC16(points, scalars, opt, params)
 
 	case 20:
-		return p.msmC20(points, scalars, opt)
+		res.msmC20(points, scalars, opt, params)
 
 	case 21:
-		return p.msmC21(points, scalars, opt)
+		res.msmC21(points, scalars, opt, params)
 
 	case 22:
-		return p.msmC22(points, scalars, opt)
+		res.msmC22(points, scalars, opt, params)
 
 	default:
-		panic("unimplemented")
+		opt.lock.Unlock()
+		return nil, errors.New("unimplemented c")
+	}
+
+	// the chunks of a cancelled multi exponentiation may be incomplete
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.Set(&res)
+	return p, nil
+}
+
+// msmBestCG1 returns the window size of the implemented msmCX methods minimizing the
+// approximate cost (in group operations) of a multi exponentiation of nbPoints points by nbBits-bit scalars
+func msmBestCG1(nbPoints, nbBits int) uint64 {
+	implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 20, 21}
+
+	// cost = nbChunks * (nbPoints + 2^{c-1}), nbChunks = ceil(nbBits/c) + 1 (carry window)
+	// this needs to be verified empirically.
+	// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
+	var bestC uint64
+	min := math.MaxFloat64
+	for _, c := range implementedCs {
+		nbChunks := (nbBits+int(c)-1)/int(c) + 1
+		cost := float64(nbChunks) * float64(nbPoints+(1<<(c-1)))
+		if cost < min {
+			min = cost
+			bestC = c
+		}
+	}
+
+	// empirical, needs to be tuned.
+	if bestC > 16 && nbPoints < 1<<23 {
+		bestC = 16
+	}
+	return bestC
+}
+
+// msmTernary sets p to the multi exponentiation of points by scalars in {-1, 0, 1} (NOT in Montgomery form),
+// that is a sum of (possibly negated) points. The sum is split in chunks processed in parallel.
+func (p *G1Jac) msmTernary(ctx context.Context, points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) (*G1Jac, error) {
+	nbPoints := len(scalars)
+	nbTasks := cap(opt.chCpus)
+	if nbPoints < msmTernaryMinPointsPerTask*nbTasks {
+		nbTasks = nbPoints/msmTernaryMinPointsPerTask + 1
+	}
+	chunkSize := (nbPoints + nbTasks - 1) / nbTasks
+
+	done := ctx.Done()
+	progress := newMsmProgress(opt.Progress)
+	chChunks := make([]chan G1Jac, nbTasks)
+
+	// take all the cpus to ourselves
+	opt.lock.Lock()
+	for i := 0; i < nbTasks; i++ {
+		start, end := i*chunkSize, (i+1)*chunkSize
+		if start > nbPoints {
+			start = nbPoints
+		}
+		if end > nbPoints {
+			end = nbPoints
+		}
+		chChunks[i] = make(chan G1Jac, 1)
+		if !opt.acquireCpu(done) {
+			// cancelled, the chunk is not processed
+			chChunks[i] <- g1Infinity
+			continue
+		}
+		go func(chRes chan<- G1Jac, points []G1Affine, scalars []fr.Element) {
+			chRes <- msmTernaryChunkG1(points, scalars, done)
+			progress.chunkDone(nbTasks)
+			opt.chCpus <- struct{}{} // release token in the semaphore
+		}(chChunks[i], points[start:end], scalars[start:end])
+	}
+	opt.lock.Unlock()
+
+	var res G1Jac
+	res.Set(&g1Infinity)
+	for i := 0; i < nbTasks; i++ {
+		total := <-chChunks[i]
+		res.AddAssign(&total)
+	}
+
+	// the chunks of a cancelled multi exponentiation may be incomplete
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.Set(&res)
+	return p, nil
+}
+
+// msmTernaryChunkG1 returns the sum of the points with scalar 1, minus the points with scalar -1
+//
+// Large inputs are accumulated in msmTernaryNbLanes affine accumulators, the point i being added to
+// the accumulator i modulo msmTernaryNbLanes: the additions of a round share one field inversion
+// (see batchAddG1Affine)
+func msmTernaryChunkG1(points []G1Affine, scalars []fr.Element, done <-chan struct{}) G1Jac {
+	var total g1JacExtended
+	total.setInfinity()
+
+	if len(scalars) < batchAffineMinPoints {
+		for i := 0; i < len(scalars); i++ {
+			if i&msmCancelCheckMask == 0 && isDone(done) {
+				return g1Infinity
+			}
+			if scalars[i].IsZero() {
+				continue
+			}
+			if scalars[i] == frMinusOne {
+				total.mSub(&points[i])
+			} else {
+				total.mAdd(&points[i])
+			}
+		}
+		var res G1Jac
+		return *res.fromJacExtended(&total)
+	}
+
+	// lanes are initialized to the infinity symbol (0, 0)
+	lanes := make([]G1Affine, msmTernaryNbLanes)
+	laneIDs := make([]uint32, 0, msmTernaryNbLanes)
+	toAdd := make([]G1Affine, 0, msmTernaryNbLanes)
+	scratch := make([]fp.Element, msmTernaryNbLanes)
+
+	lane := uint32(0)
+	for i := 0; i < len(scalars); i++ {
+		if i&msmCancelCheckMask == 0 && isDone(done) {
+			return g1Infinity
+		}
+		if scalars[i].IsZero() || (points[i].X.IsZero() && points[i].Y.IsZero()) {
+			continue
+		}
+		q := points[i]
+		if scalars[i] == frMinusOne {
+			q.Neg(&q)
+		}
+
+		acc := &lanes[lane]
+		if acc.X.IsZero() && acc.Y.IsZero() {
+			*acc = q
+		} else if acc.X.Equal(&q.X) {
+			if acc.Y.Equal(&q.Y) {
+				// doubling, this should be rare
+				var t G1Jac
+				t.FromAffine(acc).DoubleAssign()
+				acc.FromJacobian(&t)
+			} else {
+				// q == -acc
+				*acc = G1Affine{}
+			}
+		} else {
+			laneIDs = append(laneIDs, lane)
+			toAdd = append(toAdd, q)
+		}
+
+		// each lane appears at most once in a batch
+		lane++
+		if lane == msmTernaryNbLanes {
+			batchAddG1Affine(lanes, laneIDs, toAdd, scratch)
+			laneIDs, toAdd = laneIDs[:0], toAdd[:0]
+			lane = 0
+		}
 	}
+	batchAddG1Affine(lanes, laneIDs, toAdd, scratch)
+
+	for i := 0; i < len(lanes); i++ {
+		if !(lanes[i].X.IsZero() && lanes[i].Y.IsZero()) {
+			total.mAdd(&lanes[i])
+		}
+	}
+	var res G1Jac
+	return *res.fromJacExtended(&total)
+}
+
+// MultiExpManyG1 computes the multi exponentiations of points by each of the scalars vectors
+// (NOT in Montgomery form), with a single pass over the points per c-bit window for all the vectors.
+// The windows are processed in parallel, within the limits of the (optional) MultiExpOptions.
+// It returns an error if one of the vectors doesn't have one scalar per point
+func MultiExpManyG1(points []G1Affine, scalars [][]fr.Element, opts ...*MultiExpOptions) ([]G1Jac, error) {
+	var opt *MultiExpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewMultiExpOptions(runtime.NumCPU())
+	}
+
+	nbVectors := len(scalars)
+	res := make([]G1Jac, nbVectors)
+	if nbVectors == 0 {
+		return res, nil
+	}
+	nbPoints := len(points)
+	nbBits := 0
+	for v := 0; v < nbVectors; v++ {
+		if len(scalars[v]) != nbPoints {
+			return nil, fmt.Errorf("len(points) != len(scalars[%d])", v)
+		}
+		b, ternary := scalarsBitLen(scalars[v])
+		if ternary {
+			// scalarsBitLen leaves -1 out of the bit length of ternary scalars, but the windows
+			// see it as r-1
+			for i := 0; i < nbPoints; i++ {
+				if scalars[v][i] == frMinusOne {
+					b = fr.Bits
+					break
+				}
+			}
+		}
+		if b > nbBits {
+			nbBits = b
+		}
+	}
+
+	c := msmManyBestC(nbPoints, nbVectors, nbBits)
+	digits := make([][]fr.Element, nbVectors)
+	for v := 0; v < nbVectors; v++ {
+		digits[v] = partitionScalars(scalars[v], c)
+	}
+
+	nbChunks := fr.Limbs * 64 / int(c)
+	if (fr.Limbs*64)%int(c) != 0 {
+		nbChunks++
+	}
+	nbChunks = msmParams{nbBits: nbBits}.nbChunks(int(c), nbChunks)
+
+	// totals[chunk][v] is the weighted sum of the buckets of the vector v in the window chunk
+	totals := make([][]G1Jac, nbChunks)
+	var wg sync.WaitGroup
+	wg.Add(nbChunks)
+
+	// take all the cpus to ourselves
+	opt.lock.Lock()
+	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+		<-opt.chCpus // wait to have a cpu before scheduling
+		go func(chunk int) {
+			totals[chunk] = msmManyProcessChunkG1(uint64(chunk), c, points, digits)
+			opt.chCpus <- struct{}{} // release token in the semaphore
+			wg.Done()
+		}(chunk)
+	}
+	opt.lock.Unlock()
+	wg.Wait()
+
+	for v := 0; v < nbVectors; v++ {
+		res[v].Set(&g1Infinity)
+		for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+			for l := uint64(0); l < c; l++ {
+				res[v].DoubleAssign()
+			}
+			res[v].AddAssign(&totals[chunk][v])
+		}
+	}
+	return res, nil
+}
+
+// msmManyProcessChunkG1 returns, for each of the vectors of digits, the weighted sum of the buckets of the window chunk
+// each block of points is read once, and added to the buckets of all the vectors
+func msmManyProcessChunkG1(chunk, c uint64, points []G1Affine, digits [][]fr.Element) []G1Jac {
+	nbBuckets := 1 << (c - 1)
+	msbWindow := uint64(nbBuckets)
+
+	buckets := make([]g1JacExtended, len(digits)*nbBuckets)
+	for i := 0; i < len(buckets); i++ {
+		buckets[i].setInfinity()
+	}
+
+	s := newChunkSelector(chunk, c)
+
+	// the points are processed in blocks which stay in cache while they are added to the buckets of each vector
+	for start := 0; start < len(points); start += msmManyBlockSize {
+		end := start + msmManyBlockSize
+		if end > len(points) {
+			end = len(points)
+		}
+		for v := 0; v < len(digits); v++ {
+			vBuckets := buckets[v*nbBuckets : (v+1)*nbBuckets]
+			vDigits := digits[v]
+			for i := start; i < end; i++ {
+				bits := (vDigits[i][s.index] & s.mask) >> s.shift
+				if s.multiWordSelect {
+					bits += (vDigits[i][s.index+1] & s.maskHigh) << s.shiftHigh
+				}
+
+				if bits == 0 {
+					continue
+				}
+
+				// if msbWindow bit is set, we need to substract
+				if bits&msbWindow == 0 {
+					vBuckets[bits-1].mAdd(&points[i])
+				} else {
+					vBuckets[bits & ^msbWindow].mSub(&points[i])
+				}
+			}
+		}
+	}
+
+	// reduce buckets into totals
+	// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+	totals := make([]G1Jac, len(digits))
+	for v := 0; v < len(digits); v++ {
+		vBuckets := buckets[v*nbBuckets : (v+1)*nbBuckets]
+		var runningSum, tj G1Jac
+		runningSum.Set(&g1Infinity)
+		totals[v].Set(&g1Infinity)
+		for k := len(vBuckets) - 1; k >= 0; k-- {
+			if !vBuckets[k].ZZ.IsZero() {
+				runningSum.AddAssign(tj.unsafeFromJacExtended(&vBuckets[k]))
+			}
+			totals[v].AddAssign(&runningSum)
+		}
+	}
+	return totals
+}
+
+// MultiExpReaderAt computes the multi exponentiation of the len(scalars) points read from r by scalars
+// (NOT in Montgomery form), without holding all the points in memory
+//
+// r holds the raw encoded points (see RawBytes), contiguous from offset 0: for example a file written by
+// an Encoder with the RawEncoding option, past the 4 bytes length prefix (see io.NewSectionReader), or a
+// memory-mapped file (see bytes.NewReader). The points are not checked: r must be a trusted input.
+//
+// The points are processed in segments, the next segment being read while the current one is processed;
+// the memory used for the points and the scalars (not counting the scalars argument) stays below memoryBudget bytes.
+// p is modified only if no error is returned
+func (p *G1Jac) MultiExpReaderAt(ctx context.Context, r io.ReaderAt, scalars []fr.Element, memoryBudget int, opts ...*MultiExpOptions) (*G1Jac, error) {
+	var opt *MultiExpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewMultiExpOptions(runtime.NumCPU())
+	}
+
+	// per point: the raw bytes read, 2 decoded points (the current segment and the next one), and a partitioned scalar
+	const pointSize = SizeOfG1AffineUncompressed
+	const memoryPerPoint = 3*pointSize + SizeOfFr
+	segmentSize := memoryBudget / memoryPerPoint
+	if segmentSize < 1 {
+		return nil, errors.New("memory budget is too small")
+	}
+	nbPoints := len(scalars)
+	if segmentSize > nbPoints {
+		segmentSize = nbPoints
+	}
+	nbSegments := 0
+	if nbPoints > 0 {
+		nbSegments = (nbPoints + segmentSize - 1) / segmentSize
+	}
+
+	type segment struct {
+		points []G1Affine
+		err    error
+	}
+	chSegments := make(chan segment)
+	done := make(chan struct{})
+	defer close(done)
+
+	// the segment k is decoded in segments[k%2]; it is written only after the segment k-1 is processed
+	go func() {
+		buf := make([]byte, segmentSize*pointSize)
+		var segments [2][]G1Affine
+		for k := 0; k < nbSegments; k++ {
+			start := k * segmentSize
+			n := segmentSize
+			if start+n > nbPoints {
+				n = nbPoints - start
+			}
+			if segments[k%2] == nil {
+				segments[k%2] = make([]G1Affine, segmentSize)
+			}
+			points := segments[k%2][:n]
+
+			read, err := r.ReadAt(buf[:n*pointSize], int64(start)*pointSize)
+			if err == io.EOF && read == n*pointSize {
+				err = nil
+			}
+			if err == nil {
+				err = decodeParallel(n, func(i int) (int, error) {
+					return points[i].setBytesNoChecks(buf[i*pointSize : (i+1)*pointSize])
+				}, pointSize)
+			}
+
+			select {
+			case chSegments <- segment{points, err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var res, partial G1Jac
+	res.Set(&g1Infinity)
+	for k := 0; k < nbSegments; k++ {
+		seg := <-chSegments
+		if seg.err != nil {
+			return nil, seg.err
+		}
+		start := k * segmentSize
+		if _, err := partial.multiExp(ctx, seg.points, scalars[start:start+len(seg.points)], opt, fr.Limbs*64); err != nil {
+			return nil, err
+		}
+		res.AddAssign(&partial)
+	}
+	p.Set(&res)
+	return p, nil
 }
 
 // msmReduceChunkG1 reduces the weighted sum of the buckets into the result of the multiExp
@@ -172,29 +611,24 @@ func msmProcessChunkG1(chunk uint64,
 	buckets []g1JacExtended,
 	c uint64,
 	points []G1Affine,
-	scalars []fr.Element) {
+	scalars []fr.Element,
+	done <-chan struct{}) {
 
-	mask := uint64((1 << c) - 1) // low c bits are 1
 	msbWindow := uint64(1 << (c - 1))
 
 	for i := 0; i < len(buckets); i++ {
 		buckets[i].setInfinity()
 	}
 
-	jc := uint64(chunk * c)
-	s := selector{}
-	s.index = jc / 64
-	s.shift = jc - (s.index * 64)
-	s.mask = mask << s.shift
-	s.multiWordSelect = (64%c) != 0 && s.shift > (64-c) && s.index < (fr.Limbs-1)
-	if s.multiWordSelect {
-		nbBitsHigh := s.shift - uint64(64-c)
-		s.maskHigh = (1 << nbBitsHigh) - 1
-		s.shiftHigh = (c - nbBitsHigh)
-	}
+	s := newChunkSelector(chunk, c)
 
 	// for each scalars, get the digit corresponding to the chunk we're processing.
 	for i := 0; i < len(scalars); i++ {
+		if i&msmCancelCheckMask == 0 && isDone(done) {
+			chRes <- g1Infinity
+			close(chRes)
+			return
+		}
 		bits := (scalars[i][s.index] & s.mask) >> s.shift
 		if s.multiWordSelect {
 			bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
@@ -231,17 +665,253 @@ func msmProcessChunkG1(chunk uint64,
 	close(chRes)
 }
 
-func (p *G1Jac) msmC4(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+// msmProcessChunkG1BatchAffine does the same as msmProcessChunkG1, with buckets in affine coordinates
+//
+// Additions into the buckets are batched: the slopes of up to batchSize additions share a single
+// field inversion (Montgomery's trick). A bucket appears at most once in a batch; points for a
+// bucket already in the current batch are queued, and scheduled once when the batch is executed.
+// The queue holds at most batchSize points: when it is full, or when the bucket of a queued point
+// is in the batch again, the point is added to a second set of buckets in extended Jacobian
+// coordinates instead. This bounds the work per point when the digits are skewed (e.g. all the
+// scalars are equal), where most points target the same few buckets.
+func msmProcessChunkG1BatchAffine(chunk uint64,
+	chRes chan<- G1Jac,
+	nbBuckets int,
+	c uint64,
+	points []G1Affine,
+	scalars []fr.Element,
+	done <-chan struct{}) {
+
+	if nbBuckets < batchAffineMinBuckets {
+		// batches would be too small to amortize the inversion (this happens for the last, smaller, window)
+		buckets := make([]g1JacExtended, nbBuckets)
+		msmProcessChunkG1(chunk, chRes, buckets, c, points, scalars, done)
+		return
+	}
+
+	msbWindow := uint64(1 << (c - 1))
+	s := newChunkSelector(chunk, c)
+
+	// buckets are initialized to the infinity symbol (0, 0)
+	buckets := make([]G1Affine, nbBuckets)
+
+	batchSize := nbBuckets / batchAffineBucketsPerAdd
+	if batchSize > batchAffineMaxSize {
+		batchSize = batchAffineMaxSize
+	}
+
+	inBatch := make([]bool, nbBuckets)
+	bucketIDs := make([]uint32, 0, batchSize)
+	toAdd := make([]G1Affine, 0, batchSize)
+	scratch := make([]fp.Element, batchSize)
+
+	type queuedAdd struct {
+		bucketID uint32
+		point    G1Affine
+	}
+	queue := make([]queuedAdd, 0, batchSize)
+
+	// overflow buckets, in extended Jacobian coordinates, receive the conflicting points that don't
+	// fit in the queue, and the doublings
+	var overflow []g1JacExtended
+	addOverflow := func(id uint32, q *G1Affine) {
+		if overflow == nil {
+			overflow = make([]g1JacExtended, nbBuckets)
+			for i := 0; i < len(overflow); i++ {
+				overflow[i].setInfinity()
+			}
+		}
+		overflow[id].mAdd(q)
+	}
+
+	executeBatch := func() {
+		batchAddG1Affine(buckets, bucketIDs, toAdd, scratch)
+		for _, id := range bucketIDs {
+			inBatch[id] = false
+		}
+		bucketIDs = bucketIDs[:0]
+		toAdd = toAdd[:0]
+	}
+
+	// scheduleAdd adds q to bucket id, or schedules it in the current batch.
+	// the bucket must not be in the current batch
+	scheduleAdd := func(id uint32, q *G1Affine) {
+		bucket := &buckets[id]
+		if bucket.X.IsZero() && bucket.Y.IsZero() {
+			*bucket = *q
+			return
+		}
+		if bucket.X.Equal(&q.X) {
+			if bucket.Y.Equal(&q.Y) {
+				// doubling, which the affine batch doesn't handle
+				addOverflow(id, q)
+			} else {
+				// q == -bucket
+				*bucket = G1Affine{}
+			}
+			return
+		}
+		inBatch[id] = true
+		bucketIDs = append(bucketIDs, id)
+		toAdd = append(toAdd, *q)
+		if len(bucketIDs) == batchSize {
+			executeBatch()
+		}
+	}
+
+	// processQueue empties the queue once a batch was executed: the queued additions are scheduled,
+	// or sent to the overflow buckets if their bucket is in the current batch again
+	processQueue := func() {
+		for i := 0; i < len(queue); i++ {
+			if inBatch[queue[i].bucketID] {
+				addOverflow(queue[i].bucketID, &queue[i].point)
+				continue
+			}
+			scheduleAdd(queue[i].bucketID, &queue[i].point)
+		}
+		queue = queue[:0]
+	}
+
+	// for each scalars, get the digit corresponding to the chunk we're processing.
+	for i := 0; i < len(scalars); i++ {
+		if i&msmCancelCheckMask == 0 && isDone(done) {
+			chRes <- g1Infinity
+			close(chRes)
+			return
+		}
+		bits := (scalars[i][s.index] & s.mask) >> s.shift
+		if s.multiWordSelect {
+			bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
+		}
+
+		if bits == 0 || (points[i].X.IsZero() && points[i].Y.IsZero()) {
+			continue
+		}
+
+		var id uint32
+		q := points[i]
+		// if msbWindow bit is set, we need to substract
+		if bits&msbWindow == 0 {
+			id = uint32(bits - 1)
+		} else {
+			id = uint32(bits & ^msbWindow)
+			q.Neg(&q)
+		}
+
+		if inBatch[id] {
+			if len(queue) == cap(queue) {
+				addOverflow(id, &q)
+			} else {
+				queue = append(queue, queuedAdd{id, q})
+			}
+			continue
+		}
+		scheduleAdd(id, &q)
+		if len(bucketIDs) == 0 && len(queue) != 0 {
+			// a batch was just executed
+			processQueue()
+		}
+	}
+
+	// flush the batches and the queue: processQueue empties the queue, so this runs at most twice
+	for len(bucketIDs) != 0 || len(queue) != 0 {
+		executeBatch()
+		processQueue()
+	}
+
+	// reduce buckets into total
+	// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+
+	var runningSum, tj, total G1Jac
+	runningSum.Set(&g1Infinity)
+	total.Set(&g1Infinity)
+	for k := len(buckets) - 1; k >= 0; k-- {
+		runningSum.AddMixed(&buckets[k])
+		if overflow != nil && !overflow[k].ZZ.IsZero() {
+			runningSum.AddAssign(tj.unsafeFromJacExtended(&overflow[k]))
+		}
+		total.AddAssign(&runningSum)
+	}
+
+	chRes <- total
+	close(chRes)
+}
+
+// batchAddG1Affine sets buckets[ids[i]] += points[i] for all i, with a single field inversion
+//
+// the buckets must be distinct, and the additions must not be doublings or involve the infinity
+// scratch must be at least len(ids) long
+func batchAddG1Affine(buckets []G1Affine, ids []uint32, points []G1Affine, scratch []fp.Element) {
+	if len(ids) == 0 {
+		return
+	}
+
+	// scratch[i] = prod_{j<i} (points[j].X - buckets[ids[j]].X)
+	var acc, d fp.Element
+	acc.SetOne()
+	for i := 0; i < len(ids); i++ {
+		scratch[i] = acc
+		d.Sub(&points[i].X, &buckets[ids[i]].X)
+		acc.Mul(&acc, &d)
+	}
+
+	acc.Inverse(&acc)
+
+	var lambda, t fp.Element
+	for i := len(ids) - 1; i >= 0; i-- {
+		r := &buckets[ids[i]]
+
+		// acc = 1 / prod_{j<=i} d_j so 1 / d_i = acc * scratch[i]
+		d.Sub(&points[i].X, &r.X)
+		lambda.Mul(&acc, &scratch[i])
+		acc.Mul(&acc, &d)
+
+		// lambda = (y2 - y1) / (x2 - x1)
+		t.Sub(&points[i].Y, &r.Y)
+		lambda.Mul(&lambda, &t)
+
+		// x3 = lambda**2 - x1 - x2
+		t.Square(&lambda).
+			Sub(&t, &r.X).
+			Sub(&t, &points[i].X)
+
+		// y3 = lambda * (x1 - x3) - y1
+		d.Sub(&r.X, &t)
+		d.Mul(&lambda, &d)
+		r.Y.Sub(&d, &r.Y)
+		r.X = t
+	}
+}
+
+func (p *G1Jac) msmC4(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 4                          // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
-	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
+
+	for chunk := start; chunk >= 0; chunk-- {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -250,27 +920,56 @@ func (p *G1Jac) msmC4(points []G1Affine, scalars []fr.Element, opt *MultiExpOpti
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC5(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC5(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 5                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -279,27 +978,56 @@ func (p *G1Jac) msmC5(points []G1Affine, scalars []fr.Element, opt *MultiExpOpti
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC6(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC6(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 6                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -308,27 +1036,56 @@ func (p *G1Jac) msmC6(points []G1Affine, scalars []fr.Element, opt *MultiExpOpti
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC7(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC7(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 7                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -337,17 +1094,35 @@ func (p *G1Jac) msmC7(points []G1Affine, scalars []fr.Element, opt *MultiExpOpti
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC8(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC8(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 8                          // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
-	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
+
+	for chunk := start; chunk >= 0; chunk-- {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -356,27 +1131,56 @@ func (p *G1Jac) msmC8(points []G1Affine, scalars []fr.Element, opt *MultiExpOpti
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC9(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC9(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 9                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -385,27 +1189,56 @@ func (p *G1Jac) msmC9(points []G1Affine, scalars []fr.Element, opt *MultiExpOpti
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC10(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC10(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 10                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -414,27 +1247,56 @@ func (p *G1Jac) msmC10(points []G1Affine, scalars []fr.Element, opt *MultiExpOpt
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC11(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC11(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 11                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -443,27 +1305,56 @@ func (p *G1Jac) msmC11(points []G1Affine, scalars []fr.Element, opt *MultiExpOpt
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC12(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC12(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 12                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -472,27 +1363,56 @@ func (p *G1Jac) msmC12(points []G1Affine, scalars []fr.Element, opt *MultiExpOpt
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC13(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC13(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 13                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -501,27 +1421,56 @@ func (p *G1Jac) msmC13(points []G1Affine, scalars []fr.Element, opt *MultiExpOpt
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC14(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC14(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 14                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -530,27 +1479,56 @@ func (p *G1Jac) msmC14(points []G1Affine, scalars []fr.Element, opt *MultiExpOpt
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC15(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC15(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 15                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -559,17 +1537,35 @@ func (p *G1Jac) msmC15(points []G1Affine, scalars []fr.Element, opt *MultiExpOpt
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC16(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC16(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 16                         // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
-	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
+
+	for chunk := start; chunk >= 0; chunk-- {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -578,27 +1574,56 @@ func (p *G1Jac) msmC16(points []G1Affine, scalars []fr.Element, opt *MultiExpOpt
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC20(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC20(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 20                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -607,27 +1632,56 @@ func (p *G1Jac) msmC20(points []G1Affine, scalars []fr.Element, opt *MultiExpOpt
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC21(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC21(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 21                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -636,27 +1690,56 @@ func (p *G1Jac) msmC21(points []G1Affine, scalars []fr.Element, opt *MultiExpOpt
 	return msmReduceChunkG1(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC22(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions) *G1Jac {
+func (p *G1Jac) msmC22(points []G1Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *G1Jac {
 	const c = 22                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan G1Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan G1Jac, 1)
+		chChunks[chunk] <- g1Infinity
+	}
+	start := nbChunksUsed - 1
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan G1Jac, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan G1Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunkG1BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1 << (lastC - 1)]g1JacExtended
+					msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- g1Infinity
+		}
+		start--
+	}
 
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan G1Jac, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- g1Infinity
+			continue
+		}
 		go func(j uint64, chRes chan G1Jac, points []G1Affine, scalars []fr.Element) {
-			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunkG1BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1 << (c - 1)]g1JacExtended
+				msmProcessChunkG1(j, chRes, buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -865,3 +1948,134 @@ func (p *g1JacExtended) double(q *G1Affine) *g1JacExtended {
 
 	return p
 }
+
+// PrecomputedBasesG1 stores, for a fixed set of bases P_i, the shifted multiples 2^{k*shift}·P_i
+// for k < factor and shift = ceil(fr.Bits / factor)
+//
+// a multi-exponentiation over the P_i then reduces to a multi-exponentiation over factor times
+// more points, with shift-bit scalars: it needs factor times less windows and doublings
+type PrecomputedBasesG1 struct {
+	factor int
+	points []G1Affine // points[k*n+i] = 2^{k*shift}·P_i
+}
+
+// NewPrecomputedBasesG1 returns the precomputed multiples of points, for a precomputation
+// factor in [1, fr.Bits]; memory usage is factor times the size of points
+func NewPrecomputedBasesG1(points []G1Affine, factor int) (*PrecomputedBasesG1, error) {
+	if factor < 1 || factor > fr.Bits {
+		return nil, errors.New("invalid precomputation factor")
+	}
+	n := len(points)
+	shift := (fr.Bits + factor - 1) / factor
+
+	jac := make([]G1Jac, n*factor)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			var acc G1Jac
+			acc.FromAffine(&points[i])
+			jac[i] = acc
+			for k := 1; k < factor; k++ {
+				for j := 0; j < shift; j++ {
+					acc.DoubleAssign()
+				}
+				jac[k*n+i] = acc
+			}
+		}
+	})
+
+	res := &PrecomputedBasesG1{factor: factor, points: make([]G1Affine, n*factor)}
+	BatchJacobianToAffineG1(jac, res.points)
+	return res, nil
+}
+
+// Len returns the number of bases P_i
+func (pb *PrecomputedBasesG1) Len() int {
+	return len(pb.points) / pb.factor
+}
+
+// Factor returns the precomputation factor
+func (pb *PrecomputedBasesG1) Factor() int {
+	return pb.factor
+}
+
+// MultiExpPrecomputed computes Σ scalars[i]·P_i over the precomputed bases P_i
+// the scalars are NOT in Montgomery form; it returns an error if there isn't one scalar per base.
+// p is modified only if no error is returned
+func (p *G1Jac) MultiExpPrecomputed(bases *PrecomputedBasesG1, scalars []fr.Element, opts ...*MultiExpOptions) (*G1Jac, error) {
+	if bases == nil || bases.factor < 1 {
+		return nil, errors.New("invalid precomputed bases")
+	}
+	n := bases.Len()
+	if len(scalars) != n {
+		return nil, errors.New("number of scalars must match the number of precomputed bases")
+	}
+	var opt *MultiExpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewMultiExpOptions(runtime.NumCPU())
+	}
+
+	// split each scalar in factor shift-bit scalars, the k-th one multiplying 2^{k*shift}·P_i
+	shift := (fr.Bits + bases.factor - 1) / bases.factor
+	digits := make([]fr.Element, len(bases.points))
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			for k := 0; k < bases.factor; k++ {
+				digits[k*n+i] = scalarBits(&scalars[i], k*shift, shift)
+			}
+		}
+	})
+
+	return p.multiExp(context.Background(), bases.points, digits, opt, shift)
+}
+
+// WriteTo writes the precomputed bases to w: the factor as a big-endian uint32, followed
+// by the compressed points (see Encoder)
+func (pb *PrecomputedBasesG1) WriteTo(w io.Writer) (int64, error) {
+	return pb.writeTo(w)
+}
+
+// WriteRawTo writes the precomputed bases to w like WriteTo, with uncompressed points (see RawEncoding).
+// The encoding is twice as large, but ReadFrom doesn't need to decompress the points: it is much
+// faster to read
+func (pb *PrecomputedBasesG1) WriteRawTo(w io.Writer) (int64, error) {
+	return pb.writeTo(w, RawEncoding())
+}
+
+func (pb *PrecomputedBasesG1) writeTo(w io.Writer, options ...func(*Encoder)) (int64, error) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(pb.factor))
+	n, err := w.Write(buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	enc := NewEncoder(w, options...)
+	err = enc.Encode(pb.points)
+	return int64(n) + enc.BytesWritten(), err
+}
+
+// ReadFrom reads precomputed bases written by WriteTo or WriteRawTo
+// the points are not checked (see NoChecks): r must be a trusted input
+func (pb *PrecomputedBasesG1) ReadFrom(r io.Reader) (int64, error) {
+	var buf [4]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	factor := int(binary.BigEndian.Uint32(buf[:]))
+	if factor < 1 || factor > fr.Bits {
+		return int64(n), errors.New("invalid encoding: invalid precomputation factor")
+	}
+	var points []G1Affine
+	dec := NewDecoder(r, NoChecks())
+	err = dec.Decode(&points)
+	if err != nil {
+		return int64(n) + dec.BytesRead(), err
+	}
+	if len(points)%factor != 0 {
+		return int64(n) + dec.BytesRead(), errors.New("invalid encoding: number of points is not a multiple of the precomputation factor")
+	}
+	pb.factor, pb.points = factor, points
+	return int64(n) + dec.BytesRead(), nil
+}
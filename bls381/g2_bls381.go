@@ -11,35 +11,25 @@
 
 package bls381
 
-// ClearCofactor maps a point in E(Fp) to E(Fp)[r]
-// cd https://pdfs.semanticscholar.org/e305/a02d91f222de4fe62d4b5689d3b03c7db0c3.pdf, 3.1
+// ClearCofactor maps a point in E(Fp2) to E(Fp2)[r]
+// it computes [h_eff]a = [x**2-x-1]a + [x-1]psi(a) + psi**2([2]a), as specified in
+// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-G.3
+// cf https://eprint.iacr.org/2017/419.pdf, 4.1
 func (p *G2Jac) ClearCofactor(a *G2Jac) *G2Jac {
 
-	var xg, xxg, xxxg, res, t G2Jac
-	xg.ScalarMultiplication(a, &xGen).Neg(&xg)
-	xxg.ScalarMultiplication(&xg, &xGen).Neg(&xxg)
-	xxxg.ScalarMultiplication(&xxg, &xGen).Neg(&xxxg)
-
-	res.Set(a).
-		Double(&res).
-		Double(&res).
-		SubAssign(&xg).
-		SubAssign(&xxg).
-		AddAssign(&xxxg)
-
-	t.Set(a).
-		Neg(&t).
-		AddAssign(&xg).
-		AddAssign(&xg).
-		SubAssign(&xxg).
-		psi(&t).
-		AddAssign(a).
-		SubAssign(&xg).
-		SubAssign(&xxg).
-		AddAssign(&xxxg).
-		psi(&t)
-
-	res.AddAssign(&t)
+	var xa, psia, res G2Jac
+
+	// x is negative, xGen stores |x|
+	xa.ScalarMultiplication(a, &xGen).Neg(&xa)
+	psia.psi(a)
+
+	// psi**2([2]a) - psi(a)
+	res.Double(a).psi(&res).psi(&res).SubAssign(&psia)
+
+	// + [x]([x]a + psi(a)) - [x]a - a
+	psia.AddAssign(&xa).ScalarMultiplication(&psia, &xGen).Neg(&psia)
+	res.AddAssign(&psia).SubAssign(&xa).SubAssign(a)
+
 	p.Set(&res)
 
 	return p
@@ -0,0 +1,443 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls381
+
+import (
+	"github.com/consensys/gurvy/bls381/fp"
+	"github.com/consensys/gurvy/utils/encoding"
+)
+
+// Hash to curve, following https://www.rfc-editor.org/rfc/rfc9380.html
+// suites BLS12381G1_XMD:SHA-256_SSWU_RO_ (HashToCurveG1) and BLS12381G1_XMD:SHA-256_SSWU_NU_ (EncodeToCurveG1)
+// suites BLS12381G2_XMD:SHA-256_SSWU_RO_ (HashToCurveG2) and BLS12381G2_XMD:SHA-256_SSWU_NU_ (EncodeToCurveG2)
+//
+// note: these functions are NOT constant time
+
+// E': y**2=x**3+A'x+B', 11-isogenous to E, on which the simplified SWU map is defined
+var sswuG1 struct {
+	A, B fp.Element
+	Z    fp.Element // non square in Fp, 11
+}
+
+// rational maps of the 11-isogeny E'->E, x=xNum/xDen, y=y*yNum/yDen (cf RFC 9380, appendix E.2)
+// the denominators are monic, their leading coefficient is omitted
+var isogenyG1 struct {
+	xNum [12]fp.Element
+	xDen [10]fp.Element
+	yNum [16]fp.Element
+	yDen [15]fp.Element
+}
+
+// Etwist': y**2=x**3+A'x+B', 3-isogenous to Etwist, on which the simplified SWU map is defined
+var sswuG2 struct {
+	A, B E2
+	Z    E2 // non square in Fp2, -(2+u)
+}
+
+// rational maps of the 3-isogeny Etwist'->Etwist (cf RFC 9380, appendix E.3)
+// the denominators are monic, their leading coefficient is omitted
+var isogenyG2 struct {
+	xNum [4]E2
+	xDen [2]E2
+	yNum [4]E2
+	yDen [3]E2
+}
+
+// HashToCurveG1 hashes msg to a point in G1, with the domain separation tag dst
+// the output distribution is indistinguishable from uniform (random oracle)
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-3
+func HashToCurveG1(msg, dst []byte) (G1Affine, error) {
+	var res G1Affine
+	u, err := hashToFp(msg, dst, 2)
+	if err != nil {
+		return res, err
+	}
+	var q0, q1 G1Jac
+	q0.mapToCurveG1(&u[0])
+	q1.mapToCurveG1(&u[1])
+	q0.AddAssign(&q1).ClearCofactor(&q0)
+	res.FromJacobian(&q0)
+	return res, nil
+}
+
+// EncodeToCurveG1 maps msg to a point in G1, with the domain separation tag dst
+// it is faster than HashToCurveG1 but the output distribution is not uniform (nonuniform encoding)
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-3
+func EncodeToCurveG1(msg, dst []byte) (G1Affine, error) {
+	var res G1Affine
+	u, err := hashToFp(msg, dst, 1)
+	if err != nil {
+		return res, err
+	}
+	var q G1Jac
+	q.mapToCurveG1(&u[0]).ClearCofactor(&q)
+	res.FromJacobian(&q)
+	return res, nil
+}
+
+// HashToCurveG2 hashes msg to a point in G2, with the domain separation tag dst
+// the output distribution is indistinguishable from uniform (random oracle)
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-3
+func HashToCurveG2(msg, dst []byte) (G2Affine, error) {
+	var res G2Affine
+	u, err := hashToE2(msg, dst, 2)
+	if err != nil {
+		return res, err
+	}
+	var q0, q1 G2Jac
+	q0.mapToCurveG2(&u[0])
+	q1.mapToCurveG2(&u[1])
+	q0.AddAssign(&q1).ClearCofactor(&q0)
+	res.FromJacobian(&q0)
+	return res, nil
+}
+
+// EncodeToCurveG2 maps msg to a point in G2, with the domain separation tag dst
+// it is faster than HashToCurveG2 but the output distribution is not uniform (nonuniform encoding)
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-3
+func EncodeToCurveG2(msg, dst []byte) (G2Affine, error) {
+	var res G2Affine
+	u, err := hashToE2(msg, dst, 1)
+	if err != nil {
+		return res, err
+	}
+	var q G2Jac
+	q.mapToCurveG2(&u[0]).ClearCofactor(&q)
+	res.FromJacobian(&q)
+	return res, nil
+}
+
+// hashToFp hashes msg to count elements of Fp
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.2
+func hashToFp(msg, dst []byte, count int) ([]fp.Element, error) {
+	// L = ceil((ceil(log2(p)) + k) / 8), k=128 the security level
+	const L = 64
+	uniformBytes, err := encoding.ExpandMsgXmd(msg, dst, count*L)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]fp.Element, count)
+	for i := 0; i < count; i++ {
+		res[i].SetBytes(uniformBytes[i*L : (i+1)*L])
+	}
+	return res, nil
+}
+
+// hashToE2 hashes msg to count elements of Fp2
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.2
+func hashToE2(msg, dst []byte, count int) ([]E2, error) {
+	// L = ceil((ceil(log2(p)) + k) / 8), k=128 the security level
+	const L = 64
+	uniformBytes, err := encoding.ExpandMsgXmd(msg, dst, count*2*L)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]E2, count)
+	for i := 0; i < count; i++ {
+		res[i].A0.SetBytes(uniformBytes[2*i*L : (2*i+1)*L])
+		res[i].A1.SetBytes(uniformBytes[(2*i+1)*L : (2*i+2)*L])
+	}
+	return res, nil
+}
+
+// mapToCurveG1 sets p to the image in E of u by the simplified SWU map to E' composed with the 11-isogeny
+// the result is not in G1, the cofactor must be cleared
+func (p *G1Jac) mapToCurveG1(u *fp.Element) *G1Jac {
+	var x, y fp.Element
+	sswuMapG1(&x, &y, u)
+	return p.isogenyG1(&x, &y)
+}
+
+// sswuMapG1 sets (x, y) to the image of u by the simplified SWU map to E'
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-6.6.2
+func sswuMapG1(x, y, u *fp.Element) {
+	var tv1, tv2, x1, gx fp.Element
+
+	// tv1 = 1 / (Z**2 * u**4 + Z * u**2)
+	tv2.Square(u).Mul(&tv2, &sswuG1.Z) // Z * u**2
+	tv1.Square(&tv2).Add(&tv1, &tv2)
+
+	if tv1.IsZero() {
+		// x1 = B / (Z * A)
+		x1.Mul(&sswuG1.Z, &sswuG1.A).Inverse(&x1).Mul(&x1, &sswuG1.B)
+	} else {
+		// x1 = (-B / A) * (1 + tv1)
+		var one fp.Element
+		one.SetOne()
+		tv1.Inverse(&tv1).Add(&tv1, &one)
+		x1.Inverse(&sswuG1.A).Mul(&x1, &sswuG1.B).Neg(&x1).Mul(&x1, &tv1)
+	}
+
+	// gx1 = x1**3 + A * x1 + B
+	sswuCurveEquationG1(&gx, &x1)
+	if y.Sqrt(&gx) != nil {
+		x.Set(&x1)
+	} else {
+		// x2 = Z * u**2 * x1, gx2 = x2**3 + A * x2 + B is a square
+		x.Mul(&tv2, &x1)
+		sswuCurveEquationG1(&gx, x)
+		y.Sqrt(&gx)
+	}
+
+	// sgn0(u) == sgn0(y)
+	if sgn0(u) != sgn0(y) {
+		y.Neg(y)
+	}
+}
+
+// sswuCurveEquationG1 sets z to x**3 + A'x + B'
+func sswuCurveEquationG1(z, x *fp.Element) {
+	var tv fp.Element
+	tv.Mul(x, &sswuG1.A)
+	z.Square(x).Mul(z, x).Add(z, &tv).Add(z, &sswuG1.B)
+}
+
+// isogenyG1 sets p to the image of (x, y) in E' by the 11-isogeny E'->E
+// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-E.2
+func (p *G1Jac) isogenyG1(x, y *fp.Element) *G1Jac {
+	var xNum, xDen, yNum, yDen fp.Element
+	evalPolynomial(&xNum, isogenyG1.xNum[:], false, x)
+	evalPolynomial(&xDen, isogenyG1.xDen[:], true, x)
+	evalPolynomial(&yNum, isogenyG1.yNum[:], false, x)
+	evalPolynomial(&yDen, isogenyG1.yDen[:], true, x)
+
+	// exceptional case, the point is in the kernel of the isogeny
+	if xDen.IsZero() || yDen.IsZero() {
+		p.Set(&g1Infinity)
+		return p
+	}
+
+	// in Jacobian coordinates, with Z = xDen * yDen:
+	// X = xNum * xDen * yDen**2
+	// Y = y * yNum * xDen**3 * yDen**2
+	p.Z.Mul(&xDen, &yDen)
+	var zz fp.Element
+	zz.Square(&p.Z)
+	p.X.Mul(&xNum, &yDen).Mul(&p.X, &p.Z)
+	p.Y.Mul(y, &yNum).Mul(&p.Y, &xDen).Mul(&p.Y, &zz)
+	return p
+}
+
+// mapToCurveG2 sets p to the image in Etwist of u by the simplified SWU map to Etwist' composed with the 3-isogeny
+// the result is not in G2, the cofactor must be cleared
+func (p *G2Jac) mapToCurveG2(u *E2) *G2Jac {
+	var x, y E2
+	sswuMapG2(&x, &y, u)
+	return p.isogenyG2(&x, &y)
+}
+
+// sswuMapG2 sets (x, y) to the image of u by the simplified SWU map to Etwist'
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-6.6.2
+func sswuMapG2(x, y, u *E2) {
+	var tv1, tv2, x1, gx E2
+
+	// tv1 = 1 / (Z**2 * u**4 + Z * u**2)
+	tv2.Square(u).Mul(&tv2, &sswuG2.Z) // Z * u**2
+	tv1.Square(&tv2).Add(&tv1, &tv2)
+
+	if tv1.IsZero() {
+		// x1 = B / (Z * A)
+		x1.Mul(&sswuG2.Z, &sswuG2.A).Inverse(&x1).Mul(&x1, &sswuG2.B)
+	} else {
+		// x1 = (-B / A) * (1 + tv1)
+		var one E2
+		one.SetOne()
+		tv1.Inverse(&tv1).Add(&tv1, &one)
+		x1.Inverse(&sswuG2.A).Mul(&x1, &sswuG2.B).Neg(&x1).Mul(&x1, &tv1)
+	}
+
+	// gx1 = x1**3 + A * x1 + B
+	sswuCurveEquationG2(&gx, &x1)
+	if gx.Legendre() != -1 {
+		x.Set(&x1)
+	} else {
+		// x2 = Z * u**2 * x1, gx2 = x2**3 + A * x2 + B is a square
+		x.Mul(&tv2, &x1)
+		sswuCurveEquationG2(&gx, x)
+	}
+	y.Sqrt(&gx)
+
+	// sgn0(u) == sgn0(y)
+	if sgn0E2(u) != sgn0E2(y) {
+		y.Neg(y)
+	}
+}
+
+// sswuCurveEquationG2 sets z to x**3 + A'x + B'
+func sswuCurveEquationG2(z, x *E2) {
+	var tv E2
+	tv.Mul(x, &sswuG2.A)
+	z.Square(x).Mul(z, x).Add(z, &tv).Add(z, &sswuG2.B)
+}
+
+// isogenyG2 sets p to the image of (x, y) in Etwist' by the 3-isogeny Etwist'->Etwist
+// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-E.3
+func (p *G2Jac) isogenyG2(x, y *E2) *G2Jac {
+	var xNum, xDen, yNum, yDen E2
+	evalPolynomialE2(&xNum, isogenyG2.xNum[:], false, x)
+	evalPolynomialE2(&xDen, isogenyG2.xDen[:], true, x)
+	evalPolynomialE2(&yNum, isogenyG2.yNum[:], false, x)
+	evalPolynomialE2(&yDen, isogenyG2.yDen[:], true, x)
+
+	// exceptional case, the point is in the kernel of the isogeny
+	if xDen.IsZero() || yDen.IsZero() {
+		p.Set(&g2Infinity)
+		return p
+	}
+
+	// in Jacobian coordinates, with Z = xDen * yDen:
+	// X = xNum * xDen * yDen**2
+	// Y = y * yNum * xDen**3 * yDen**2
+	p.Z.Mul(&xDen, &yDen)
+	var zz E2
+	zz.Square(&p.Z)
+	p.X.Mul(&xNum, &yDen).Mul(&p.X, &p.Z)
+	p.Y.Mul(y, &yNum).Mul(&p.Y, &xDen).Mul(&p.Y, &zz)
+	return p
+}
+
+// evalPolynomial sets z to the evaluation at x of the polynomial whose coefficients are given
+// in ascending degree; if monic is set, the polynomial has an extra leading coefficient equal to 1
+func evalPolynomial(z *fp.Element, coefficients []fp.Element, monic bool, x *fp.Element) {
+	i := len(coefficients) - 1
+	if monic {
+		z.Add(&coefficients[i], x)
+	} else {
+		z.Set(&coefficients[i])
+	}
+	for i--; i >= 0; i-- {
+		z.Mul(z, x).Add(z, &coefficients[i])
+	}
+}
+
+// evalPolynomialE2 is the Fp2 version of evalPolynomial
+func evalPolynomialE2(z *E2, coefficients []E2, monic bool, x *E2) {
+	i := len(coefficients) - 1
+	if monic {
+		z.Add(&coefficients[i], x)
+	} else {
+		z.Set(&coefficients[i])
+	}
+	for i--; i >= 0; i-- {
+		z.Mul(z, x).Add(z, &coefficients[i])
+	}
+}
+
+// sgn0 returns the parity of x (in regular form)
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-4.1
+func sgn0(x *fp.Element) uint64 {
+	_x := x.ToRegular()
+	return _x[0] & 1
+}
+
+// sgn0E2 returns the sign of x = x0 + x1*u, that is sgn0(x0), or sgn0(x1) if x0 is zero
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-4.1
+func sgn0E2(x *E2) uint64 {
+	if x.A0.IsZero() {
+		return sgn0(&x.A1)
+	}
+	return sgn0(&x.A0)
+}
+
+func init() {
+	sswuG1.A.SetString("12190336318893619529228877361869031420615612348429846051986726275283378313155663745811710833465465981901188123677")
+	sswuG1.B.SetString("2906670324641927570491258158026293881577086121416628140204402091718288198173574630967936031029026176254968826637280")
+	sswuG1.Z.SetUint64(11)
+
+	isogenyG1.xNum[0].SetString("2712959285290305970661081772124144179193819192423276218370281158706191519995889425075952244140278856085036081760695")
+	isogenyG1.xNum[1].SetString("3564859427549639835253027846704205725951033235539816243131874237388832081954622352624080767121604606753339903542203")
+	isogenyG1.xNum[2].SetString("2051387046688339481714726479723076305756384619135044672831882917686431912682625619320120082313093891743187631791280")
+	isogenyG1.xNum[3].SetString("3612713941521031012780325893181011392520079402153354595775735142359240110423346445050803899623018402874731133626465")
+	isogenyG1.xNum[4].SetString("2247053637822768981792833880270996398470828564809439728372634811976089874056583714987807553397615562273407692740057")
+	isogenyG1.xNum[5].SetString("3415427104483187489859740871640064348492611444552862448295571438270821994900526625562705192993481400731539293415811")
+	isogenyG1.xNum[6].SetString("2067521456483432583860405634125513059912765526223015704616050604591207046392807563217109432457129564962571408764292")
+	isogenyG1.xNum[7].SetString("3650721292069012982822225637849018828271936405382082649291891245623305084633066170122780668657208923883092359301262")
+	isogenyG1.xNum[8].SetString("1239271775787030039269460763652455868148971086016832054354147730155061349388626624328773377658494412538595239256855")
+	isogenyG1.xNum[9].SetString("3479374185711034293956731583912244564891370843071137483962415222733470401948838363051960066766720884717833231600798")
+	isogenyG1.xNum[10].SetString("2492756312273161536685660027440158956721981129429869601638362407515627529461742974364729223659746272460004902959995")
+	isogenyG1.xNum[11].SetString("1058488477413994682556770863004536636444795456512795473806825292198091015005841418695586811009326456605062948114985")
+	isogenyG1.xDen[0].SetString("1353092447850172218905095041059784486169131709710991428415161466575141675351394082965234118340787683181925558786844")
+	isogenyG1.xDen[1].SetString("2822220997908397120956501031591772354860004534930174057793539372552395729721474912921980407622851861692773516917759")
+	isogenyG1.xDen[2].SetString("1717937747208385987946072944131378949849282930538642983149296304709633281382731764122371874602115081850953846504985")
+	isogenyG1.xDen[3].SetString("501624051089734157816582944025690868317536915684467868346388760435016044027032505306995281054569109955275640941784")
+	isogenyG1.xDen[4].SetString("3025903087998593826923738290305187197829899948335370692927241015584233559365859980023579293766193297662657497834014")
+	isogenyG1.xDen[5].SetString("2224140216975189437834161136818943039444741035168992629437640302964164227138031844090123490881551522278632040105125")
+	isogenyG1.xDen[6].SetString("1146414465848284837484508420047674663876992808692209238763293935905506532411661921697047880549716175045414621825594")
+	isogenyG1.xDen[7].SetString("3179090966864399634396993677377903383656908036827452986467581478509513058347781039562481806409014718357094150199902")
+	isogenyG1.xDen[8].SetString("1549317016540628014674302140786462938410429359529923207442151939696344988707002602944342203885692366490121021806145")
+	isogenyG1.xDen[9].SetString("1442797143427491432630626390066422021593505165588630398337491100088557278058060064930663878153124164818522816175370")
+	isogenyG1.yNum[0].SetString("1393399195776646641963150658816615410692049723305861307490980409834842911816308830479576739332720113414154429643571")
+	isogenyG1.yNum[1].SetString("2968610969752762946134106091152102846225411740689724909058016729455736597929366401532929068084731548131227395540630")
+	isogenyG1.yNum[2].SetString("122933100683284845219599644396874530871261396084070222155796123161881094323788483360414289333111221370374027338230")
+	isogenyG1.yNum[3].SetString("303251954782077855462083823228569901064301365507057490567314302006681283228886645653148231378803311079384246777035")
+	isogenyG1.yNum[4].SetString("1353972356724735644398279028378555627591260676383150667237975415318226973994509601413730187583692624416197017403099")
+	isogenyG1.yNum[5].SetString("3443977503653895028417260979421240655844034880950251104724609885224259484262346958661845148165419691583810082940400")
+	isogenyG1.yNum[6].SetString("718493410301850496156792713845282235942975872282052335612908458061560958159410402177452633054233549648465863759602")
+	isogenyG1.yNum[7].SetString("1466864076415884313141727877156167508644960317046160398342634861648153052436926062434809922037623519108138661903145")
+	isogenyG1.yNum[8].SetString("1536886493137106337339531461344158973554574987550750910027365237255347020572858445054025958480906372033954157667719")
+	isogenyG1.yNum[9].SetString("2171468288973248519912068884667133903101171670397991979582205855298465414047741472281361964966463442016062407908400")
+	isogenyG1.yNum[10].SetString("3915937073730221072189646057898966011292434045388986394373682715266664498392389619761133407846638689998746172899634")
+	isogenyG1.yNum[11].SetString("3802409194827407598156407709510350851173404795262202653149767739163117554648574333789388883640862266596657730112910")
+	isogenyG1.yNum[12].SetString("1707589313757812493102695021134258021969283151093981498394095062397393499601961942449581422761005023512037430861560")
+	isogenyG1.yNum[13].SetString("349697005987545415860583335313370109325490073856352967581197273584891698473628451945217286148025358795756956811571")
+	isogenyG1.yNum[14].SetString("885704436476567581377743161796735879083481447641210566405057346859953524538988296201011389016649354976986251207243")
+	isogenyG1.yNum[15].SetString("3370924952219000111210625390420697640496067348723987858345031683392215988129398381698161406651860675722373763741188")
+	isogenyG1.yDen[0].SetString("3396434800020507717552209507749485772788165484415495716688989613875369612529138640646200921379825018840894888371137")
+	isogenyG1.yDen[1].SetString("3907278185868397906991868466757978732688957419873771881240086730384895060595583602347317992689443299391009456758845")
+	isogenyG1.yDen[2].SetString("854914566454823955479427412036002165304466268547334760894270240966182605542146252771872707010378658178126128834546")
+	isogenyG1.yDen[3].SetString("3496628876382137961119423566187258795236027183112131017519536056628828830323846696121917502443333849318934945158166")
+	isogenyG1.yDen[4].SetString("1828256966233331991927609917644344011503610008134915752990581590799656305331275863706710232159635159092657073225757")
+	isogenyG1.yDen[5].SetString("1362317127649143894542621413133849052553333099883364300946623208643344298804722863920546222860227051989127113848748")
+	isogenyG1.yDen[6].SetString("3443845896188810583748698342858554856823966611538932245284665132724280883115455093457486044009395063504744802318172")
+	isogenyG1.yDen[7].SetString("3484671274283470572728732863557945897902920439975203610275006103818288159899345245633896492713412187296754791689945")
+	isogenyG1.yDen[8].SetString("3755735109429418587065437067067640634211015783636675372165599470771975919172394156249639331555277748466603540045130")
+	isogenyG1.yDen[9].SetString("3459661102222301807083870307127272890283709299202626530836335779816726101522661683404130556379097384249447658110805")
+	isogenyG1.yDen[10].SetString("742483168411032072323733249644347333168432665415341249073150659015707795549260947228694495111018381111866512337576")
+	isogenyG1.yDen[11].SetString("1662231279858095762833829698537304807741442669992646287950513237989158777254081548205552083108208170765474149568658")
+	isogenyG1.yDen[12].SetString("1668238650112823419388205992952852912407572045257706138925379268508860023191233729074751042562151098884528280913356")
+	isogenyG1.yDen[13].SetString("369162719928976119195087327055926326601627748362769544198813069133429557026740823593067700396825489145575282378487")
+	isogenyG1.yDen[14].SetString("2164195715141237148945939585099633032390257748382945597506236650132835917087090097395995817229686247227784224263055")
+
+	sswuG2.A.SetString("0", "240")
+	sswuG2.B.SetString("1012", "1012")
+	sswuG2.Z.SetString("-2", "-1")
+
+	isogenyG2.xNum[0].SetString("889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542",
+		"889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542")
+	isogenyG2.xNum[1].SetString("0",
+		"2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706522")
+	isogenyG2.xNum[2].SetString("2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706526",
+		"1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853261")
+	isogenyG2.xNum[3].SetString("3557697382419259905260257622876359250272784728834673675850718343221361467102966990615722337003569479144794908942033",
+		"0")
+	isogenyG2.xDen[0].SetString("0",
+		"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559715")
+	isogenyG2.xDen[1].SetString("12",
+		"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559775")
+	isogenyG2.yNum[0].SetString("3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558",
+		"3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558")
+	isogenyG2.yNum[1].SetString("0",
+		"889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235518")
+	isogenyG2.yNum[2].SetString("2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706524",
+		"1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853263")
+	isogenyG2.yNum[3].SetString("2816510427748580758331037284777117739799287910327449993381818688383577828123182200904113516794492504322962636245776",
+		"0")
+	isogenyG2.yDen[0].SetString("4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355",
+		"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355")
+	isogenyG2.yDen[1].SetString("0",
+		"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559571")
+	isogenyG2.yDen[2].SetString("18",
+		"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559769")
+}
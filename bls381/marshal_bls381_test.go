@@ -0,0 +1,68 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls381
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// standard generators (as in ZCash and IETF), with their compressed encoding
+const (
+	g1StdX             = "3685416753713387016781088315183077757961620795782546409894578378688607592378376318836054947676345821548104185464507"
+	g1StdCompressedHex = "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+	g2StdXA0           = "352701069587466618187139116011060144890029952792775240219908644239793785735715026873347600343865175952761926303160"
+	g2StdXA1           = "3059144344244213709971259814753781636986470325476647558659373206291635324768958432433509563104347017837885763365758"
+	g2StdCompressedHex = "93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8"
+)
+
+func TestPointsSerializationZCash(t *testing.T) {
+
+	var g1 G1Affine
+	var g2 G2Affine
+
+	// decode the standard generators and check their x coordinate
+	buf, _ := hex.DecodeString(g1StdCompressedHex)
+	if _, err := g1.SetBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if g1.X.String() != g1StdX {
+		t.Fatal("wrong x coordinate for G1 standard generator")
+	}
+	buf, _ = hex.DecodeString(g2StdCompressedHex)
+	if _, err := g2.SetBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if g2.X.A0.String() != g2StdXA0 || g2.X.A1.String() != g2StdXA1 {
+		t.Fatal("wrong x coordinate for G2 standard generator")
+	}
+
+	// encoding them again should output the same bytes
+	g1b := g1.Bytes()
+	if hex.EncodeToString(g1b[:]) != g1StdCompressedHex {
+		t.Fatal("G1 standard generator compressed encoding doesn't match ZCash encoding")
+	}
+	g2b := g2.Bytes()
+	if hex.EncodeToString(g2b[:]) != g2StdCompressedHex {
+		t.Fatal("G2 standard generator compressed encoding doesn't match ZCash encoding")
+	}
+
+	// infinity is 0xc0 followed by zeroes in compressed form
+	var inf G1Affine
+	infb := inf.Bytes()
+	if infb[0] != 0xc0 {
+		t.Fatal("point at infinity should be encoded as 0xc0||0...")
+	}
+}
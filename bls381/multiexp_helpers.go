@@ -17,6 +17,8 @@
 package bls381
 
 import (
+	"math"
+	"math/bits"
 	"sync"
 
 	"github.com/consensys/gurvy/bls381/fr"
@@ -25,11 +27,26 @@ import (
 
 // MultiExpOptions enables users to set optional parameters to the multiexp
 type MultiExpOptions struct {
-	c      uint64
 	chCpus chan struct{} // semaphore to limit number of cpus iterating through points and scalrs at the same time
 	lock   sync.Mutex
+
+	// BatchAffine enables bucket accumulation with batched affine additions (one field inversion
+	// per batch, cf Montgomery's trick) instead of extended Jacobian additions. It is faster for
+	// large inputs, and ignored below batchAffineMinPoints points.
+	BatchAffine bool
+
+	// Progress, if set, is called each time a chunk (c-bit window) of a multiExp is processed, with
+	// the number of processed chunks and the total number of chunks. Calls are serialized.
+	Progress func(done, total int)
 }
 
+const (
+	batchAffineMinPoints     = 1 << 14 // below, MultiExp falls back to extended Jacobian buckets
+	batchAffineMinBuckets    = 1 << 10 // windows with less buckets fall back to extended Jacobian buckets
+	batchAffineBucketsPerAdd = 8       // batch size is nbBuckets / batchAffineBucketsPerAdd, which limits conflicts
+	batchAffineMaxSize       = 1 << 10 // maximum number of additions in a batch
+)
+
 // NewMultiExpOptions returns a new multiExp options to be used with MultiExp
 // this option can be shared between different MultiExp calls and will ensure only numCpus are used
 // through a semaphore
@@ -55,6 +72,178 @@ type selector struct {
 	shiftHigh       uint64 // same than shift, for index+1
 }
 
+// msmParams are the parameters of a call to the msmCX methods
+type msmParams struct {
+	batchAffine bool            // accumulate the buckets with batch affine additions
+	nbBits      int             // the scalars (before partitionScalars) are smaller than 2^nbBits
+	done        <-chan struct{} // closed when the multiExp is cancelled, nil if it can't be
+	progress    *msmProgress    // nil if progress is not reported
+}
+
+// msmCancelCheckMask sets how often the msmProcessChunk functions check for cancellation (every 4096 scalars)
+const msmCancelCheckMask = (1 << 12) - 1
+
+// msmTernaryMinPointsPerTask is the minimum number of points summed by a go routine in msmTernary
+const msmTernaryMinPointsPerTask = 1 << 10
+
+// msmTernaryNbLanes is the number of accumulators of the batch affine sums in msmTernary
+const msmTernaryNbLanes = 1 << 9
+
+// frMinusOne is -1 (NOT in Montgomery form)
+var frMinusOne fr.Element
+
+func init() {
+	frMinusOne.SetOne().Neg(&frMinusOne).FromMont()
+}
+
+// scalarsBitLen returns the bit length of the largest scalar (NOT in Montgomery form),
+// and true if all the scalars are in {-1, 0, 1}
+func scalarsBitLen(scalars []fr.Element) (nbBits int, ternary bool) {
+	var or fr.Element
+	hasMinusOne := false
+	for i := 0; i < len(scalars); i++ {
+		if scalars[i] == frMinusOne {
+			hasMinusOne = true
+			continue
+		}
+		for j := 0; j < fr.Limbs; j++ {
+			or[j] |= scalars[i][j]
+		}
+	}
+	ternary = or[0] <= 1
+	for j := fr.Limbs - 1; j >= 0; j-- {
+		if or[j] != 0 {
+			nbBits = j*64 + bits.Len64(or[j])
+			ternary = ternary && j == 0
+			break
+		}
+	}
+	if hasMinusOne && !ternary {
+		nbBits = fr.Bits
+	}
+	return
+}
+
+// msmManyBlockSize is the number of points read from memory once for all the vectors in MultiExpMany
+const msmManyBlockSize = 1 << 10
+
+// msmManyMaxBuckets bounds the number of buckets (for all the vectors) of a window in MultiExpMany
+const msmManyMaxBuckets = 1 << 17
+
+// msmManyBestC returns the window size minimizing the approximate cost (in group operations)
+// of MultiExpMany, with less than msmManyMaxBuckets buckets per window
+func msmManyBestC(nbPoints, nbVectors, nbBits int) uint64 {
+	var bestC uint64 = 4
+	min := math.MaxFloat64
+	for c := 4; c <= 16; c++ {
+		if c > 4 && nbVectors<<(c-1) > msmManyMaxBuckets {
+			break
+		}
+		nbChunks := (nbBits+c-1)/c + 1
+		cost := float64(nbChunks) * float64(nbVectors) * float64(nbPoints+(1<<(c-1)))
+		if cost < min {
+			min = cost
+			bestC = uint64(c)
+		}
+	}
+	return bestC
+}
+
+// acquireCpu waits for a token of the cpu semaphore, and returns false without taking one if done
+// is closed first: a cancelled multiExp doesn't wait for the tokens held by other calls
+func (opt *MultiExpOptions) acquireCpu(done <-chan struct{}) bool {
+	if isDone(done) {
+		return false
+	}
+	select {
+	case <-opt.chCpus:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// isDone returns true if done is closed; a nil channel is never closed
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// msmProgress counts the processed chunks of a multiExp, and reports them to MultiExpOptions.Progress
+type msmProgress struct {
+	lock     sync.Mutex
+	done     int
+	callback func(done, total int)
+}
+
+func newMsmProgress(callback func(done, total int)) *msmProgress {
+	if callback == nil {
+		return nil
+	}
+	return &msmProgress{callback: callback}
+}
+
+// chunkDone reports that one more of the total chunks is processed
+func (pr *msmProgress) chunkDone(total int) {
+	if pr == nil {
+		return
+	}
+	pr.lock.Lock()
+	pr.done++
+	pr.callback(pr.done, total)
+	pr.lock.Unlock()
+}
+
+// nbChunks returns the number of c-bit windows holding non-zero digits, at most max
+func (params msmParams) nbChunks(c, max int) int {
+	// partitionScalars may propagate a carry in the window above the last bit
+	n := (params.nbBits+c-1)/c + 1
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// newChunkSelector returns the selector of the c-bit window at index chunk
+func newChunkSelector(chunk, c uint64) selector {
+	mask := uint64((1 << c) - 1) // low c bits are 1
+	jc := uint64(chunk * c)
+	s := selector{}
+	s.index = jc / 64
+	s.shift = jc - (s.index * 64)
+	s.mask = mask << s.shift
+	s.multiWordSelect = (64%c) != 0 && s.shift > (64-c) && s.index < (fr.Limbs-1)
+	if s.multiWordSelect {
+		nbBitsHigh := s.shift - uint64(64-c)
+		s.maskHigh = (1 << nbBitsHigh) - 1
+		s.shiftHigh = (c - nbBitsHigh)
+	}
+	return s
+}
+
+// scalarBits returns the n bits of s starting at bit from, as a regular form fr.Element
+// n must be at most fr.Bits
+func scalarBits(s *fr.Element, from, n int) (res fr.Element) {
+	for j := 0; j*64 < n; j++ {
+		pos := from + j*64
+		w, off := pos/64, uint(pos%64)
+		if w < fr.Limbs {
+			res[j] = s[w] >> off
+		}
+		if off != 0 && w+1 < fr.Limbs {
+			res[j] |= s[w+1] << (64 - off)
+		}
+	}
+	if r := n % 64; r != 0 {
+		res[(n-1)/64] &= (uint64(1) << uint(r)) - 1
+	}
+	return
+}
+
 // partitionScalars  compute, for each scalars over c-bit wide windows, nbChunk digits
 // if the digit is larger than 2^{c-1}, then, we borrow 2^c from the next window and substract
 // 2^{c} to the current digit, making it negative.
@@ -15,16 +15,21 @@
 package bls381
 
 import (
+	"errors"
+	"math/big"
 	"math/bits"
+
+	"github.com/consensys/gurvy/bls381/fr"
+	"github.com/consensys/gurvy/utils"
 )
 
 // GT target group of the pairing
-type GT = e12
+type GT = E12
 
 type lineEvaluation struct {
-	r0 e2
-	r1 e2
-	r2 e2
+	r0 E2
+	r1 E2
+	r2 E2
 }
 
 // FinalExponentiation computes the final expo x**(p**6-1)(p**2+1)(p**4 - p**2 +1)/r
@@ -42,6 +47,15 @@ func FinalExponentiation(z *GT, _z ...*GT) GT {
 	return result
 }
 
+// FinalExponentiationCompressed computes the final expo x**(p**6-1)(p**2+1)(p**4 - p**2 +1)/r
+// and returns the result in its compressed T2 torus form, half the size of a GT element (see E12.CompressTorus)
+// It is a convenience wrapper around FinalExponentiation: the exponentiation itself runs on
+// uncompressed elements and costs the same, only the result is compressed.
+func FinalExponentiationCompressed(z *GT, _z ...*GT) (E6, error) {
+	result := FinalExponentiation(z, _z...)
+	return result.CompressTorus()
+}
+
 // FinalExponentiation sets z to the final expo x**((p**12 - 1)/r), returns z
 func (z *GT) FinalExponentiation(x *GT) *GT {
 
@@ -95,42 +109,132 @@ func (z *GT) FinalExponentiation(x *GT) *GT {
 	return z
 }
 
-// MillerLoop Miller loop
-func MillerLoop(P G1Affine, Q G2Affine) *GT {
+// Pair calculates the reduced pairing for a set of points
+// ∏ᵢ e(Pᵢ, Qᵢ)
+// pairs with a point at infinity are skipped, P and Q must have the same length (the empty product is 1)
+func Pair(P []G1Affine, Q []G2Affine) (GT, error) {
+	f, err := MillerLoop(P, Q)
+	if err != nil {
+		return GT{}, err
+	}
+	return FinalExponentiation(&f), nil
+}
+
+// PairingCheck calculates the reduced pairing for a set of points and returns True if the result is One
+// ∏ᵢ e(Pᵢ, Qᵢ) =? 1
+// pairs with a point at infinity are skipped, P and Q must have the same length (the empty product is 1)
+func PairingCheck(P []G1Affine, Q []G2Affine) (bool, error) {
+	f, err := Pair(P, Q)
+	if err != nil {
+		return false, err
+	}
+	var one GT
+	one.SetOne()
+	return f.Equal(&one), nil
+}
+
+// MillerLoop computes the multi-Miller loop ∏ᵢ MillerLoop(Pᵢ, Qᵢ)
+// the squarings of the accumulator are shared between the pairs, pairs with a point at infinity are skipped
+func MillerLoop(P []G1Affine, Q []G2Affine) (GT, error) {
+	if len(P) != len(Q) {
+		var one GT
+		one.SetOne()
+		return one, errors.New("invalid inputs sizes")
+	}
+	q := make([]PreparedG2, len(Q))
+	for k := 0; k < len(Q); k++ {
+		q[k] = PrepareG2(&Q[k])
+	}
+	return MillerLoopPrepared(P, q)
+}
+
+// PreparedG2 stores the coefficients of the lines computed during the Miller loop of a G2 point.
+// They don't depend on the G1 point and can be evaluated later at any P, which saves the G2
+// arithmetic when the same Q is used in several pairings (e.g. a verifying key)
+type PreparedG2 struct {
+	lines []lineEvaluation // nil for the point at infinity
+}
+
+// PrepareG2 computes the line coefficients of the Miller loop of Q, to be used in MillerLoopPrepared
+func PrepareG2(Q *G2Affine) PreparedG2 {
+	var res PreparedG2
+	if Q.IsInfinity() {
+		return res
+	}
+
+	var qJac, qAcc, qDbl G2Jac
+	var l lineEvaluation
+	qJac.FromAffine(Q)
+	qAcc.Set(&qJac)
+
+	for i := len(loopCounter) - 2; i >= 0; i-- {
+
+		qDbl.Double(&qAcc).Neg(&qDbl)
+		lineCoefficients(&qAcc, &qDbl, &l) // div(f) = 2(Q)+(-2Q)-3(O)
+		res.lines = append(res.lines, l)
+		qAcc.Neg(&qDbl)
+
+		if loopCounter[i] == 1 {
+			lineCoefficients(&qAcc, &qJac, &l) // div(f) = (Q)+(Qk)+(-Q-Qk)-3(O)
+			res.lines = append(res.lines, l)
+			qAcc.AddAssign(&qJac)
+		}
+	}
+
+	return res
+}
+
+// MillerLoopPrepared computes the multi-Miller loop ∏ᵢ MillerLoop(Pᵢ, Qᵢ), the Qᵢ being prepared with PrepareG2
+// pairs with a point at infinity are skipped
+func MillerLoopPrepared(P []G1Affine, Q []PreparedG2) (GT, error) {
 
 	var result GT
 	result.SetOne()
 
-	if P.IsInfinity() || Q.IsInfinity() {
-		return &result
+	if len(P) != len(Q) {
+		return result, errors.New("invalid inputs sizes")
 	}
 
-	ch := make(chan struct{}, 10)
-
-	var evaluations [68]lineEvaluation
-	go preCompute(&evaluations, &Q, &P, ch)
+	// filter infinity points
+	p := make([]G1Affine, 0, len(P))
+	q := make([]*PreparedG2, 0, len(Q))
+	for k := 0; k < len(P); k++ {
+		if P[k].IsInfinity() || Q[k].lines == nil {
+			continue
+		}
+		p = append(p, P[k])
+		q = append(q, &Q[k])
+	}
+	n := len(p)
 
+	var lEval lineEvaluation
 	j := 0
+
 	for i := len(loopCounter) - 2; i >= 0; i-- {
 
 		result.Square(&result)
-		<-ch
-		result.mulAssign(&evaluations[j])
+		for k := 0; k < n; k++ {
+			lEval.evaluate(&q[k].lines[j], &p[k])
+			result.mulAssign(&lEval)
+		}
 		j++
 
 		if loopCounter[i] == 1 {
-			<-ch
-			result.mulAssign(&evaluations[j])
+			for k := 0; k < n; k++ {
+				lEval.evaluate(&q[k].lines[j], &p[k])
+				result.mulAssign(&lEval)
+			}
 			j++
 		}
 	}
 
-	return &result
+	return result, nil
 }
 
-// lineEval computes the evaluation of the line through Q, R (on the twist) at P
+// lineCoefficients computes the coefficients of the line through Q, R (on the twist),
+// to be evaluated at P with lineEvaluation.evaluate
 // Q, R are in jacobian coordinates
-func lineEval(Q, R *G2Jac, P *G1Affine, result *lineEvaluation) {
+func lineCoefficients(Q, R *G2Jac, result *lineEvaluation) {
 
 	// converts _Q and _R to projective coords
 	var _Q, _R G2Proj
@@ -148,108 +252,101 @@ func lineEval(Q, R *G2Jac, P *G1Affine, result *lineEvaluation) {
 	result.r1.Sub(&result.r1, &_Q.Z)
 	result.r0.Sub(&result.r0, &_Q.X)
 	result.r2.Sub(&result.r2, &_Q.Y)
+}
 
-	result.r1.MulByElement(&result.r1, &P.X)
-	result.r0.MulByElement(&result.r0, &P.Y)
+// evaluate sets l to the evaluation at P of the line whose coefficients are c, and returns l
+func (l *lineEvaluation) evaluate(c *lineEvaluation, P *G1Affine) *lineEvaluation {
+	l.r1.MulByElement(&c.r1, &P.X)
+	l.r0.MulByElement(&c.r0, &P.Y)
+	l.r2.Set(&c.r2)
+	return l
 }
 
 // multiplies a result of a line evaluation to the current pairing result, taking care of mapping it
-// back to the original  The line evaluation l is f(P) where div(f)=(P')+(Q')+(-P'-Q')-3(O), the support
+// back to the original curve. The line evaluation l is f(P) where div(f)=(P')+(Q')+(-P'-Q')-3(O), the support
 // being on the twist.
+// On the twist the line is r1*v*w*(1,1)^{-1} + r0*v^2*(1,1)^{-1} + r2*w*(1,1)^{-1}. It is scaled by (1,1)*w^{-1},
+// which is killed by the final exponentiation, to get the sparse element r2 + r1*v + r0*v*w.
 func (z *GT) mulAssign(l *lineEvaluation) *GT {
-
-	var a, b, c GT
-	a.MulByVWNRInv(z, &l.r1)
-	b.MulByV2NRInv(z, &l.r0)
-	c.MulByWNRInv(z, &l.r2)
-	z.Add(&a, &b).Add(z, &c)
-
-	return z
+	return z.MulBy014(&l.r2, &l.r1, &l.r0)
 }
 
-// precomputes the line evaluations used during the Miller loop.
-func preCompute(evaluations *[68]lineEvaluation, Q *G2Affine, P *G1Affine, ch chan struct{}) {
-
-	var Q1, Q2, Qbuf G2Jac
-	Q1.FromAffine(Q)
-	Q2.FromAffine(Q)
-	Qbuf.FromAffine(Q)
-
-	j := 0
-
-	for i := len(loopCounter) - 2; i >= 0; i-- {
-
-		Q1.Set(&Q2)
-		Q2.Double(&Q1).Neg(&Q2)
-		lineEval(&Q1, &Q2, P, &evaluations[j]) // f(P), div(f) = 2(Q1)+(-2Q2)-3(O)
-		ch <- struct{}{}
-		Q2.Neg(&Q2)
-		j++
-
-		if loopCounter[i] == 1 {
-			lineEval(&Q2, &Qbuf, P, &evaluations[j]) // f(P), div(f) = (Q2)+(Q)+(-Q2-Q)-3(O)
-			ch <- struct{}{}
-			Q2.AddMixed(Q)
-			j++
-		}
+// IsInSubGroup returns true if z is in GT, the r-torsion of the cyclotomic subgroup of E12
+// z is first checked to be in the cyclotomic subgroup, z**(p**4-p**2+1) == 1. There, z**p == z**x
+// is equivalent to z**r == 1, as gcd(p**4-p**2+1, p-x) == r (cf https://eprint.iacr.org/2021/1130.pdf)
+// GT.SetBytes doesn't perform this check, it should be done for untrusted inputs.
+func (z *GT) IsInSubGroup() bool {
+	if z.IsZero() {
+		return false
 	}
-	close(ch)
-}
 
-// MulByV2NRInv set z to x*(y*v^2*(1,1)^{-1}) and return z
-func (z *GT) MulByV2NRInv(x *GT, y *e2) *GT {
+	// cyclotomic subgroup: Frobenius**4(z) * z == Frobenius**2(z)
+	var a, b GT
+	a.FrobeniusSquare(z)
+	b.FrobeniusSquare(&a).Mul(&b, z)
+	if !a.Equal(&b) {
+		return false
+	}
 
-	var result GT
-	var yNRInv e2
-	yNRInv.MulByNonResidueInv(y)
+	// r-torsion: Frobenius(z) == z**x
+	a.Frobenius(z)
+	b.Expt(z)
+	return a.Equal(&b)
+}
 
-	result.C0.B0.Mul(&x.C0.B1, y)
-	result.C0.B1.Mul(&x.C0.B2, y)
-	result.C0.B2.Mul(&x.C0.B0, &yNRInv)
+// ExpGLV sets z to x**k and returns z, x being in GT
+// The Frobenius acts on GT as x -> x**lambdaGT, so k is split as k1 + k2*lambdaGT with k1, k2 about half
+// the size of r (https://www.iacr.org/archive/crypto2001/21390189.pdf), and z = x**k1 * Frobenius(x)**k2
+// is computed with a shared square-and-multiply loop in the cyclotomic subgroup.
+// The result is undefined if x is not in GT, see IsInSubGroup.
+func (z *GT) ExpGLV(x *GT, k *big.Int) *GT {
+
+	var s big.Int
+	s.Mod(k, fr.Modulus())
+	if s.Sign() == 0 {
+		return z.SetOne()
+	}
 
-	result.C1.B0.Mul(&x.C1.B1, y)
-	result.C1.B1.Mul(&x.C1.B2, y)
-	result.C1.B2.Mul(&x.C1.B0, &yNRInv)
+	// table stores [x, Frobenius(x), x*Frobenius(x)], with signs matching k1, k2
+	var table [3]GT
+	table[0].Set(x)
+	table[1].Frobenius(x)
 
-	z.Set(&result)
-	return z
-}
-
-// MulByVWNRInv set z to x*(y*v*w*(1,1)^{-1}) and return z
-func (z *GT) MulByVWNRInv(x *GT, y *e2) *GT {
-	var result GT
-	var yNRInv e2
-	yNRInv.MulByNonResidueInv(y)
+	e := utils.SplitScalar(&s, &glvBasisGT)
+	if e[0].Sign() == -1 {
+		e[0].Neg(&e[0])
+		table[0].Conjugate(&table[0])
+	}
+	if e[1].Sign() == -1 {
+		e[1].Neg(&e[1])
+		table[1].Conjugate(&table[1])
+	}
+	table[2].Mul(&table[0], &table[1])
 
-	result.C0.B0.Mul(&x.C1.B1, y)
-	result.C0.B1.Mul(&x.C1.B2, y)
-	result.C0.B2.Mul(&x.C1.B0, &yNRInv)
+	var res GT
+	res.SetOne()
 
-	result.C1.B0.Mul(&x.C0.B2, y)
-	result.C1.B1.Mul(&x.C0.B0, &yNRInv)
-	result.C1.B2.Mul(&x.C0.B1, &yNRInv)
+	n := e[0].BitLen()
+	if e[1].BitLen() > n {
+		n = e[1].BitLen()
+	}
+	for i := n - 1; i >= 0; i-- {
+		res.CyclotomicSquare(&res)
+		b := e[0].Bit(i) | (e[1].Bit(i) << 1)
+		if b != 0 {
+			res.Mul(&res, &table[b-1])
+		}
+	}
 
-	z.Set(&result)
+	z.Set(&res)
 	return z
 }
 
-// MulByWNRInv set z to x*(y*w*(1,1)^{-1}) and return z
-func (z *GT) MulByWNRInv(x *GT, y *e2) *GT {
-
-	var result GT
-	var yNRInv e2
-	yNRInv.MulByNonResidueInv(y)
-
-	result.C0.B0.Mul(&x.C1.B2, y)
-	result.C0.B1.Mul(&x.C1.B0, &yNRInv)
-	result.C0.B2.Mul(&x.C1.B1, &yNRInv)
-
-	result.C1.B0.Mul(&x.C0.B0, &yNRInv)
-	result.C1.B1.Mul(&x.C0.B1, &yNRInv)
-	result.C1.B2.Mul(&x.C0.B2, &yNRInv)
-
-	z.Set(&result)
-	return z
+// ExpFr sets z to x**k and returns z, x being in GT, see ExpGLV
+func (z *GT) ExpFr(x *GT, k *fr.Element) *GT {
+	var _k big.Int
+	k.ToBigIntRegular(&_k)
+	return z.ExpGLV(x, &_k)
 }
 
 // Expt set z to x^t in GT and return z
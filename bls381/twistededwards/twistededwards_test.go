@@ -63,6 +63,44 @@ func TestAddProj(t *testing.T) {
 
 }
 
+func TestAddProjNotNormalized(t *testing.T) {
+
+	var p1, p2, expected Point
+	var p1proj, p2proj PointProj
+
+	p1.X.SetString("21793328330329971148710654283888115697962123987759099803244199498744022094670")
+	p1.Y.SetString("2101040637884652362150023747029283466236613497763786920682459476507158507058")
+
+	p2.X.SetString("50629843885093813360334764484465489653158679010834922765195739220081842003850")
+	p2.Y.SetString("39525475875082628301311747912064089490877815436253076910246067124459956047086")
+
+	expected.Add(&p1, &p2)
+
+	// the projective addition must hold for any representative (λX:λY:λZ) of the inputs
+	var l1, l2 fr.Element
+	l1.SetUint64(3)
+	l2.SetUint64(5)
+	p1proj.FromAffine(&p1)
+	p1proj.X.Mul(&p1proj.X, &l1)
+	p1proj.Y.Mul(&p1proj.Y, &l1)
+	p1proj.Z.Mul(&p1proj.Z, &l1)
+	p2proj.FromAffine(&p2)
+	p2proj.X.Mul(&p2proj.X, &l2)
+	p2proj.Y.Mul(&p2proj.Y, &l2)
+	p2proj.Z.Mul(&p2proj.Z, &l2)
+
+	p1proj.Add(&p1proj, &p2proj)
+	p1.FromProj(&p1proj)
+
+	if !p1.X.Equal(&expected.X) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p1.Y.Equal(&expected.Y) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
+
 func TestDouble(t *testing.T) {
 
 	var p Point
@@ -136,3 +174,55 @@ func TestScalarMul(t *testing.T) {
 	}
 
 }
+
+func TestScalarMulCT(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+
+	scalars := make([]fr.Element, 4, 14)
+	scalars[0].SetZero()
+	scalars[1].SetOne().FromMont()
+	scalars[2].SetUint64(23902374).FromMont()
+	scalars[3].SetOne().Neg(&scalars[3]).FromMont()
+	for i := 0; i < 10; i++ {
+		var s fr.Element
+		s.SetRandom().FromMont()
+		scalars = append(scalars, s)
+	}
+
+	for _, s := range scalars {
+		var p1, p2 Point
+		p1.ScalarMul(&ed.Base, s)
+		p2.ScalarMultiplicationCT(&ed.Base, s)
+		if !p1.X.Equal(&p2.X) || !p1.Y.Equal(&p2.Y) {
+			t.Fatal("constant time scalar multiplication doesn't match ScalarMul")
+		}
+	}
+}
+
+func TestFromProjCT(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+
+	for i := 0; i < 10; i++ {
+		var s, z fr.Element
+		s.SetRandom().FromMont()
+		z.SetRandom()
+
+		// random point, with a random (non normalized) Z
+		var p Point
+		var pProj PointProj
+		p.ScalarMul(&ed.Base, s)
+		pProj.FromAffine(&p)
+		pProj.X.Mul(&pProj.X, &z)
+		pProj.Y.Mul(&pProj.Y, &z)
+		pProj.Z.Mul(&pProj.Z, &z)
+
+		var p1, p2 Point
+		p1.FromProj(&pProj)
+		p2.fromProjCT(&pProj)
+		if !p1.X.Equal(&p2.X) || !p1.Y.Equal(&p2.Y) || !p2.X.Equal(&p.X) || !p2.Y.Equal(&p.Y) {
+			t.Fatal("fromProjCT doesn't match FromProj")
+		}
+	}
+}
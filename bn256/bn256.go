@@ -25,7 +25,7 @@ const ID = gurvy.BN256
 var bCurveCoeff fp.Element
 
 // bTwistCurveCoeff b coeff of the twist (defined over Fp2) curve
-var bTwistCurveCoeff e2
+var bTwistCurveCoeff E2
 
 // generators of the r-torsion group, resp. in ker(pi-id), ker(Tr)
 var g1Gen G1Jac
@@ -54,10 +54,14 @@ var lambdaGLV big.Int
 // in ker((u,v)->u+vlambda[r]), and their determinant
 var glvBasis utils.Lattice
 
+// lambdaGT is the eigenvalue of the Frobenius on GT (p mod r), glvBasisGT the associated lattice
+var lambdaGT big.Int
+var glvBasisGT utils.Lattice
+
 // psi o pi o psi**-1, where psi:E->E' is the degree 6 iso defined over Fp12
 var endo struct {
-	u e2
-	v e2
+	u E2
+	v E2
 }
 
 // generator of the curve
@@ -94,6 +98,8 @@ func init() {
 	lambdaGLV.SetString("4407920970296243842393367215006156084916469457145843978461", 10) // (36*x**3+18*x**2+6*x+1)
 	_r := fr.Modulus()
 	utils.PrecomputeLattice(_r, &lambdaGLV, &glvBasis)
+	lambdaGT.Mod(fp.Modulus(), _r)
+	utils.PrecomputeLattice(_r, &lambdaGT, &glvBasisGT)
 
 	endo.u.A0.SetString("21575463638280843010398324269430826099269044274347216827212613867836435027261")
 	endo.u.A1.SetString("10307601595873709700152284273816112264069230130616436755625194854815875713954")
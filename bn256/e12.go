@@ -17,82 +17,126 @@
 package bn256
 
 import (
+	"errors"
 	"math/big"
 )
 
-// e12 is a degree two finite field extension of fp6
-type e12 struct {
-	C0, C1 e6
+// SizeOfE12 is the size in bytes of an E12 element, in regular form
+const SizeOfE12 = 2 * SizeOfE6
+
+// E12 is a degree two finite field extension of fp6
+type E12 struct {
+	C0, C1 E6
 }
 
 // Equal returns true if z equals x, fasle otherwise
-func (z *e12) Equal(x *e12) bool {
+func (z *E12) Equal(x *E12) bool {
 	return z.C0.Equal(&x.C0) && z.C1.Equal(&x.C1)
 }
 
-// String puts e12 in string form
-func (z *e12) String() string {
+// String puts E12 in string form
+func (z *E12) String() string {
 	return (z.C0.String() + "+(" + z.C1.String() + ")*w")
 }
 
-// SetString sets a e12 from string
-func (z *e12) SetString(s0, s1, s2, s3, s4, s5, s6, s7, s8, s9, s10, s11 string) *e12 {
+// SetString sets a E12 from string
+func (z *E12) SetString(s0, s1, s2, s3, s4, s5, s6, s7, s8, s9, s10, s11 string) *E12 {
 	z.C0.SetString(s0, s1, s2, s3, s4, s5)
 	z.C1.SetString(s6, s7, s8, s9, s10, s11)
 	return z
 }
 
 // Set copies x into z and returns z
-func (z *e12) Set(x *e12) *e12 {
+func (z *E12) Set(x *E12) *E12 {
 	z.C0 = x.C0
 	z.C1 = x.C1
 	return z
 }
 
+// SetZero sets an E12 elmt to zero
+func (z *E12) SetZero() *E12 {
+	*z = E12{}
+	return z
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E12) IsZero() bool {
+	return z.C0.IsZero() && z.C1.IsZero()
+}
+
 // SetOne sets z to 1 in Montgomery form and returns z
-func (z *e12) SetOne() *e12 {
-	*z = e12{}
+func (z *E12) SetOne() *E12 {
+	*z = E12{}
 	z.C0.B0.A0.SetOne()
 	return z
 }
 
 // ToMont converts to Mont form
-func (z *e12) ToMont() *e12 {
+func (z *E12) ToMont() *E12 {
 	z.C0.ToMont()
 	z.C1.ToMont()
 	return z
 }
 
 // FromMont converts from Mont form
-func (z *e12) FromMont() *e12 {
+func (z *E12) FromMont() *E12 {
 	z.C0.FromMont()
 	z.C1.FromMont()
 	return z
 }
 
-// Add set z=x+y in e12 and return z
-func (z *e12) Add(x, y *e12) *e12 {
+// Bytes returns the regular (non montgomery) value of z as a big-endian byte array
+// C1 | C0, each E6 being encoded as B2 | B1 | B0
+func (z *E12) Bytes() (res [SizeOfE12]byte) {
+	b := z.C1.Bytes()
+	copy(res[:SizeOfE6], b[:])
+	b = z.C0.Bytes()
+	copy(res[SizeOfE6:], b[:])
+	return
+}
+
+// SetBytes interprets buf as the big-endian encoding C1 | C0 of an E12 element, and sets z to it
+// It returns an error if buf is not SizeOfE12 bytes long, or if a coordinate is not reduced modulo q
+func (z *E12) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfE12 {
+		return errors.New("invalid buffer size")
+	}
+	if err := z.C1.SetBytes(buf[:SizeOfE6]); err != nil {
+		return err
+	}
+	return z.C0.SetBytes(buf[SizeOfE6:])
+}
+
+// Add set z=x+y in E12 and return z
+func (z *E12) Add(x, y *E12) *E12 {
 	z.C0.Add(&x.C0, &y.C0)
 	z.C1.Add(&x.C1, &y.C1)
 	return z
 }
 
+// Neg sets z=-x in E12 and returns z
+func (z *E12) Neg(x *E12) *E12 {
+	z.C0.Neg(&x.C0)
+	z.C1.Neg(&x.C1)
+	return z
+}
+
 // Sub sets z to x sub y and return z
-func (z *e12) Sub(x, y *e12) *e12 {
+func (z *E12) Sub(x, y *E12) *E12 {
 	z.C0.Sub(&x.C0, &y.C0)
 	z.C1.Sub(&x.C1, &y.C1)
 	return z
 }
 
 // Double sets z=2*x and returns z
-func (z *e12) Double(x *e12) *e12 {
+func (z *E12) Double(x *E12) *E12 {
 	z.C0.Double(&x.C0)
 	z.C1.Double(&x.C1)
 	return z
 }
 
 // SetRandom used only in tests
-func (z *e12) SetRandom() *e12 {
+func (z *E12) SetRandom() *E12 {
 	z.C0.B0.A0.SetRandom()
 	z.C0.B0.A1.SetRandom()
 	z.C0.B1.A0.SetRandom()
@@ -108,9 +152,9 @@ func (z *e12) SetRandom() *e12 {
 	return z
 }
 
-// Mul set z=x*y in e12 and return z
-func (z *e12) Mul(x, y *e12) *e12 {
-	var a, b, c e6
+// Mul set z=x*y in E12 and return z
+func (z *E12) Mul(x, y *E12) *E12 {
+	var a, b, c E6
 	a.Add(&x.C0, &x.C1)
 	b.Add(&y.C0, &y.C1)
 	a.Mul(&a, &b)
@@ -121,11 +165,58 @@ func (z *e12) Mul(x, y *e12) *e12 {
 	return z
 }
 
-// Square set z=x*x in e12 and return z
-func (z *e12) Square(x *e12) *e12 {
+// MulBy014 multiplies z by the sparse element (c0,c1,0,0,c4,0) in place and returns z
+// i.e. the E12 element with C0.B0=c0, C0.B1=c1, C1.B1=c4 and all other components 0
+func (z *E12) MulBy014(c0, c1, c4 *E2) *E12 {
+
+	var a, b E6
+	var d E2
+
+	a.Set(&z.C0)
+	a.MulBy01(c0, c1)
+
+	b.Set(&z.C1)
+	b.MulBy1(c4)
+	d.Add(c1, c4)
+
+	z.C1.Add(&z.C1, &z.C0)
+	z.C1.MulBy01(c0, &d)
+	z.C1.Sub(&z.C1, &a)
+	z.C1.Sub(&z.C1, &b)
+	z.C0.MulByNonResidue(&b)
+	z.C0.Add(&z.C0, &a)
+
+	return z
+}
+
+// MulBy034 multiplies z by the sparse element (c0,0,0,c3,c4,0) in place and returns z
+// i.e. the E12 element with C0.B0=c0, C1.B0=c3, C1.B1=c4 and all other components 0
+func (z *E12) MulBy034(c0, c3, c4 *E2) *E12 {
+
+	var a, b, d E6
+	var c03 E2
+
+	a.MulByE2(&z.C0, c0)
+
+	b.Set(&z.C1)
+	b.MulBy01(c3, c4)
+
+	c03.Add(c0, c3)
+	d.Add(&z.C0, &z.C1)
+	d.MulBy01(&c03, c4)
+
+	z.C1.Add(&a, &b).Neg(&z.C1).Add(&z.C1, &d)
+	z.C0.MulByNonResidue(&b)
+	z.C0.Add(&z.C0, &a)
+
+	return z
+}
+
+// Square set z=x*x in E12 and return z
+func (z *E12) Square(x *E12) *E12 {
 
 	//Algorithm 22 from https://eprint.iacr.org/2010/354.pdf
-	var c0, c2, c3 e6
+	var c0, c2, c3 E6
 	c0.Sub(&x.C0, &x.C1)
 	c3.MulByNonResidue(&x.C1).Neg(&c3).Add(&x.C0, &c3)
 	c2.Mul(&x.C0, &x.C1)
@@ -138,8 +229,8 @@ func (z *e12) Square(x *e12) *e12 {
 }
 
 // squares an element a+by interpreted as an Fp4 elmt, where y**2= non_residue_e2
-func fp4Square(a, b, c, d *e2) {
-	var tmp e2
+func fp4Square(a, b, c, d *E2) {
+	var tmp E2
 	c.Square(a)
 	tmp.Square(b).MulByNonResidue(&tmp)
 	c.Add(c, &tmp)
@@ -147,10 +238,10 @@ func fp4Square(a, b, c, d *e2) {
 }
 
 // CyclotomicSquare https://eprint.iacr.org/2009/565.pdf, 3.2
-func (z *e12) CyclotomicSquare(x *e12) *e12 {
+func (z *E12) CyclotomicSquare(x *E12) *E12 {
 
-	var res, b, a e12
-	var tmp e2
+	var res, b, a E12
+	var tmp E2
 
 	// A
 	fp4Square(&x.C0.B0, &x.C1.B1, &b.C0.B0, &b.C1.B1)
@@ -178,11 +269,11 @@ func (z *e12) CyclotomicSquare(x *e12) *e12 {
 	return z
 }
 
-// Inverse set z to the inverse of x in e12 and return z
-func (z *e12) Inverse(x *e12) *e12 {
+// Inverse set z to the inverse of x in E12 and return z
+func (z *E12) Inverse(x *E12) *E12 {
 	// Algorithm 23 from https://eprint.iacr.org/2010/354.pdf
 
-	var t0, t1, tmp e6
+	var t0, t1, tmp E6
 	t0.Square(&x.C0)
 	t1.Square(&x.C1)
 	tmp.MulByNonResidue(&t1)
@@ -194,9 +285,17 @@ func (z *e12) Inverse(x *e12) *e12 {
 	return z
 }
 
-// Exp sets z=x**e and returns it
-func (z *e12) Exp(x *e12, e big.Int) *e12 {
-	var res e12
+// Exp sets z=x**k and returns it
+// if k is negative, z is set to the inverse of x**(-k)
+func (z *E12) Exp(x *E12, k *big.Int) *E12 {
+	var base, res E12
+	e := k
+	if k.Sign() == -1 {
+		base.Inverse(x)
+		e = new(big.Int).Neg(k)
+	} else {
+		base.Set(x)
+	}
 	res.SetOne()
 	b := e.Bytes()
 	for i := range b {
@@ -205,7 +304,7 @@ func (z *e12) Exp(x *e12, e big.Int) *e12 {
 		for j := 7; j >= 0; j-- {
 			res.Square(&res)
 			if (w&mask)>>j != 0 {
-				res.Mul(&res, x)
+				res.Mul(&res, &base)
 			}
 			mask = mask >> 1
 		}
@@ -215,13 +314,50 @@ func (z *e12) Exp(x *e12, e big.Int) *e12 {
 }
 
 // InverseUnitary inverse a unitary element
-func (z *e12) InverseUnitary(x *e12) *e12 {
+func (z *E12) InverseUnitary(x *E12) *E12 {
 	return z.Conjugate(x)
 }
 
 // Conjugate set z to x conjugated and return z
-func (z *e12) Conjugate(x *e12) *e12 {
+func (z *E12) Conjugate(x *E12) *E12 {
 	*z = *x
 	z.C1.Neg(&z.C1)
 	return z
 }
+
+// CompressTorus compresses z, an element of the cyclotomic subgroup (e.g. GT), to half its size
+// using the T2 torus representation: z = (y + w) / (y - w) with y = (1 + C0) / C1 in E6
+// cf "Compression in finite fields and torus-based cryptography", K. Rubin and A. Silverberg.
+// C1 is zero only for z = ±1: 1 is compressed to 0, and -1 (which is not in GT) returns an error.
+func (z *E12) CompressTorus() (E6, error) {
+	var res, one E6
+	one.SetOne()
+	if z.C1.IsZero() {
+		if z.C0.Equal(&one) {
+			return res, nil
+		}
+		return res, errors.New("invalid input: element has no torus representation")
+	}
+	res.Inverse(&z.C1)
+	one.Add(&z.C0, &one)
+	res.Mul(&res, &one)
+	return res, nil
+}
+
+// DecompressTorus sets z to the element of the cyclotomic subgroup whose T2 torus
+// representation is y (see CompressTorus) and returns z
+// z = (y + w) / (y - w) = (y**2 + v + 2y*w) / (y**2 - v)
+func (z *E12) DecompressTorus(y *E6) *E12 {
+	if y.IsZero() {
+		return z.SetOne()
+	}
+	var y2, v, inv, c0, c1 E6
+	v.B1.A0.SetOne()
+	y2.Square(y)
+	inv.Sub(&y2, &v).Inverse(&inv)
+	c0.Add(&y2, &v).Mul(&c0, &inv)
+	c1.Double(y).Mul(&c1, &inv)
+	z.C0.Set(&c0)
+	z.C1.Set(&c1)
+	return z
+}
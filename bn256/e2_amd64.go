@@ -24,7 +24,7 @@ import (
 var supportAdx = cpu.X86.HasADX && cpu.X86.HasBMI2
 
 // q (modulus)
-var qe2 = [4]uint64{
+var qE2 = [4]uint64{
 	4332616871279656263,
 	10917124144477883021,
 	13281191951274694749,
@@ -32,43 +32,43 @@ var qe2 = [4]uint64{
 }
 
 // q'[0], see montgommery multiplication algorithm
-var qe2Inv0 uint64 = 9786893198990664585
+var qE2Inv0 uint64 = 9786893198990664585
 
 //go:noescape
-func addE2(res, x, y *e2)
+func addE2(res, x, y *E2)
 
 //go:noescape
-func subE2(res, x, y *e2)
+func subE2(res, x, y *E2)
 
 //go:noescape
-func doubleE2(res, x *e2)
+func doubleE2(res, x *E2)
 
 //go:noescape
-func negE2(res, x *e2)
+func negE2(res, x *E2)
 
 //go:noescape
-func mulNonResE2(res, x *e2)
+func mulNonResE2(res, x *E2)
 
 //go:noescape
-func squareAdxE2(res, x *e2)
+func squareAdxE2(res, x *E2)
 
 //go:noescape
-func mulAdxE2(res, x, y *e2)
+func mulAdxE2(res, x, y *E2)
 
-// MulByNonResidue multiplies a e2 by (9,1)
-func (z *e2) MulByNonResidue(x *e2) *e2 {
+// MulByNonResidue multiplies a E2 by (9,1)
+func (z *E2) MulByNonResidue(x *E2) *E2 {
 	mulNonResE2(z, x)
 	return z
 }
 
-// Mul sets z to the e2-product of x,y, returns z
-func (z *e2) Mul(x, y *e2) *e2 {
+// Mul sets z to the E2-product of x,y, returns z
+func (z *E2) Mul(x, y *E2) *E2 {
 	mulAdxE2(z, x, y)
 	return z
 }
 
-// Square sets z to the e2-product of x,x, returns z
-func (z *e2) Square(x *e2) *e2 {
+// Square sets z to the E2-product of x,x, returns z
+func (z *E2) Square(x *E2) *E2 {
 	squareAdxE2(z, x)
 	return z
 }
@@ -16,41 +16,59 @@
 
 package bn256
 
-// e6 is a degree three finite field extension of fp2
-type e6 struct {
-	B0, B1, B2 e2
+import (
+	"errors"
+)
+
+// SizeOfE6 is the size in bytes of an E6 element, in regular form
+const SizeOfE6 = 3 * SizeOfE2
+
+// E6 is a degree three finite field extension of fp2
+type E6 struct {
+	B0, B1, B2 E2
 }
 
 // Equal returns true if z equals x, fasle otherwise
-func (z *e6) Equal(x *e6) bool {
+func (z *E6) Equal(x *E6) bool {
 	return z.B0.Equal(&x.B0) && z.B1.Equal(&x.B1) && z.B2.Equal(&x.B2)
 }
 
-// SetString sets a e6 elmt from stringf
-func (z *e6) SetString(s1, s2, s3, s4, s5, s6 string) *e6 {
+// SetString sets a E6 elmt from stringf
+func (z *E6) SetString(s1, s2, s3, s4, s5, s6 string) *E6 {
 	z.B0.SetString(s1, s2)
 	z.B1.SetString(s3, s4)
 	z.B2.SetString(s5, s6)
 	return z
 }
 
-// Set Sets a e6 elmt form another e6 elmt
-func (z *e6) Set(x *e6) *e6 {
+// Set Sets a E6 elmt form another E6 elmt
+func (z *E6) Set(x *E6) *E6 {
 	z.B0 = x.B0
 	z.B1 = x.B1
 	z.B2 = x.B2
 	return z
 }
 
+// SetZero sets an E6 elmt to zero
+func (z *E6) SetZero() *E6 {
+	*z = E6{}
+	return z
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E6) IsZero() bool {
+	return z.B0.IsZero() && z.B1.IsZero() && z.B2.IsZero()
+}
+
 // SetOne sets z to 1 in Montgomery form and returns z
-func (z *e6) SetOne() *e6 {
-	*z = e6{}
+func (z *E6) SetOne() *E6 {
+	*z = E6{}
 	z.B0.A0.SetOne()
 	return z
 }
 
 // SetRandom set z to a random elmt
-func (z *e6) SetRandom() *e6 {
+func (z *E6) SetRandom() *E6 {
 	z.B0.SetRandom()
 	z.B1.SetRandom()
 	z.B2.SetRandom()
@@ -58,7 +76,7 @@ func (z *e6) SetRandom() *e6 {
 }
 
 // ToMont converts to Mont form
-func (z *e6) ToMont() *e6 {
+func (z *E6) ToMont() *E6 {
 	z.B0.ToMont()
 	z.B1.ToMont()
 	z.B2.ToMont()
@@ -66,61 +84,146 @@ func (z *e6) ToMont() *e6 {
 }
 
 // FromMont converts from Mont form
-func (z *e6) FromMont() *e6 {
+func (z *E6) FromMont() *E6 {
 	z.B0.FromMont()
 	z.B1.FromMont()
 	z.B2.FromMont()
 	return z
 }
 
-// Add adds two elements of e6
-func (z *e6) Add(x, y *e6) *e6 {
+// Bytes returns the regular (non montgomery) value of z as a big-endian byte array
+// B2 | B1 | B0, each E2 being encoded as A1 | A0
+func (z *E6) Bytes() (res [SizeOfE6]byte) {
+	b := z.B2.Bytes()
+	copy(res[:SizeOfE2], b[:])
+	b = z.B1.Bytes()
+	copy(res[SizeOfE2:2*SizeOfE2], b[:])
+	b = z.B0.Bytes()
+	copy(res[2*SizeOfE2:], b[:])
+	return
+}
+
+// SetBytes interprets buf as the big-endian encoding B2 | B1 | B0 of an E6 element, and sets z to it
+// It returns an error if buf is not SizeOfE6 bytes long, or if a coordinate is not reduced modulo q
+func (z *E6) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfE6 {
+		return errors.New("invalid buffer size")
+	}
+	if err := z.B2.SetBytes(buf[:SizeOfE2]); err != nil {
+		return err
+	}
+	if err := z.B1.SetBytes(buf[SizeOfE2 : 2*SizeOfE2]); err != nil {
+		return err
+	}
+	return z.B0.SetBytes(buf[2*SizeOfE2:])
+}
+
+// Add adds two elements of E6
+func (z *E6) Add(x, y *E6) *E6 {
 	z.B0.Add(&x.B0, &y.B0)
 	z.B1.Add(&x.B1, &y.B1)
 	z.B2.Add(&x.B2, &y.B2)
 	return z
 }
 
-// Neg negates the e6 number
-func (z *e6) Neg(x *e6) *e6 {
+// Neg negates the E6 number
+func (z *E6) Neg(x *E6) *E6 {
 	z.B0.Neg(&x.B0)
 	z.B1.Neg(&x.B1)
 	z.B2.Neg(&x.B2)
 	return z
 }
 
-// Sub two elements of e6
-func (z *e6) Sub(x, y *e6) *e6 {
+// Sub two elements of E6
+func (z *E6) Sub(x, y *E6) *E6 {
 	z.B0.Sub(&x.B0, &y.B0)
 	z.B1.Sub(&x.B1, &y.B1)
 	z.B2.Sub(&x.B2, &y.B2)
 	return z
 }
 
-// Double doubles an element in e6
-func (z *e6) Double(x *e6) *e6 {
+// Double doubles an element in E6
+func (z *E6) Double(x *E6) *E6 {
 	z.B0.Double(&x.B0)
 	z.B1.Double(&x.B1)
 	z.B2.Double(&x.B2)
 	return z
 }
 
-// String puts e6 elmt in string form
-func (z *e6) String() string {
+// String puts E6 elmt in string form
+func (z *E6) String() string {
 	return (z.B0.String() + "+(" + z.B1.String() + ")*v+(" + z.B2.String() + ")*v**2")
 }
 
 // MulByNonResidue mul x by (0,1,0)
-func (z *e6) MulByNonResidue(x *e6) *e6 {
+func (z *E6) MulByNonResidue(x *E6) *E6 {
 	z.B2, z.B1, z.B0 = x.B1, x.B0, x.B2
 	z.B0.MulByNonResidue(&z.B0)
 	return z
 }
 
-// Mul sets z to the e6 product of x,y, returns z
-func (z *e6) Mul(x, y *e6) *e6 {
+// MulByE2 multiplies an element in E6 by an element in E2
+func (z *E6) MulByE2(x *E6, y *E2) *E6 {
+	var yCopy E2
+	yCopy.Set(y)
+	z.B0.Mul(&x.B0, &yCopy)
+	z.B1.Mul(&x.B1, &yCopy)
+	z.B2.Mul(&x.B2, &yCopy)
+	return z
+}
+
+// MulBy01 multiplies z by the sparse element (c0,c1,0) in place and returns z
+func (z *E6) MulBy01(c0, c1 *E2) *E6 {
+
+	var a, b, tmp, t0, t1, t2 E2
+
+	a.Mul(&z.B0, c0)
+	b.Mul(&z.B1, c1)
+
+	tmp.Add(&z.B1, &z.B2)
+	t0.Mul(c1, &tmp)
+	t0.Sub(&t0, &b)
+	t0.MulByNonResidue(&t0)
+	t0.Add(&t0, &a)
+
+	tmp.Add(&z.B0, &z.B2)
+	t2.Mul(c0, &tmp)
+	t2.Sub(&t2, &a)
+	t2.Add(&t2, &b)
+
+	t1.Add(c0, c1)
+	tmp.Add(&z.B0, &z.B1)
+	t1.Mul(&t1, &tmp)
+	t1.Sub(&t1, &a)
+	t1.Sub(&t1, &b)
+
+	z.B0.Set(&t0)
+	z.B1.Set(&t1)
+	z.B2.Set(&t2)
+
+	return z
+}
+
+// MulBy1 multiplies z by the sparse element (0,c1,0) in place and returns z
+func (z *E6) MulBy1(c1 *E2) *E6 {
+
+	var t0, t1, t2 E2
+
+	t0.Mul(&z.B2, c1).MulByNonResidue(&t0)
+	t1.Mul(&z.B0, c1)
+	t2.Mul(&z.B1, c1)
+
+	z.B0.Set(&t0)
+	z.B1.Set(&t1)
+	z.B2.Set(&t2)
+
+	return z
+}
+
+// Mul sets z to the E6 product of x,y, returns z
+func (z *E6) Mul(x, y *E6) *E6 {
 	// Algorithm 13 from https://eprint.iacr.org/2010/354.pdf
-	var t0, t1, t2, c0, c1, c2, tmp e2
+	var t0, t1, t2, c0, c1, c2, tmp E2
 	t0.Mul(&x.B0, &y.B0)
 	t1.Mul(&x.B1, &y.B1)
 	t2.Mul(&x.B2, &y.B2)
@@ -145,11 +248,11 @@ func (z *e6) Mul(x, y *e6) *e6 {
 	return z
 }
 
-// Square sets z to the e6 product of x,x, returns z
-func (z *e6) Square(x *e6) *e6 {
+// Square sets z to the E6 product of x,x, returns z
+func (z *E6) Square(x *E6) *E6 {
 
 	// Algorithm 16 from https://eprint.iacr.org/2010/354.pdf
-	var c4, c5, c1, c2, c3, c0 e2
+	var c4, c5, c1, c2, c3, c0 E2
 	c4.Mul(&x.B0, &x.B1).Double(&c4)
 	c5.Square(&x.B2)
 	c1.MulByNonResidue(&c5).Add(&c1, &c4)
@@ -166,11 +269,11 @@ func (z *e6) Square(x *e6) *e6 {
 	return z
 }
 
-// Inverse an element in e6
-func (z *e6) Inverse(x *e6) *e6 {
+// Inverse an element in E6
+func (z *E6) Inverse(x *E6) *E6 {
 	// Algorithm 17 from https://eprint.iacr.org/2010/354.pdf
 	// step 9 is wrong in the paper it's t1-t4
-	var t0, t1, t2, t3, t4, t5, t6, c0, c1, c2, d1, d2 e2
+	var t0, t1, t2, t3, t4, t5, t6, c0, c1, c2, d1, d2 E2
 	t0.Square(&x.B0)
 	t1.Square(&x.B1)
 	t2.Square(&x.B2)
@@ -37,8 +37,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	genB := GenE6()
 
 	properties.Property("[BN256] Having the receiver as operand (addition) should output the same result", prop.ForAll(
-		func(a, b *e6) bool {
-			var c, d e6
+		func(a, b *E6) bool {
+			var c, d E6
 			d.Set(a)
 			c.Add(a, b)
 			a.Add(a, b)
@@ -50,8 +50,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Having the receiver as operand (sub) should output the same result", prop.ForAll(
-		func(a, b *e6) bool {
-			var c, d e6
+		func(a, b *E6) bool {
+			var c, d E6
 			d.Set(a)
 			c.Sub(a, b)
 			a.Sub(a, b)
@@ -63,8 +63,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Having the receiver as operand (mul) should output the same result", prop.ForAll(
-		func(a, b *e6) bool {
-			var c, d e6
+		func(a, b *E6) bool {
+			var c, d E6
 			d.Set(a)
 			c.Mul(a, b)
 			a.Mul(a, b)
@@ -76,8 +76,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Having the receiver as operand (square) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Square(a)
 			a.Square(a)
 			return a.Equal(&b)
@@ -86,8 +86,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Having the receiver as operand (neg) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Neg(a)
 			a.Neg(a)
 			return a.Equal(&b)
@@ -96,8 +96,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Having the receiver as operand (double) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Double(a)
 			a.Double(a)
 			return a.Equal(&b)
@@ -106,8 +106,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.MulByNonResidue(a)
 			a.MulByNonResidue(a)
 			return a.Equal(&b)
@@ -116,8 +116,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Inverse(a)
 			a.Inverse(a)
 			return a.Equal(&b)
@@ -137,10 +137,11 @@ func TestE6Ops(t *testing.T) {
 
 	genA := GenE6()
 	genB := GenE6()
+	genE2 := GenE2()
 
 	properties.Property("[BN256] sub & add should leave an element invariant", prop.ForAll(
-		func(a, b *e6) bool {
-			var c e6
+		func(a, b *E6) bool {
+			var c E6
 			c.Set(a)
 			c.Add(&c, b).Sub(&c, b)
 			return c.Equal(a)
@@ -150,8 +151,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] mul & inverse should leave an element invariant", prop.ForAll(
-		func(a, b *e6) bool {
-			var c, d e6
+		func(a, b *E6) bool {
+			var c, d E6
 			d.Inverse(b)
 			c.Set(a)
 			c.Mul(&c, b).Mul(&c, &d)
@@ -162,8 +163,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] inverse twice should leave an element invariant", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Inverse(a).Inverse(&b)
 			return a.Equal(&b)
 		},
@@ -171,8 +172,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] neg twice should leave an element invariant", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Neg(a).Neg(&b)
 			return a.Equal(&b)
 		},
@@ -180,8 +181,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] square and mul should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b, c e6
+		func(a *E6) bool {
+			var b, c E6
 			b.Mul(a, a)
 			c.Square(a)
 			return b.Equal(&c)
@@ -190,8 +191,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Double and add twice should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Add(a, a)
 			a.Double(a)
 			return a.Equal(&b)
@@ -200,8 +201,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Mul by non residue should be the same as multiplying by (0,1,0)", prop.ForAll(
-		func(a *e6) bool {
-			var b, c e6
+		func(a *E6) bool {
+			var b, c E6
 			b.B1.A0.SetOne()
 			c.Mul(a, &b)
 			a.MulByNonResidue(a)
@@ -210,6 +211,52 @@ func TestE6Ops(t *testing.T) {
 		genA,
 	))
 
+	properties.Property("[BN256] MulByE2 MulBy01 MulBy1 should output the same result as a full multiplication by the sparse element", prop.ForAll(
+		func(a *E6, c0, c1 *E2) bool {
+			var b, c, d, e, f, g E6
+			b.B0.Set(c0)
+			c.Mul(a, &b)
+			d.MulByE2(a, c0)
+			b.B1.Set(c1)
+			e.Mul(a, &b)
+			f.Set(a).MulBy01(c0, c1)
+			b.B0.SetZero()
+			b.B1.Set(c1)
+			g.Mul(a, &b)
+			a.MulBy1(c1)
+			return c.Equal(&d) && e.Equal(&f) && g.Equal(a)
+		},
+		genA,
+		genE2,
+		genE2,
+	))
+
+	properties.Property("[BN256] Bytes and SetBytes should leave an element invariant", prop.ForAll(
+		func(a *E6) bool {
+			var b E6
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[BN256] a - a should be zero, a + 1 should not", prop.ForAll(
+		func(a *E6) bool {
+			var b, one E6
+			one.SetOne()
+			b.Sub(a, a)
+			if !b.IsZero() {
+				return false
+			}
+			b.Add(&b, &one)
+			return !b.IsZero()
+		},
+		genA,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
 }
@@ -218,7 +265,7 @@ func TestE6Ops(t *testing.T) {
 // benches
 
 func BenchmarkE6Add(b *testing.B) {
-	var a, c e6
+	var a, c E6
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -228,7 +275,7 @@ func BenchmarkE6Add(b *testing.B) {
 }
 
 func BenchmarkE6Sub(b *testing.B) {
-	var a, c e6
+	var a, c E6
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -238,7 +285,7 @@ func BenchmarkE6Sub(b *testing.B) {
 }
 
 func BenchmarkE6Mul(b *testing.B) {
-	var a, c e6
+	var a, c E6
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -247,8 +294,20 @@ func BenchmarkE6Mul(b *testing.B) {
 	}
 }
 
+func BenchmarkE6MulBy01(b *testing.B) {
+	var a E6
+	var c0, c1 E2
+	a.SetRandom()
+	c0.SetRandom()
+	c1.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.MulBy01(&c0, &c1)
+	}
+}
+
 func BenchmarkE6Square(b *testing.B) {
-	var a e6
+	var a E6
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -257,7 +316,7 @@ func BenchmarkE6Square(b *testing.B) {
 }
 
 func BenchmarkE6Inverse(b *testing.B) {
-	var a e6
+	var a E6
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,63 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gurvy DO NOT EDIT
+
+package fp
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestElementSetRandomFrom(t *testing.T) {
+	var zero Element
+
+	r1 := rand.New(rand.NewSource(42))
+	r2 := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 1000; i++ {
+		var a, b, c Element
+		if _, err := a.SetRandomFrom(r1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := b.SetRandomFrom(r2); err != nil {
+			t.Fatal(err)
+		}
+		if !a.Equal(&b) {
+			t.Fatal("SetRandomFrom should be deterministic for a given source")
+		}
+		// a reduced element is left unchanged by a modular addition
+		c.Add(&a, &zero)
+		if !c.Equal(&a) {
+			t.Fatal("SetRandomFrom output should be smaller than the modulus")
+		}
+	}
+
+	var a Element
+	if _, err := a.SetRandomFrom(bytes.NewReader(make([]byte, Limbs*8-1))); err != io.ErrUnexpectedEOF {
+		t.Fatal("SetRandomFrom should forward the reader error, got", err)
+	}
+}
+
+func BenchmarkElementSetRandomFrom(b *testing.B) {
+	var x Element
+	r := rand.New(rand.NewSource(42))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.SetRandomFrom(r)
+	}
+}
@@ -17,7 +17,10 @@
 package bn256
 
 import (
+	"io"
 	"math/big"
+	"math/bits"
+	"sync"
 
 	"github.com/consensys/gurvy/bn256/fp"
 	"github.com/consensys/gurvy/bn256/fr"
@@ -191,6 +194,88 @@ func (p *G1Jac) ScalarMultiplication(a *G1Jac, s *big.Int) *G1Jac {
 	return p.mulGLV(a, s)
 }
 
+// ScalarMultiplicationCT computes and returns p = a*s in constant time with respect to s
+//
+// The sequence of operations and the memory access pattern don't depend on s:
+// s is converted to regular form with a fixed Montgomery reduction, split using GLV on
+// fixed-width integers (see utils.SplitScalarCT), and the resulting scalars are
+// recoded in signed odd digits (every digit is non-zero). Table lookups scan the whole table of odd
+// multiples, signs are applied with masks, and points are added and doubled in projective
+// coordinates with the complete formulas of Renes, Costello and Batina (https://eprint.iacr.org/2015/1060),
+// which have no special case for the point at infinity or equal inputs.
+//
+// The base point a is not secret (ScalarMultiplicationCT may branch on it being infinity), and the
+// timing of the field arithmetic is inherited from the fp package: constant time on amd64 (assembly
+// using conditional moves), but the generic Go code used on other platforms ends modular
+// reductions with conditional subtractions.
+func (p *G1Jac) ScalarMultiplicationCT(a *G1Jac, s *fr.Element) *G1Jac {
+	const nbScalars = 2
+	nbDigits := (glvBasis.SplitBitLen + ctWindow - 1) / ctWindow
+
+	var table [nbScalars][1 << (ctWindow - 1)]G1Proj
+	var k [nbScalars][fr.Limbs]uint64
+	var neg, even [nbScalars]uint64
+	e := frToRegularCT(s)
+	neg[0], neg[1] = utils.SplitScalarCT(k[0][:], k[1][:], e[:], &glvBasis)
+
+	// the recoding needs odd scalars: k is replaced by k+1 if even, which is corrected at the end
+	for i := 0; i < nbScalars; i++ {
+		even[i] = ^k[i][0] & 1
+		k[i][0] |= 1
+	}
+
+	// table[0] stores [a, 3a, 5a, ...], table[1] stores phi(table[0])
+	var a2 G1Proj
+	if a.Z.IsZero() {
+		table[0][0].Y.SetOne()
+	} else {
+		table[0][0].FromJacobian(a)
+	}
+	a2.doubleCT(&table[0][0])
+	for j := 1; j < len(table[0]); j++ {
+		table[0][j].addCT(&table[0][j-1], &a2)
+	}
+	for j := 0; j < len(table[1]); j++ {
+		table[1][j].phi(&table[0][j])
+	}
+
+	var res, tmp G1Proj
+
+	// most significant digits are positive and the accumulator is initialized with them
+	for i := 0; i < nbScalars; i++ {
+		idx, _ := signedDigitCT(k[i][:], nbDigits-1, true)
+		tmp.lookupCT(table[i][:], idx)
+		tmp.negCT(neg[i])
+		if i == 0 {
+			res.Set(&tmp)
+		} else {
+			res.addCT(&res, &tmp)
+		}
+	}
+
+	for d := nbDigits - 2; d >= 0; d-- {
+		for j := 0; j < ctWindow; j++ {
+			res.doubleCT(&res)
+		}
+		for i := 0; i < nbScalars; i++ {
+			idx, sign := signedDigitCT(k[i][:], d, false)
+			tmp.lookupCT(table[i][:], idx)
+			tmp.negCT(sign ^ neg[i])
+			res.addCT(&res, &tmp)
+		}
+	}
+
+	// subtract the base points for the scalars that were even
+	for i := 0; i < nbScalars; i++ {
+		tmp.Set(&table[i][0])
+		tmp.negCT(1 ^ neg[i])
+		tmp.addCT(&tmp, &res)
+		res.cmov(&tmp, even[i])
+	}
+
+	return p.fromProjCT(&res)
+}
+
 // Set set p to the provided point
 func (p *G1Jac) Set(a *G1Jac) *G1Jac {
 	p.X, p.Y, p.Z = a.X, a.Y, a.Z
@@ -448,6 +533,231 @@ func (p *G1Jac) mulGLV(a *G1Jac, s *big.Int) *G1Jac {
 	return p
 }
 
+// Set sets p to the provided point
+func (p *G1Proj) Set(a *G1Proj) *G1Proj {
+	p.X, p.Y, p.Z = a.X, a.Y, a.Z
+	return p
+}
+
+// addCT sets p to a+b using the complete addition formula for a=0 curves
+// (https://eprint.iacr.org/2015/1060, algorithm 7), and returns p
+func (p *G1Proj) addCT(a, b *G1Proj) *G1Proj {
+	var t0, t1, t2, t3, t4, b3, X3, Y3, Z3 fp.Element
+	b3.Double(&bCurveCoeff).Add(&b3, &bCurveCoeff)
+	t0.Mul(&a.X, &b.X)
+	t1.Mul(&a.Y, &b.Y)
+	t2.Mul(&a.Z, &b.Z)
+	t3.Add(&a.X, &a.Y)
+	t4.Add(&b.X, &b.Y)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(&a.Y, &a.Z)
+	X3.Add(&b.Y, &b.Z)
+	t4.Mul(&t4, &X3)
+	X3.Add(&t1, &t2)
+	t4.Sub(&t4, &X3)
+	X3.Add(&a.X, &a.Z)
+	Y3.Add(&b.X, &b.Z)
+	X3.Mul(&X3, &Y3)
+	Y3.Add(&t0, &t2)
+	Y3.Sub(&X3, &Y3)
+	X3.Double(&t0)
+	t0.Add(&X3, &t0)
+	t2.Mul(&b3, &t2)
+	Z3.Add(&t1, &t2)
+	t1.Sub(&t1, &t2)
+	Y3.Mul(&b3, &Y3)
+	X3.Mul(&t4, &Y3)
+	t2.Mul(&t3, &t1)
+	X3.Sub(&t2, &X3)
+	Y3.Mul(&Y3, &t0)
+	t1.Mul(&t1, &Z3)
+	Y3.Add(&t1, &Y3)
+	t0.Mul(&t0, &t3)
+	Z3.Mul(&Z3, &t4)
+	Z3.Add(&Z3, &t0)
+	p.X, p.Y, p.Z = X3, Y3, Z3
+	return p
+}
+
+// doubleCT sets p to 2a using the complete doubling formula for a=0 curves
+// (https://eprint.iacr.org/2015/1060, algorithm 9), and returns p
+func (p *G1Proj) doubleCT(a *G1Proj) *G1Proj {
+	var t0, t1, t2, b3, X3, Y3, Z3 fp.Element
+	b3.Double(&bCurveCoeff).Add(&b3, &bCurveCoeff)
+	t0.Square(&a.Y)
+	Z3.Double(&t0).Double(&Z3).Double(&Z3)
+	t1.Mul(&a.Y, &a.Z)
+	t2.Square(&a.Z)
+	t2.Mul(&b3, &t2)
+	X3.Mul(&t2, &Z3)
+	Y3.Add(&t0, &t2)
+	Z3.Mul(&t1, &Z3)
+	t1.Double(&t2)
+	t2.Add(&t1, &t2)
+	t0.Sub(&t0, &t2)
+	Y3.Mul(&t0, &Y3)
+	Y3.Add(&X3, &Y3)
+	t1.Mul(&a.X, &a.Y)
+	X3.Mul(&t0, &t1)
+	X3.Double(&X3)
+	p.X, p.Y, p.Z = X3, Y3, Z3
+	return p
+}
+
+// phi assigns p to phi(a) where phi: (x,y)->(ux,y), and returns p
+func (p *G1Proj) phi(a *G1Proj) *G1Proj {
+	p.Set(a)
+	p.X.Mul(&p.X, &thirdRootOneG1)
+	return p
+}
+
+// fromProjCT sets p to a in Jacobian coordinates, (X*Z, Y*Z**2, Z), and returns p
+// the point at infinity (0:1:0) is mapped to (1,1,0) with a conditional move
+func (p *G1Jac) fromProjCT(a *G1Proj) *G1Jac {
+	var zz fp.Element
+	zz.Square(&a.Z)
+	p.X.Mul(&a.X, &a.Z)
+	p.Y.Mul(&a.Y, &zz)
+	p.Z = a.Z
+
+	var inf G1Jac
+	inf.X.SetOne()
+	inf.Y.SetOne()
+	var acc uint64
+	for i := 0; i < fp.Limbs; i++ {
+		acc |= a.Z[i]
+	}
+	return p.cmov(&inf, ctEq(acc, 0))
+}
+
+// cmov sets p to a if c == 1 and leaves it unchanged if c == 0, in constant time
+func (p *G1Jac) cmov(a *G1Jac, c uint64) *G1Jac {
+	fpCmov(&p.X, &a.X, c)
+	fpCmov(&p.Y, &a.Y, c)
+	fpCmov(&p.Z, &a.Z, c)
+	return p
+}
+
+// cmov sets p to a if c == 1 and leaves it unchanged if c == 0, in constant time
+func (p *G1Proj) cmov(a *G1Proj, c uint64) *G1Proj {
+	fpCmov(&p.X, &a.X, c)
+	fpCmov(&p.Y, &a.Y, c)
+	fpCmov(&p.Z, &a.Z, c)
+	return p
+}
+
+// negCT sets p to -p if c == 1 and leaves it unchanged if c == 0, in constant time
+func (p *G1Proj) negCT(c uint64) *G1Proj {
+	// Neg branches on zero inputs, Sub doesn't
+	var n G1Proj
+	var zero fp.Element
+	n.Set(p)
+	n.Y.Sub(&zero, &p.Y)
+	return p.cmov(&n, c)
+}
+
+// lookupCT sets p to table[idx], reading every entry of the table so that
+// the memory access pattern doesn't depend on idx
+func (p *G1Proj) lookupCT(table []G1Proj, idx uint64) *G1Proj {
+	p.Set(&table[0])
+	for i := 1; i < len(table); i++ {
+		p.cmov(&table[i], ctEq(uint64(i), idx))
+	}
+	return p
+}
+
+// ctWindow is the window size used in constant time scalar multiplications
+const ctWindow = 4
+
+// fpCmov sets z to x if c == 1 and leaves it unchanged if c == 0, in constant time
+func fpCmov(z, x *fp.Element, c uint64) {
+	mask := -c
+	for i := 0; i < fp.Limbs; i++ {
+		z[i] ^= (z[i] ^ x[i]) & mask
+	}
+}
+
+// ctEq returns 1 if a == b, 0 otherwise, in constant time
+func ctEq(a, b uint64) uint64 {
+	x := a ^ b
+	return 1 ^ ((x | -x) >> 63)
+}
+
+// frModulusWords and frQInvNeg = -r**-1 mod 2**64 are used by frToRegularCT
+var frModulusWords, frQInvNeg = func() (q [fr.Limbs]uint64, qInvNeg uint64) {
+	r := fr.Modulus()
+	var w big.Int
+	for i := 0; i < fr.Limbs; i++ {
+		q[i] = w.Rsh(r, uint(64*i)).Uint64()
+	}
+	// Newton iteration: each step doubles the number of correct low bits of the inverse
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - q[0]*inv
+	}
+	return q, -inv
+}()
+
+// frToRegularCT returns the regular (non Montgomery) form of s, in constant time
+//
+// it computes s * 2**(-64*fr.Limbs) mod r with a Montgomery reduction: since s < r,
+// the result is less than r and the final conditional subtraction is not needed
+func frToRegularCT(s *fr.Element) [fr.Limbs]uint64 {
+	t := [fr.Limbs]uint64(*s)
+	for i := 0; i < fr.Limbs; i++ {
+		m := t[0] * frQInvNeg
+		hi, lo := bits.Mul64(m, frModulusWords[0])
+		_, c := bits.Add64(lo, t[0], 0)
+		carry := hi + c
+		for j := 1; j < fr.Limbs; j++ {
+			hi, lo = bits.Mul64(m, frModulusWords[j])
+			lo, c = bits.Add64(lo, t[j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[j-1] = lo
+			carry = hi
+		}
+		t[fr.Limbs-1] = carry
+	}
+	return t
+}
+
+// signedDigitCT returns the i-th digit of the regular signed recoding of the odd
+// scalar k in base 2**ctWindow, as an index in a table of odd multiples and a sign bit
+//
+// the recoding is k = sum(d_i * 2**(ctWindow*i)) where the d_i are odd, |d_i| < 2**ctWindow
+// and the last digit is positive. It is obtained with k_0 = k, d_i = (k_i mod 2**(ctWindow+1)) - 2**ctWindow,
+// k_(i+1) = (k_i - d_i) / 2**ctWindow = (k >> (ctWindow*(i+1))) | 1
+func signedDigitCT(k []uint64, i int, last bool) (idx, sign uint64) {
+	if last {
+		return (bitsAt(k, i*ctWindow, ctWindow) | 1) >> 1, 0
+	}
+	t := bitsAt(k, i*ctWindow, ctWindow+1)
+	if i != 0 {
+		t |= 1
+	}
+	d := t - (1 << ctWindow)
+	sign = d >> 63
+	d = (d ^ -sign) + sign
+	return d >> 1, sign
+}
+
+// bitsAt returns the n bits of k starting at offset
+func bitsAt(k []uint64, offset, n int) uint64 {
+	w, s := offset/64, uint(offset%64)
+	if w >= len(k) {
+		return 0
+	}
+	v := k[w] >> s
+	if s+uint(n) > 64 && w+1 < len(k) {
+		v |= k[w+1] << (64 - s)
+	}
+	return v & ((1 << uint(n)) - 1)
+}
+
 // BatchJacobianToAffineG1 converts points in Jacobian coordinates to Affine coordinates
 // performing a single field inversion (Montgomery batch inversion trick)
 // result must be allocated with len(result) == len(points)
@@ -613,3 +923,156 @@ func BatchScalarMultiplicationG1(base *G1Affine, scalars []fr.Element) []G1Affin
 	return toReturnAff
 
 }
+
+// FixedBaseTableG1 stores precomputed multiples of a fixed base point for scalar multiplications
+// using signed windows of fixedBaseWindow bits: table[i][d-1] = d * 2^(fixedBaseWindow*i) * base, 1 <= d <= 2^(fixedBaseWindow-1)
+//
+// A scalar multiplication is then nbChunks mixed additions, without doublings.
+// The table is read only once built and can be shared between goroutines.
+type FixedBaseTableG1 struct {
+	table [][1 << (fixedBaseWindow - 1)]G1Affine
+}
+
+// NewFixedBaseTableG1 builds the fixed base table of base
+func NewFixedBaseTableG1(base *G1Affine) *FixedBaseTableG1 {
+	const nbChunks = fr.Limbs * 64 / fixedBaseWindow
+	t := &FixedBaseTableG1{
+		table: make([][1 << (fixedBaseWindow - 1)]G1Affine, nbChunks),
+	}
+
+	if base.IsInfinity() {
+		// all entries are the infinity symbol (0, 0)
+		return t
+	}
+
+	// bases[i] = 2^(fixedBaseWindow*i) * base
+	bases := make([]G1Jac, nbChunks)
+	bases[0].FromAffine(base)
+	for i := 1; i < nbChunks; i++ {
+		bases[i].Set(&bases[i-1])
+		for j := 0; j < fixedBaseWindow; j++ {
+			bases[i].DoubleAssign()
+		}
+	}
+
+	parallel.Execute(nbChunks, func(start, end int) {
+		var multiples [1 << (fixedBaseWindow - 1)]G1Jac
+		for i := start; i < end; i++ {
+			multiples[0].Set(&bases[i])
+			for j := 1; j < len(multiples); j++ {
+				multiples[j].Set(&multiples[j-1]).AddAssign(&bases[i])
+			}
+			BatchJacobianToAffineG1(multiples[:], t.table[i][:])
+		}
+	})
+
+	return t
+}
+
+// Mul computes and returns s * base
+func (t *FixedBaseTableG1) Mul(s *big.Int) G1Jac {
+	var e big.Int
+	var k fr.Element
+	e.Mod(s, fr.Modulus())
+	k.SetBigInt(&e).FromMont()
+
+	var res G1Jac
+	digits := partitionScalars([]fr.Element{k}, fixedBaseWindow)
+	t.mul(&res, &digits[0])
+	return res
+}
+
+// MulBatch multiplies the base by all scalars (NOT in Montgomery form)
+// and returns resulting points in affine coordinates
+func (t *FixedBaseTableG1) MulBatch(scalars []fr.Element) []G1Affine {
+	digits := partitionScalars(scalars, fixedBaseWindow)
+	toReturn := make([]G1Jac, len(scalars))
+
+	parallel.Execute(len(digits), func(start, end int) {
+		var p G1Jac
+		for i := start; i < end; i++ {
+			t.mul(&p, &digits[i])
+			toReturn[i] = p
+		}
+	})
+	toReturnAff := make([]G1Affine, len(scalars))
+	BatchJacobianToAffineG1(toReturn, toReturnAff)
+	return toReturnAff
+}
+
+// mul sets p to the sum of the table entries selected by the signed digits
+// (as output by partitionScalars) and returns p
+func (t *FixedBaseTableG1) mul(p *G1Jac, digits *fr.Element) *G1Jac {
+	const mask = uint64((1 << fixedBaseWindow) - 1)
+	const msbWindow = uint64(1 << (fixedBaseWindow - 1))
+
+	p.Set(&g1Infinity)
+	for chunk := 0; chunk < len(t.table); chunk++ {
+		jc := chunk * fixedBaseWindow
+		bits := (digits[jc/64] >> uint(jc%64)) & mask
+
+		if bits == 0 {
+			continue
+		}
+
+		// if msbWindow bit is set, we need to substract
+		if bits&msbWindow == 0 {
+			p.AddMixed(&t.table[chunk][bits-1])
+		} else {
+			q := t.table[chunk][bits & ^msbWindow]
+			q.Neg(&q)
+			p.AddMixed(&q)
+		}
+	}
+	return p
+}
+
+var g1GenTable *FixedBaseTableG1
+var g1GenTableOnce sync.Once
+
+// G1GeneratorTable returns the fixed base table of the G1 generator, built on first call
+func G1GeneratorTable() *FixedBaseTableG1 {
+	g1GenTableOnce.Do(func() {
+		g1GenTable = NewFixedBaseTableG1(&g1GenAff)
+	})
+	return g1GenTable
+}
+
+// fixedBaseWindow is the window size of the fixed base tables. It divides fr.Limbs * 64, so
+// partitionScalars adds no carry window and a carry out of the top window would be lost. This
+// doesn't happen because the top window of r-1 is smaller than 2^(fixedBaseWindow-1) - 1: with
+// the carry of the window below, the top digit still doesn't reach 2^(fixedBaseWindow-1)
+const fixedBaseWindow = 8
+
+// RandomG1 returns a uniformly random point in the r-torsion of G1, using the randomness read from r
+//
+// x is sampled until x**3+b is a square, y is one of its square roots selected with a random bit.
+// Using a seeded r makes the output reproducible.
+func RandomG1(r io.Reader) (G1Affine, error) {
+	var res G1Affine
+	var x, y fp.Element
+	var sign [1]byte
+
+	for {
+		if _, err := x.SetRandomFrom(r); err != nil {
+			return res, err
+		}
+		y.Square(&x).Mul(&y, &x).Add(&y, &bCurveCoeff)
+		if y.Legendre() == 1 {
+			break
+		}
+	}
+	y.Sqrt(&y)
+	if _, err := io.ReadFull(r, sign[:]); err != nil {
+		return res, err
+	}
+	// the output of Sqrt is not canonical, the random bit selects the lexicographically largest root or not
+	largest := lexicographicallyLargest(&y)
+	if largest != (sign[0]&1 == 1) {
+		y.Neg(&y)
+	}
+	// the cofactor is 1
+	res.X, res.Y = x, y
+
+	return res, nil
+}
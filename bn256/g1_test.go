@@ -17,15 +17,21 @@
 package bn256
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"math/big"
 	"math/bits"
+	"math/rand"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/consensys/gurvy/bn256/fp"
 	"github.com/consensys/gurvy/bn256/fr"
 	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
 )
 
@@ -387,6 +393,52 @@ func TestG1Ops(t *testing.T) {
 		genScalar,
 	))
 
+	properties.Property("[BN256] constant time scalar multiplication should output the same result as ScalarMultiplication", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var r big.Int
+			var sNeg fr.Element
+			var op1, op2, op3, op4 G1Jac
+			s.ToBigIntRegular(&r)
+			op1.ScalarMultiplication(&g1Gen, &r)
+			op2.ScalarMultiplicationCT(&g1Gen, &s)
+			sNeg.Neg(&s)
+			op3.Neg(&op1)
+			op4.ScalarMultiplicationCT(&g1Gen, &sNeg)
+			return op1.Equal(&op2) && op3.Equal(&op4)
+
+		},
+		genScalar,
+	))
+
+	properties.Property("[BN256] constant time scalar multiplication should handle small scalars, r-1 and the point at infinity", prop.ForAll(
+		func() bool {
+
+			var s fr.Element
+			var r big.Int
+			var op1, op2 G1Jac
+			for _, v := range []uint64{0, 1, 2, 3, 16, 17} {
+				s.SetUint64(v)
+				r.SetUint64(v)
+				op1.ScalarMultiplication(&g1Gen, &r)
+				op2.ScalarMultiplicationCT(&g1Gen, &s)
+				if !op1.Equal(&op2) {
+					return false
+				}
+			}
+			s.SetOne().Neg(&s)
+			op1.Neg(&g1Gen)
+			op2.ScalarMultiplicationCT(&g1Gen, &s)
+			if !op1.Equal(&op2) {
+				return false
+			}
+			s.SetUint64(5)
+			op2.ScalarMultiplicationCT(&g1Infinity, &s)
+			return op2.Z.IsZero()
+
+		},
+	))
+
 	// note : this test is here as we expect to have a different multiExp than the above bucket method
 	// for small number of points
 	properties.Property("[BN256] Multi exponentation (<50points) should be consistant with sum of square", prop.ForAll(
@@ -453,6 +505,42 @@ func TestG1MultiExp(t *testing.T) {
 	scalar.Mul(&scalar, new(big.Int).SetInt64(2*nbSamples+1))
 	scalar.Div(&scalar, new(big.Int).SetInt64(6))
 
+	properties.Property("[BN256] Multi exponentation with batch affine buckets should handle repeated, opposite and infinity points", prop.ForAll(
+		func(mixer fr.Element) bool {
+
+			// few distinct points, so that buckets receive equal and opposite points
+			const nbPoints = 1000
+			points := make([]G1Affine, nbPoints)
+			scalars := make([]fr.Element, nbPoints)
+			for i := 0; i < nbPoints; i++ {
+				switch i % 4 {
+				case 0:
+					points[i] = samplePoints[0]
+				case 1:
+					points[i].Neg(&samplePoints[0])
+				case 2:
+					points[i] = samplePoints[i%7]
+				case 3:
+					// infinity
+				}
+				scalars[i].SetUint64(uint64(i % 13)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			var result, expected G1Jac
+			opt := NewMultiExpOptions(runtime.NumCPU())
+			scalars = partitionScalars(scalars, 16)
+			opt.lock.Lock()
+			result.msmC16(points, scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
+			opt.lock.Lock()
+			expected.msmC16(points, scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			return result.Equal(&expected)
+		},
+		genScalar,
+	))
+
 	properties.Property("[BN256] Multi exponentation (c=4) should be consistant with sum of square", prop.ForAll(
 		func(mixer fr.Element) bool {
 
@@ -471,14 +559,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 4)
-			result.msmC4(samplePoints[:], scalars, opt)
+			result.msmC4(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC4(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -501,14 +593,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 5)
-			result.msmC5(samplePoints[:], scalars, opt)
+			result.msmC5(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC5(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -531,14 +627,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 6)
-			result.msmC6(samplePoints[:], scalars, opt)
+			result.msmC6(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC6(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -561,14 +661,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 7)
-			result.msmC7(samplePoints[:], scalars, opt)
+			result.msmC7(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC7(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -591,14 +695,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 8)
-			result.msmC8(samplePoints[:], scalars, opt)
+			result.msmC8(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC8(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -621,14 +729,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 9)
-			result.msmC9(samplePoints[:], scalars, opt)
+			result.msmC9(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC9(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -651,14 +763,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 10)
-			result.msmC10(samplePoints[:], scalars, opt)
+			result.msmC10(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC10(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -681,14 +797,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 11)
-			result.msmC11(samplePoints[:], scalars, opt)
+			result.msmC11(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC11(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -711,14 +831,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 12)
-			result.msmC12(samplePoints[:], scalars, opt)
+			result.msmC12(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC12(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -741,14 +865,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 13)
-			result.msmC13(samplePoints[:], scalars, opt)
+			result.msmC13(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC13(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -771,14 +899,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 14)
-			result.msmC14(samplePoints[:], scalars, opt)
+			result.msmC14(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC14(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -801,14 +933,18 @@ func TestG1MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], 15)
-			result.msmC15(samplePoints[:], scalars, opt)
+			result.msmC15(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine G1Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC15(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 			// compute expected result with double and add
 			var finalScalar, mixerBigInt big.Int
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -833,14 +969,18 @@ func TestG1MultiExp(t *testing.T) {
 				opt := NewMultiExpOptions(runtime.NumCPU())
 				opt.lock.Lock()
 				scalars := partitionScalars(sampleScalars[:], 16)
-				result.msmC16(samplePoints[:], scalars, opt)
+				result.msmC16(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+				var resultBatchAffine G1Jac
+				opt.lock.Lock()
+				resultBatchAffine.msmC16(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
 				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 				expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-				return result.Equal(&expected)
+				return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 			},
 			genScalar,
 		))
@@ -867,14 +1007,18 @@ func TestG1MultiExp(t *testing.T) {
 				opt := NewMultiExpOptions(runtime.NumCPU())
 				opt.lock.Lock()
 				scalars := partitionScalars(sampleScalars[:], 20)
-				result.msmC20(samplePoints[:], scalars, opt)
+				result.msmC20(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+				var resultBatchAffine G1Jac
+				opt.lock.Lock()
+				resultBatchAffine.msmC20(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
 				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 				expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-				return result.Equal(&expected)
+				return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 			},
 			genScalar,
 		))
@@ -901,14 +1045,18 @@ func TestG1MultiExp(t *testing.T) {
 				opt := NewMultiExpOptions(runtime.NumCPU())
 				opt.lock.Lock()
 				scalars := partitionScalars(sampleScalars[:], 21)
-				result.msmC21(samplePoints[:], scalars, opt)
+				result.msmC21(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+				var resultBatchAffine G1Jac
+				opt.lock.Lock()
+				resultBatchAffine.msmC21(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
 				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 				expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-				return result.Equal(&expected)
+				return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 			},
 			genScalar,
 		))
@@ -935,14 +1083,18 @@ func TestG1MultiExp(t *testing.T) {
 				opt := NewMultiExpOptions(runtime.NumCPU())
 				opt.lock.Lock()
 				scalars := partitionScalars(sampleScalars[:], 22)
-				result.msmC22(samplePoints[:], scalars, opt)
+				result.msmC22(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+				var resultBatchAffine G1Jac
+				opt.lock.Lock()
+				resultBatchAffine.msmC22(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
 				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 				expected.ScalarMultiplication(&g1Gen, &finalScalar)
 
-				return result.Equal(&expected)
+				return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 			},
 			genScalar,
 		))
@@ -952,159 +1104,881 @@ func TestG1MultiExp(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
-func TestG1BatchScalarMultiplication(t *testing.T) {
+func TestG1MultiExpSmallScalars(t *testing.T) {
 
 	parameters := gopter.DefaultTestParameters()
-	parameters.MinSuccessfulTests = 10
+	parameters.MinSuccessfulTests = 5
 
 	properties := gopter.NewProperties(parameters)
 
-	genScalar := GenFr()
-
-	// size of the multiExps
-	const nbSamples = 500
+	// enough points for the batch affine sums of msmTernary
+	const nbSamples = batchAffineMinPoints + 100
 
-	properties.Property("[BN256] BatchScalarMultiplication should be consistant with individual scalar multiplications", prop.ForAll(
-		func(mixer fr.Element) bool {
-			// mixer ensures that all the words of a fpElement are set
-			var sampleScalars [nbSamples]fr.Element
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		// repeated points, so that the accumulators see doublings and opposite points
+		if i%100 == 0 {
+			g.Set(&g1Gen)
+		}
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
 
-			for i := 1; i <= nbSamples; i++ {
-				sampleScalars[i-1].SetUint64(uint64(i)).
-					MulAssign(&mixer).
-					FromMont()
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne).FromMont()
+
+	properties.Property("[BN256] Multi exponentation with scalars in {-1, 0, 1} should output the sum of the points", prop.ForAll(
+		func(seed int64, signed bool) bool {
+			rnd := rand.New(rand.NewSource(seed))
+			scalars := make([]fr.Element, nbSamples)
+			var expected G1Jac
+			expected.Set(&g1Infinity)
+			for i := 0; i < nbSamples; i++ {
+				var q G1Jac
+				q.FromAffine(&samplePoints[i])
+				switch rnd.Intn(3) {
+				case 1:
+					scalars[i].SetOne().FromMont()
+					expected.AddAssign(&q)
+				case 2:
+					if signed {
+						scalars[i] = minusOne
+						expected.SubAssign(&q)
+					}
+				}
+			}
+			if nbBits, ternary := scalarsBitLen(scalars); !ternary || (!signed && nbBits > 1) {
+				return false
 			}
 
-			result := BatchScalarMultiplicationG1(&g1GenAff, sampleScalars[:])
+			var result, resultSmall G1Jac
+			result.MultiExp(samplePoints, scalars)
+			resultSmall.MultiExp(samplePoints[:1000], scalars[:1000])
 
-			if len(result) != len(sampleScalars) {
+			expectedSmall := msmNaiveG1(samplePoints[:1000], scalars[:1000])
+			return result.Equal(&expected) && resultSmall.Equal(&expectedSmall)
+		},
+		gen.Int64(),
+		gen.Bool(),
+	))
+
+	properties.Property("[BN256] Multi exponentation with 32-bit scalars should output the naive multi exponentiation", prop.ForAll(
+		func(seed int64) bool {
+			const nbPoints = 200
+			rnd := rand.New(rand.NewSource(seed))
+			scalars := make([]fr.Element, nbPoints)
+			for i := 0; i < nbPoints; i++ {
+				scalars[i].SetUint64(uint64(rnd.Uint32())).FromMont()
+			}
+			if nbBits, ternary := scalarsBitLen(scalars); ternary || nbBits > 32 {
 				return false
 			}
 
-			for i := 0; i < len(result); i++ {
-				var expectedJac G1Jac
-				var expected G1Affine
-				var b big.Int
-				expectedJac.mulGLV(&g1Gen, sampleScalars[i].ToBigInt(&b))
-				expected.FromJacobian(&expectedJac)
-				if !result[i].Equal(&expected) {
-					return false
-				}
-			}
-			return true
+			var result G1Jac
+			result.MultiExp(samplePoints[:nbPoints], scalars)
+			expected := msmNaiveG1(samplePoints[:nbPoints], scalars)
+			return result.Equal(&expected)
 		},
-		genScalar,
+		gen.Int64(),
+	))
+
+	properties.Property("[BN256] scalarsBitLen should not consider -1 as a small scalar with larger scalars", prop.ForAll(
+		func(a uint64) bool {
+			scalars := []fr.Element{minusOne, {}}
+			scalars[1].SetUint64(a | 2).FromMont()
+			nbBits, ternary := scalarsBitLen(scalars)
+			return !ternary && nbBits == fr.Bits
+		},
+		gen.UInt64(),
 	))
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
-// ------------------------------------------------------------
-// benches
+func TestG1MultiExpBestC(t *testing.T) {
+	// small scalars need less windows, which makes larger windows cheaper
+	const nbPoints = 1 << 16
+	small, large := msmBestCG1(nbPoints, 8), msmBestCG1(nbPoints, fr.Bits)
+	if small >= large {
+		t.Fatalf("8-bit scalars should use smaller windows than %d-bit scalars, got c=%d and c=%d", fr.Bits, small, large)
+	}
+	if c := msmBestCG1(1<<24, fr.Bits); c < large {
+		t.Fatal("more points should not use smaller windows")
+	}
+}
 
-func BenchmarkG1BatchScalarMul(b *testing.B) {
-	// ensure every words of the scalars are filled
-	var mixer fr.Element
-	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+// msmNaiveG1 returns Σ scalars[i]·points[i] with one scalar multiplication per point
+func msmNaiveG1(points []G1Affine, scalars []fr.Element) G1Jac {
+	var res, q G1Jac
+	res.Set(&g1Infinity)
+	for i := 0; i < len(scalars); i++ {
+		var s big.Int
+		scalars[i].ToBigInt(&s)
+		q.ScalarMultiplication(new(G1Jac).FromAffine(&points[i]), &s)
+		res.AddAssign(&q)
+	}
+	return res
+}
 
-	const pow = 15
-	const nbSamples = 1 << pow
+func TestG1MultiExpBatchAffineSkewed(t *testing.T) {
+	// most of the points target the same few buckets: the conflicts must neither be requeued
+	// indefinitely nor make the batch affine accumulation slower than the jacobian one
+	const nbPoints = batchAffineMinPoints
+	points := make([]G1Affine, nbPoints)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbPoints; i++ {
+		points[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
 
-	var sampleScalars [nbSamples]fr.Element
+	var mixer fr.Element
+	mixer.SetRandom()
+	constant := make([]fr.Element, nbPoints)
+	repeated := make([]fr.Element, nbPoints)
+	for i := 0; i < nbPoints; i++ {
+		constant[i] = mixer
+		repeated[i].SetUint64(uint64(i%3+1)).Mul(&repeated[i], &mixer)
+	}
 
-	for i := 1; i <= nbSamples; i++ {
-		sampleScalars[i-1].SetUint64(uint64(i)).
-			Mul(&sampleScalars[i-1], &mixer).
-			FromMont()
+	for name, scalars := range map[string][]fr.Element{"constant": constant, "repeated": repeated} {
+		for i := 0; i < nbPoints; i++ {
+			scalars[i].FromMont()
+		}
+		scalars = partitionScalars(scalars, 16)
+		opt := NewMultiExpOptions(runtime.NumCPU())
+
+		var result, expected G1Jac
+		start := time.Now()
+		opt.lock.Lock()
+		expected.msmC16(points, scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+		tJac := time.Since(start)
+
+		start = time.Now()
+		opt.lock.Lock()
+		result.msmC16(points, scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
+		tBatchAffine := time.Since(start)
+
+		if !result.Equal(&expected) {
+			t.Fatalf("%s scalars: batch affine and jacobian multi exponentiations differ", name)
+		}
+		if tBatchAffine > 10*tJac {
+			t.Fatalf("%s scalars: batch affine multi exponentiation took %s, jacobian %s", name, tBatchAffine, tJac)
+		}
 	}
+}
 
-	for i := 5; i <= pow; i++ {
-		using := 1 << i
+func TestMultiExpManyG1(t *testing.T) {
 
-		b.Run(fmt.Sprintf("%d points", using), func(b *testing.B) {
-			b.ResetTimer()
-			for j := 0; j < b.N; j++ {
-				_ = BatchScalarMultiplicationG1(&g1GenAff, sampleScalars[:using])
-			}
-		})
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 5
+
+	properties := gopter.NewProperties(parameters)
+
+	const nbSamples = 500
+
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
 	}
-}
 
-func BenchmarkG1ScalarMul(b *testing.B) {
+	properties.Property("[BN256] MultiExpMany should output the same results as MultiExp on each vector", prop.ForAll(
+		func(seed int64) bool {
+			rnd := rand.New(rand.NewSource(seed))
+			scalars := make([][]fr.Element, 4)
+			for v := 0; v < len(scalars); v++ {
+				scalars[v] = make([]fr.Element, nbSamples)
+				for i := 0; i < nbSamples; i++ {
+					switch v {
+					case 0:
+						scalars[v][i].SetRandom().FromMont()
+					case 1:
+						// small scalars
+						scalars[v][i].SetUint64(uint64(rnd.Uint32())).FromMont()
+					case 2:
+						// zeros
+					case 3:
+						scalars[v][i].SetUint64(uint64(i)).Neg(&scalars[v][i]).FromMont()
+					}
+				}
+			}
 
-	var scalar big.Int
-	r := fr.Modulus()
-	scalar.SetString("5243587517512619047944770508185965837690552500527637822603658699938581184513", 10)
-	scalar.Add(&scalar, r)
+			results, err := MultiExpManyG1(samplePoints, scalars, NewMultiExpOptions(runtime.NumCPU()))
+			if err != nil || len(results) != len(scalars) {
+				return false
+			}
+			for v := 0; v < len(scalars); v++ {
+				var expected G1Jac
+				expected.MultiExp(samplePoints, scalars[v])
+				if !results[v].Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		gen.Int64(),
+	))
 
-	var doubleAndAdd G1Jac
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
-	b.Run("double and add", func(b *testing.B) {
-		b.ResetTimer()
-		for j := 0; j < b.N; j++ {
-			doubleAndAdd.ScalarMultiplication(&g1Gen, &scalar)
-		}
-	})
+	if results, err := MultiExpManyG1(samplePoints, nil); err != nil || len(results) != 0 {
+		t.Fatal("MultiExpMany with no vector should output no result")
+	}
 
-	var glv G1Jac
-	b.Run("GLV", func(b *testing.B) {
-		b.ResetTimer()
-		for j := 0; j < b.N; j++ {
-			glv.mulGLV(&g1Gen, &scalar)
+	// ternary vectors with -1
+	var minusOne, one fr.Element
+	minusOne.SetOne().Neg(&minusOne).FromMont()
+	one.SetOne().FromMont()
+	ternary := [][]fr.Element{
+		{minusOne, one, one},
+		{one, minusOne, minusOne},
+		{one, one, one},
+	}
+	results, err := MultiExpManyG1(samplePoints[:3], ternary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 0; v < len(ternary); v++ {
+		var expected G1Jac
+		expected.MultiExp(samplePoints[:3], ternary[v])
+		if !results[v].Equal(&expected) {
+			t.Fatal("MultiExpMany should output the same results as MultiExp on scalars in {-1, 0, 1}")
 		}
-	})
-
-}
+	}
 
-func BenchmarkG1Add(b *testing.B) {
-	var a G1Jac
-	a.Double(&g1Gen)
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		a.AddAssign(&g1Gen)
+	scalars := [][]fr.Element{make([]fr.Element, nbSamples), make([]fr.Element, nbSamples-1)}
+	if _, err := MultiExpManyG1(samplePoints, scalars); err == nil {
+		t.Fatal("MultiExpMany should fail when a vector doesn't have one scalar per point")
 	}
 }
 
-func BenchmarkG1mAdd(b *testing.B) {
-	var a g1JacExtended
-	a.double(&g1GenAff)
+func TestG1MultiExpReaderAt(t *testing.T) {
 
-	var c G1Affine
-	c.FromJacobian(&g1Gen)
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		a.mAdd(&c)
-	}
+	const nbSamples = 500
 
-}
+	samplePoints := make([]G1Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		if i%50 != 7 {
+			// and a few points at infinity
+			samplePoints[i].FromJacobian(&g)
+		}
+		g.AddAssign(&g1Gen)
+		sampleScalars[i].SetRandom().FromMont()
+	}
 
-func BenchmarkG1AddMixed(b *testing.B) {
-	var a G1Jac
-	a.Double(&g1Gen)
+	var expected G1Jac
+	expected.MultiExp(samplePoints, sampleScalars)
 
-	var c G1Affine
-	c.FromJacobian(&g1Gen)
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		a.AddMixed(&c)
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, RawEncoding())
+	if err := enc.Encode(samplePoints); err != nil {
+		t.Fatal(err)
+	}
+	// skip the length prefix of the slice
+	r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 4, int64(buf.Len()-4))
+
+	const memoryPerPoint = 3*SizeOfG1AffineUncompressed + SizeOfFr
+	for _, segmentSize := range []int{1, 77, 250, nbSamples, 2 * nbSamples} {
+		var result G1Jac
+		if _, err := result.MultiExpReaderAt(context.Background(), r, sampleScalars, segmentSize*memoryPerPoint); err != nil {
+			t.Fatal(err)
+		}
+		if !result.Equal(&expected) {
+			t.Fatalf("MultiExpReaderAt with segments of %d points and MultiExp results differ", segmentSize)
+		}
 	}
 
-}
+	var result G1Jac
+	if _, err := result.MultiExpReaderAt(context.Background(), r, sampleScalars, memoryPerPoint-1); err == nil {
+		t.Fatal("MultiExpReaderAt should fail when a point doesn't fit in the memory budget")
+	}
 
-func BenchmarkG1Double(b *testing.B) {
-	var a G1Jac
-	a.Set(&g1Gen)
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		a.DoubleAssign()
+	truncated := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 4, int64(buf.Len()-5))
+	if _, err := result.MultiExpReaderAt(context.Background(), truncated, sampleScalars, 77*memoryPerPoint); err == nil {
+		t.Fatal("MultiExpReaderAt should fail on a truncated input")
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := result.MultiExpReaderAt(ctx, r, sampleScalars, 77*memoryPerPoint); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
 }
 
-func BenchmarkG1MultiExpG1(b *testing.B) {
-	// ensure every words of the scalars are filled
-	var mixer fr.Element
-	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+func TestG1MultiExpContext(t *testing.T) {
+
+	const nbSamples = 500
+
+	var samplePoints [nbSamples]G1Affine
+	var sampleScalars [nbSamples]fr.Element
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 1; i <= nbSamples; i++ {
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			MulAssign(&mixer).
+			FromMont()
+	}
+
+	var expected G1Jac
+	expected.MultiExp(samplePoints[:], sampleScalars[:])
+
+	// progress is reported once per chunk, and the result matches MultiExp
+	var result G1Jac
+	var nbCalls, lastDone, lastTotal int
+	opt := NewMultiExpOptions(runtime.NumCPU())
+	opt.Progress = func(done, total int) {
+		nbCalls++
+		lastDone, lastTotal = done, total
+	}
+	if _, err := result.MultiExpContext(context.Background(), samplePoints[:], sampleScalars[:], opt); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(&expected) {
+		t.Fatal("MultiExpContext and MultiExp results differ")
+	}
+	if nbCalls == 0 || nbCalls != lastDone || lastDone != lastTotal {
+		t.Fatalf("unexpected progress: %d calls, last reported %d/%d", nbCalls, lastDone, lastTotal)
+	}
+
+	// mismatched lengths
+	if _, err := result.MultiExpContext(context.Background(), samplePoints[:], sampleScalars[:nbSamples-1]); err == nil {
+		t.Fatal("MultiExpContext should fail on mismatched lengths")
+	}
+
+	// cancelled before the start
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result.Set(&g1Gen)
+	if _, err := result.MultiExpContext(ctx, samplePoints[:], sampleScalars[:]); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !result.Equal(&g1Gen) {
+		t.Fatal("a cancelled MultiExpContext should not modify its receiver")
+	}
+
+	// cancelled after the first chunk, with chunks processed one at a time
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	opt = NewMultiExpOptions(1)
+	opt.Progress = func(done, total int) {
+		cancel()
+	}
+	if _, err := result.MultiExpContext(ctx, samplePoints[:], sampleScalars[:], opt); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// the semaphore is released by cancelled multi exponentiations
+	opt.Progress = nil
+	if _, err := result.MultiExpContext(context.Background(), samplePoints[:], sampleScalars[:], opt); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(&expected) {
+		t.Fatal("MultiExpContext and MultiExp results differ")
+	}
+
+	// cancelled while the tokens of the shared options are held by another call
+	var ternaryScalars [nbSamples]fr.Element
+	for i := 0; i < nbSamples; i += 2 {
+		ternaryScalars[i].SetOne().FromMont()
+	}
+	for _, scalars := range [][]fr.Element{sampleScalars[:], ternaryScalars[:]} {
+		<-opt.chCpus
+		ctx, cancel := context.WithCancel(context.Background())
+		chErr := make(chan error, 1)
+		go func(scalars []fr.Element) {
+			var r G1Jac
+			_, err := r.MultiExpContext(ctx, samplePoints[:], scalars, opt)
+			chErr <- err
+		}(scalars)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		select {
+		case err := <-chErr:
+			if err != context.Canceled {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("a cancelled MultiExpContext should not wait for the cpu tokens")
+		}
+		opt.chCpus <- struct{}{}
+	}
+}
+
+func TestG1PrecomputedBases(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	const nbSamples = 50
+
+	var samplePoints [nbSamples]G1Affine
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 1; i <= nbSamples; i++ {
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+
+	properties.Property("[BN256] MultiExpPrecomputed should output the same result as MultiExp", prop.ForAll(
+		func(mixer fr.Element) bool {
+			var sampleScalars [nbSamples]fr.Element
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			var expected G1Jac
+			expected.MultiExp(samplePoints[:], sampleScalars[:])
+
+			for _, factor := range []int{1, 2, 4, 7} {
+				bases, err := NewPrecomputedBasesG1(samplePoints[:], factor)
+				if err != nil {
+					return false
+				}
+				var result G1Jac
+				if _, err := result.MultiExpPrecomputed(bases, sampleScalars[:]); err != nil || !result.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.Property("[BN256] PrecomputedBases should be unchanged by WriteTo (or WriteRawTo) then ReadFrom", prop.ForAll(
+		func(mixer fr.Element, raw bool) bool {
+			var sampleScalars [nbSamples]fr.Element
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			const factor = 3
+			bases, err := NewPrecomputedBasesG1(samplePoints[:], factor)
+			if err != nil {
+				return false
+			}
+			var buf bytes.Buffer
+			var written int64
+			pointSize := SizeOfG1AffineCompressed
+			if raw {
+				written, err = bases.WriteRawTo(&buf)
+				pointSize = SizeOfG1AffineUncompressed
+			} else {
+				written, err = bases.WriteTo(&buf)
+			}
+			// factor, slice length and points
+			if err != nil || written != int64(buf.Len()) || written != int64(4+4+factor*nbSamples*pointSize) {
+				return false
+			}
+			var decoded PrecomputedBasesG1
+			read, err := decoded.ReadFrom(&buf)
+			if err != nil || read != written || decoded.Factor() != factor || decoded.Len() != nbSamples {
+				return false
+			}
+
+			var result, expected G1Jac
+			if _, err := result.MultiExpPrecomputed(&decoded, sampleScalars[:]); err != nil {
+				return false
+			}
+			if _, err := expected.MultiExpPrecomputed(bases, sampleScalars[:]); err != nil {
+				return false
+			}
+			return result.Equal(&expected)
+		},
+		genScalar,
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	if _, err := NewPrecomputedBasesG1(samplePoints[:], 0); err == nil {
+		t.Fatal("NewPrecomputedBases should reject a factor of 0")
+	}
+	var invalid bytes.Buffer
+	invalid.Write([]byte{0, 0, 0, 0})
+	var decoded PrecomputedBasesG1
+	if _, err := decoded.ReadFrom(&invalid); err == nil {
+		t.Fatal("ReadFrom should reject a factor of 0")
+	}
+
+	bases, err := NewPrecomputedBasesG1(samplePoints[:], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result G1Jac
+	result.Set(&g1Gen)
+	if _, err := result.MultiExpPrecomputed(bases, make([]fr.Element, nbSamples-1)); err == nil {
+		t.Fatal("MultiExpPrecomputed should fail when there isn't one scalar per base")
+	}
+	if _, err := result.MultiExpPrecomputed(&PrecomputedBasesG1{}, nil); err == nil {
+		t.Fatal("MultiExpPrecomputed should reject uninitialized bases")
+	}
+	if !result.Equal(&g1Gen) {
+		t.Fatal("a failed MultiExpPrecomputed should not modify its receiver")
+	}
+}
+
+func TestG1BatchScalarMultiplication(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	// size of the multiExps
+	const nbSamples = 500
+
+	properties.Property("[BN256] BatchScalarMultiplication should be consistant with individual scalar multiplications", prop.ForAll(
+		func(mixer fr.Element) bool {
+			// mixer ensures that all the words of a fpElement are set
+			var sampleScalars [nbSamples]fr.Element
+
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			result := BatchScalarMultiplicationG1(&g1GenAff, sampleScalars[:])
+
+			if len(result) != len(sampleScalars) {
+				return false
+			}
+
+			for i := 0; i < len(result); i++ {
+				var expectedJac G1Jac
+				var expected G1Affine
+				var b big.Int
+				expectedJac.mulGLV(&g1Gen, sampleScalars[i].ToBigInt(&b))
+				expected.FromJacobian(&expectedJac)
+				if !result[i].Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestG1FixedBaseTable(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	const nbSamples = 100
+
+	properties.Property("[BN256] FixedBaseTable.Mul should output the same result as ScalarMultiplication", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var r big.Int
+			var expected G1Jac
+			s.ToBigIntRegular(&r)
+			expected.ScalarMultiplication(&g1Gen, &r)
+			res := G1GeneratorTable().Mul(&r)
+
+			var a G1Affine
+			a.Neg(&g1GenAff)
+			table := NewFixedBaseTableG1(&a)
+			res2 := table.Mul(&r)
+			res2.Neg(&res2)
+
+			return res.Equal(&expected) && res2.Equal(&expected)
+		},
+		genScalar,
+	))
+
+	properties.Property("[BN256] FixedBaseTable.MulBatch should be consistant with BatchScalarMultiplication", prop.ForAll(
+		func(mixer fr.Element) bool {
+			// mixer ensures that all the words of a fpElement are set
+			var sampleScalars [nbSamples]fr.Element
+
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+			sampleScalars[0].SetZero()
+
+			result := G1GeneratorTable().MulBatch(sampleScalars[:])
+			expected := BatchScalarMultiplicationG1(&g1GenAff, sampleScalars[:])
+
+			if len(result) != len(expected) {
+				return false
+			}
+			for i := 0; i < len(result); i++ {
+				if !result[i].Equal(&expected[i]) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.Property("[BN256] FixedBaseTable of infinity should output infinity", prop.ForAll(
+		func(s fr.Element) bool {
+			var r big.Int
+			var inf G1Affine
+			s.ToBigIntRegular(&r)
+			res := NewFixedBaseTableG1(&inf).Mul(&r)
+			return res.Z.IsZero()
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// the top window of r-1 leaves room for a carry, which partitionScalars would drop
+	var rMinusOne big.Int
+	rMinusOne.Sub(fr.Modulus(), big.NewInt(1))
+	if top := new(big.Int).Rsh(&rMinusOne, fr.Limbs*64-fixedBaseWindow); top.Int64() >= (1<<(fixedBaseWindow-1))-1 {
+		t.Fatal("the top window of the fixed base tables may carry")
+	}
+
+	// the largest scalars, r-1 and r-2
+	for _, k := range []int64{1, 2} {
+		var s big.Int
+		s.Sub(fr.Modulus(), big.NewInt(k))
+		var expected G1Jac
+		expected.ScalarMultiplication(&g1Gen, &s)
+		res := G1GeneratorTable().Mul(&s)
+		if !res.Equal(&expected) {
+			t.Fatalf("FixedBaseTable.Mul(r-%d) should output the same result as ScalarMultiplication", k)
+		}
+
+		var sFr fr.Element
+		sFr.SetBigInt(&s).FromMont()
+		batch := G1GeneratorTable().MulBatch([]fr.Element{sFr})
+		var expectedAff G1Affine
+		expectedAff.FromJacobian(&expected)
+		if !batch[0].Equal(&expectedAff) {
+			t.Fatalf("FixedBaseTable.MulBatch(r-%d) should output the same result as ScalarMultiplication", k)
+		}
+	}
+}
+
+func TestRandomG1(t *testing.T) {
+
+	r1 := rand.New(rand.NewSource(42))
+	r2 := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 10; i++ {
+		p1, err := RandomG1(r1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2, err := RandomG1(r2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p1.Equal(&p2) {
+			t.Fatal("RandomG1 should be deterministic for a given source")
+		}
+		if p1.IsInfinity() || !p1.IsInSubGroup() {
+			t.Fatal("RandomG1 should output a point in the r-torsion")
+		}
+	}
+
+	if _, err := RandomG1(bytes.NewReader(nil)); err != io.EOF {
+		t.Fatal("RandomG1 should forward the reader error, got", err)
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkG1BatchScalarMul(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const pow = 15
+	const nbSamples = 1 << pow
+
+	var sampleScalars [nbSamples]fr.Element
+
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+	}
+
+	for i := 5; i <= pow; i++ {
+		using := 1 << i
+
+		b.Run(fmt.Sprintf("%d points", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				_ = BatchScalarMultiplicationG1(&g1GenAff, sampleScalars[:using])
+			}
+		})
+	}
+}
+
+func BenchmarkG1FixedBaseTable(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 10
+
+	var sampleScalars [nbSamples]fr.Element
+
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+	}
+
+	var scalar big.Int
+	sampleScalars[nbSamples-1].ToBigInt(&scalar)
+
+	b.Run("build", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			_ = NewFixedBaseTableG1(&g1GenAff)
+		}
+	})
+
+	table := G1GeneratorTable()
+
+	b.Run("Mul", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			_ = table.Mul(&scalar)
+		}
+	})
+
+	b.Run(fmt.Sprintf("MulBatch %d points", nbSamples), func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			_ = table.MulBatch(sampleScalars[:])
+		}
+	})
+}
+
+func BenchmarkG1ScalarMul(b *testing.B) {
+
+	var scalar big.Int
+	r := fr.Modulus()
+	scalar.SetString("5243587517512619047944770508185965837690552500527637822603658699938581184513", 10)
+	scalar.Add(&scalar, r)
+
+	var doubleAndAdd G1Jac
+
+	b.Run("double and add", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			doubleAndAdd.ScalarMultiplication(&g1Gen, &scalar)
+		}
+	})
+
+	var glv G1Jac
+	b.Run("GLV", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			glv.mulGLV(&g1Gen, &scalar)
+		}
+	})
+
+	var ct G1Jac
+	var scalarFr fr.Element
+	scalarFr.SetBigInt(&scalar)
+	b.Run("constant time", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			ct.ScalarMultiplicationCT(&g1Gen, &scalarFr)
+		}
+	})
+
+}
+
+func BenchmarkG1IsInSubGroup(b *testing.B) {
+	var a G1Jac
+	a.Set(&g1Gen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.IsInSubGroup()
+	}
+}
+
+func BenchmarkG1Add(b *testing.B) {
+	var a G1Jac
+	a.Double(&g1Gen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.AddAssign(&g1Gen)
+	}
+}
+
+func BenchmarkG1mAdd(b *testing.B) {
+	var a g1JacExtended
+	a.double(&g1GenAff)
+
+	var c G1Affine
+	c.FromJacobian(&g1Gen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.mAdd(&c)
+	}
+
+}
+
+func BenchmarkG1AddMixed(b *testing.B) {
+	var a G1Jac
+	a.Double(&g1Gen)
+
+	var c G1Affine
+	c.FromJacobian(&g1Gen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.AddMixed(&c)
+	}
+
+}
+
+func BenchmarkG1Double(b *testing.B) {
+	var a G1Jac
+	a.Set(&g1Gen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.DoubleAssign()
+	}
+
+}
+
+func BenchmarkG1MultiExpG1(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
 
 	const pow = (bits.UintSize / 2) - (bits.UintSize / 8) // 24 on 64 bits arch, 12 on 32 bits
 	const nbSamples = 1 << pow
@@ -1132,3 +2006,209 @@ func BenchmarkG1MultiExpG1(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkG1MultiExpBatchAffine(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const pow = 16
+	const nbSamples = 1 << pow
+
+	samplePoints := make([]G1Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+
+	// distinct points, as batch affine additions are slow for doublings
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+
+	var testPoint G1Jac
+
+	for i := 12; i <= pow; i += 2 {
+		using := 1 << i
+
+		b.Run(fmt.Sprintf("%d points extended Jacobian", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using])
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d points batch affine", using), func(b *testing.B) {
+			opt := NewMultiExpOptions(runtime.NumCPU())
+			opt.BatchAffine = true
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using], opt)
+			}
+		})
+	}
+}
+
+func BenchmarkG1MultiExpPrecomputed(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 14
+
+	samplePoints := make([]G1Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+
+	var testPoint G1Jac
+
+	b.Run("no precomputation", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			testPoint.MultiExp(samplePoints, sampleScalars)
+		}
+	})
+
+	for _, factor := range []int{2, 4, 8} {
+		bases, _ := NewPrecomputedBasesG1(samplePoints, factor)
+		b.Run(fmt.Sprintf("factor %d", factor), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpPrecomputed(bases, sampleScalars)
+			}
+		})
+	}
+}
+
+func BenchmarkG1MultiExpSmallScalars(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne).FromMont()
+
+	rnd := rand.New(rand.NewSource(0))
+	for _, nbBits := range []int{1, 0, 32, 64, fr.Bits} {
+		scalars := make([]fr.Element, nbSamples)
+		name := fmt.Sprintf("%d bits", nbBits)
+		for i := 0; i < nbSamples; i++ {
+			switch nbBits {
+			case 0:
+				// {-1, 0, 1}
+				name = "ternary"
+				if r := rnd.Intn(3); r == 2 {
+					scalars[i] = minusOne
+				} else {
+					scalars[i].SetUint64(uint64(r)).FromMont()
+				}
+			case fr.Bits:
+				scalars[i].SetRandom().FromMont()
+			default:
+				scalars[i].SetUint64(rnd.Uint64() >> (64 - nbBits)).FromMont()
+			}
+		}
+
+		var testPoint G1Jac
+		b.Run(name, func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints, scalars)
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpManyG1(b *testing.B) {
+	const nbSamples = 1 << 16
+	const nbVectors = 4
+
+	samplePoints := make([]G1Affine, nbSamples)
+	scalars := make([][]fr.Element, nbVectors)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+	for v := 0; v < nbVectors; v++ {
+		scalars[v] = make([]fr.Element, nbSamples)
+		for i := 0; i < nbSamples; i++ {
+			scalars[v][i].SetRandom().FromMont()
+		}
+	}
+
+	b.Run(fmt.Sprintf("%d x MultiExp", nbVectors), func(b *testing.B) {
+		var testPoint G1Jac
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			for v := 0; v < nbVectors; v++ {
+				testPoint.MultiExp(samplePoints, scalars[v])
+			}
+		}
+	})
+
+	b.Run("MultiExpMany", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			MultiExpManyG1(samplePoints, scalars)
+		}
+	})
+}
+
+func BenchmarkG1MultiExpReaderAt(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]G1Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+		sampleScalars[i].SetRandom().FromMont()
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, RawEncoding())
+	if err := enc.Encode(samplePoints); err != nil {
+		b.Fatal(err)
+	}
+	r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 4, int64(buf.Len()-4))
+
+	var testPoint G1Jac
+	b.Run("in memory", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			testPoint.MultiExp(samplePoints, sampleScalars)
+		}
+	})
+
+	const memoryPerPoint = 3*SizeOfG1AffineUncompressed + SizeOfFr
+	for _, nbSegments := range []int{4, 16} {
+		b.Run(fmt.Sprintf("%d segments", nbSegments), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpReaderAt(context.Background(), r, sampleScalars, nbSamples/nbSegments*memoryPerPoint)
+			}
+		})
+	}
+}
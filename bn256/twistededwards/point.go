@@ -17,6 +17,7 @@ limitations under the License.
 package twistededwards
 
 import (
+	"math/big"
 	"math/bits"
 
 	"github.com/consensys/gurvy/bn256/fr"
@@ -136,11 +137,11 @@ func (p *PointProj) Add(p1, p2 *PointProj) *PointProj {
 	res.X.Mul(&H, &I).
 		Sub(&res.X, &C).
 		Sub(&res.X, &D).
-		Mul(&res.X, &p1.Z).
+		Mul(&res.X, &A).
 		Mul(&res.X, &F)
 	H.Mul(&ecurve.A, &C)
 	res.Y.Sub(&D, &H).
-		Mul(&res.Y, &p.Z).
+		Mul(&res.Y, &A).
 		Mul(&res.Y, &G)
 	res.Z.Mul(&F, &G)
 
@@ -206,3 +207,64 @@ func (p *Point) ScalarMul(p1 *Point, scalar fr.Element) *Point {
 
 	return p
 }
+
+// ScalarMultiplicationCT scalar multiplication of a point in constant time
+// with respect to the scalar, using a Montgomery ladder with conditional swaps.
+// The twisted Edwards addition law is complete so no exceptional case occurs.
+// p1 points on the twisted Edwards curve
+// scal scalar NOT in Montgomery form
+// modifies p
+func (p *Point) ScalarMultiplicationCT(p1 *Point, scalar fr.Element) *Point {
+
+	var r0, r1 PointProj
+	r0.X.SetZero()
+	r0.Y.SetOne()
+	r0.Z.SetOne()
+
+	r1.FromAffine(p1)
+
+	const wordSize = bits.UintSize
+
+	for i := fr.Limbs - 1; i >= 0; i-- {
+		for j := wordSize - 1; j >= 0; j-- {
+			b := (scalar[i] >> uint64(j)) & 1
+			r0.cswap(&r1, b)
+			r1.Add(&r1, &r0)
+			r0.Double(&r0)
+			r0.cswap(&r1, b)
+		}
+	}
+
+	p.fromProjCT(&r0)
+
+	return p
+}
+
+// frModulusMinusTwo is the exponent of the field inversion in fromProjCT
+var frModulusMinusTwo = new(big.Int).Sub(fr.Modulus(), big.NewInt(2))
+
+// fromProjCT sets p in affine from p1 in projective, like FromProj, but inverts Z in constant time:
+// 1/Z = Z^{r-2}, the exponent being fixed (FromProj uses a binary extended Euclid, which is not constant time)
+func (p *Point) fromProjCT(p1 *PointProj) *Point {
+	var zInv fr.Element
+	zInv.Exp(p1.Z, frModulusMinusTwo)
+	p.X.Mul(&p1.X, &zInv)
+	p.Y.Mul(&p1.Y, &zInv)
+	return p
+}
+
+// cswap swaps p and p1 if c == 1 and leaves them unchanged if c == 0, in constant time
+func (p *PointProj) cswap(p1 *PointProj, c uint64) {
+	mask := -c
+	for i := 0; i < fr.Limbs; i++ {
+		t := (p.X[i] ^ p1.X[i]) & mask
+		p.X[i] ^= t
+		p1.X[i] ^= t
+		t = (p.Y[i] ^ p1.Y[i]) & mask
+		p.Y[i] ^= t
+		p1.Y[i] ^= t
+		t = (p.Z[i] ^ p1.Z[i]) & mask
+		p.Z[i] ^= t
+		p1.Z[i] ^= t
+	}
+}
@@ -79,6 +79,44 @@ func TestAddProj(t *testing.T) {
 
 }
 
+func TestAddProjNotNormalized(t *testing.T) {
+
+	var p1, p2, expected Point
+	var p1proj, p2proj PointProj
+
+	p1.X.SetString("8728367628344135467582547753719073727968275979035063555332785894244029982715")
+	p1.Y.SetString("8834462946188529904793384347374734779374831553974460136522409595751449858199")
+
+	p2.X.SetString("9560056125663567360314373555170485462871740364163814576088225107862234393497")
+	p2.Y.SetString("13024071698463677601393829581435828705327146000694268918451707151508990195684")
+
+	expected.Add(&p1, &p2)
+
+	// the projective addition must hold for any representative (λX:λY:λZ) of the inputs
+	var l1, l2 fr.Element
+	l1.SetUint64(3)
+	l2.SetUint64(5)
+	p1proj.FromAffine(&p1)
+	p1proj.X.Mul(&p1proj.X, &l1)
+	p1proj.Y.Mul(&p1proj.Y, &l1)
+	p1proj.Z.Mul(&p1proj.Z, &l1)
+	p2proj.FromAffine(&p2)
+	p2proj.X.Mul(&p2proj.X, &l2)
+	p2proj.Y.Mul(&p2proj.Y, &l2)
+	p2proj.Z.Mul(&p2proj.Z, &l2)
+
+	p1proj.Add(&p1proj, &p2proj)
+	p1.FromProj(&p1proj)
+
+	if !p1.X.Equal(&expected.X) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p1.Y.Equal(&expected.Y) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
+
 func TestDouble(t *testing.T) {
 
 	var p Point
@@ -150,3 +188,55 @@ func TestScalarMul(t *testing.T) {
 	}
 
 }
+
+func TestScalarMulCT(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+
+	scalars := make([]fr.Element, 4, 14)
+	scalars[0].SetZero()
+	scalars[1].SetOne().FromMont()
+	scalars[2].SetUint64(23902374).FromMont()
+	scalars[3].SetOne().Neg(&scalars[3]).FromMont()
+	for i := 0; i < 10; i++ {
+		var s fr.Element
+		s.SetRandom().FromMont()
+		scalars = append(scalars, s)
+	}
+
+	for _, s := range scalars {
+		var p1, p2 Point
+		p1.ScalarMul(&ed.Base, s)
+		p2.ScalarMultiplicationCT(&ed.Base, s)
+		if !p1.X.Equal(&p2.X) || !p1.Y.Equal(&p2.Y) {
+			t.Fatal("constant time scalar multiplication doesn't match ScalarMul")
+		}
+	}
+}
+
+func TestFromProjCT(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+
+	for i := 0; i < 10; i++ {
+		var s, z fr.Element
+		s.SetRandom().FromMont()
+		z.SetRandom()
+
+		// random point, with a random (non normalized) Z
+		var p Point
+		var pProj PointProj
+		p.ScalarMul(&ed.Base, s)
+		pProj.FromAffine(&p)
+		pProj.X.Mul(&pProj.X, &z)
+		pProj.Y.Mul(&pProj.Y, &z)
+		pProj.Z.Mul(&pProj.Z, &z)
+
+		var p1, p2 Point
+		p1.FromProj(&pProj)
+		p2.fromProjCT(&pProj)
+		if !p1.X.Equal(&p2.X) || !p1.Y.Equal(&p2.Y) || !p2.X.Equal(&p.X) || !p2.Y.Equal(&p.Y) {
+			t.Fatal("fromProjCT doesn't match FromProj")
+		}
+	}
+}
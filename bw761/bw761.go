@@ -72,6 +72,10 @@ var lambdaGLV big.Int
 // in ker((u,v)->u+vlambda[r]), and their determinant
 var glvBasis utils.Lattice
 
+// lambdaGT is the eigenvalue of the Frobenius on GT (p mod r), glvBasisGT the associated lattice
+var lambdaGT big.Int
+var glvBasisGT utils.Lattice
+
 // generator of the curve
 var xGen big.Int
 
@@ -107,6 +111,8 @@ func init() {
 	lambdaGLV.SetString("80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410945", 10) // (x**5-3*x**4+3*x**3-x+1)
 	_r := fr.Modulus()
 	utils.PrecomputeLattice(_r, &lambdaGLV, &glvBasis)
+	lambdaGT.Mod(fp.Modulus(), _r)
+	utils.PrecomputeLattice(_r, &lambdaGT, &glvBasisGT)
 
 	xGen.SetString("9586122913090633729", 10)
 
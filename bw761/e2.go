@@ -15,116 +15,141 @@
 package bw761
 
 import (
+	"errors"
+
 	"github.com/consensys/gurvy/bw761/fp"
 )
 
-// e2 is a degree-two finite field extension of fp.Element
-type e2 struct {
+// SizeOfE2 is the size in bytes of an E2 element, in regular form
+const SizeOfE2 = 2 * SizeOfFp
+
+// E2 is a degree-two finite field extension of fp.Element
+type E2 struct {
 	A0, A1 fp.Element
 }
 
 // Equal returns true if z equals x, fasle otherwise
-func (z *e2) Equal(x *e2) bool {
+func (z *E2) Equal(x *E2) bool {
 	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1)
 }
 
-// SetString sets a e2 element from strings
-func (z *e2) SetString(s1, s2 string) *e2 {
+// SetString sets a E2 element from strings
+func (z *E2) SetString(s1, s2 string) *E2 {
 	z.A0.SetString(s1)
 	z.A1.SetString(s2)
 	return z
 }
 
-// SetZero sets an e2 elmt to zero
-func (z *e2) SetZero() *e2 {
+// SetZero sets an E2 elmt to zero
+func (z *E2) SetZero() *E2 {
 	z.A0.SetZero()
 	z.A1.SetZero()
 	return z
 }
 
 // Clone returns a copy of self
-func (z *e2) Clone() *e2 {
-	return &e2{
+func (z *E2) Clone() *E2 {
+	return &E2{
 		A0: z.A0,
 		A1: z.A1,
 	}
 }
 
-// Set sets an e2 from x
-func (z *e2) Set(x *e2) *e2 {
+// Set sets an E2 from x
+func (z *E2) Set(x *E2) *E2 {
 	z.A0.Set(&x.A0)
 	z.A1.Set(&x.A1)
 	return z
 }
 
 // SetOne sets z to 1 in Montgomery form and returns z
-func (z *e2) SetOne() *e2 {
+func (z *E2) SetOne() *E2 {
 	z.A0.SetOne()
 	z.A1.SetZero()
 	return z
 }
 
 // SetRandom sets a0 and a1 to random values
-func (z *e2) SetRandom() *e2 {
+func (z *E2) SetRandom() *E2 {
 	z.A0.SetRandom()
 	z.A1.SetRandom()
 	return z
 }
 
-// IsZero returns true if the two elements are equal, fasle otherwise
-func (z *e2) IsZero() bool {
+// IsZero returns true if z is zero, false otherwise
+func (z *E2) IsZero() bool {
 	return z.A0.IsZero() && z.A1.IsZero()
 }
 
-// Neg negates an e2 element
-func (z *e2) Neg(x *e2) *e2 {
+// Neg negates an E2 element
+func (z *E2) Neg(x *E2) *E2 {
 	z.A0.Neg(&x.A0)
 	z.A1.Neg(&x.A1)
 	return z
 }
 
 // String implements Stringer interface for fancy printing
-func (z *e2) String() string {
+func (z *E2) String() string {
 	return (z.A0.String() + "+" + z.A1.String() + "*u")
 }
 
 // ToMont converts to mont form
-func (z *e2) ToMont() *e2 {
+func (z *E2) ToMont() *E2 {
 	z.A0.ToMont()
 	z.A1.ToMont()
 	return z
 }
 
 // FromMont converts from mont form
-func (z *e2) FromMont() *e2 {
+func (z *E2) FromMont() *E2 {
 	z.A0.FromMont()
 	z.A1.FromMont()
 	return z
 }
 
-// Add adds two elements of e2
-func (z *e2) Add(x, y *e2) *e2 {
+// Bytes returns the regular (non montgomery) value of z as a big-endian byte array
+// A1 | A0
+func (z *E2) Bytes() (res [SizeOfE2]byte) {
+	putFp(res[:SizeOfFp], &z.A1)
+	putFp(res[SizeOfFp:], &z.A0)
+	return
+}
+
+// SetBytes interprets buf as the big-endian encoding A1 | A0 of an E2 element, and sets z to it
+// It returns an error if buf is not SizeOfE2 bytes long, or if a coordinate is not reduced modulo q
+func (z *E2) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfE2 {
+		return errors.New("invalid buffer size")
+	}
+	if err := readFp(&z.A1, buf[:SizeOfFp]); err != nil {
+		return err
+	}
+	return readFp(&z.A0, buf[SizeOfFp:])
+}
+
+// Add adds two elements of E2
+func (z *E2) Add(x, y *E2) *E2 {
 	z.A0.Add(&x.A0, &y.A0)
 	z.A1.Add(&x.A1, &y.A1)
 	return z
 }
 
-// Sub two elements of e2
-func (z *e2) Sub(x, y *e2) *e2 {
+// Sub two elements of E2
+func (z *E2) Sub(x, y *E2) *E2 {
 	z.A0.Sub(&x.A0, &y.A0)
 	z.A1.Sub(&x.A1, &y.A1)
 	return z
 }
 
-// Double doubles an e2 element
-func (z *e2) Double(x *e2) *e2 {
+// Double doubles an E2 element
+func (z *E2) Double(x *E2) *E2 {
 	z.A0.Double(&x.A0)
 	z.A1.Double(&x.A1)
 	return z
 }
 
-// Mul sets z to the e2-product of x,y, returns z
-func (z *e2) Mul(x, y *e2) *e2 {
+// Mul sets z to the E2-product of x,y, returns z
+func (z *E2) Mul(x, y *E2) *E2 {
 	var a, b, c fp.Element
 	a.Add(&x.A0, &x.A1)
 	b.Add(&y.A0, &y.A1)
@@ -136,16 +161,16 @@ func (z *e2) Mul(x, y *e2) *e2 {
 	return z
 }
 
-// MulAssign sets z to the e2-product of z,x returns z
-func (z *e2) MulAssign(x *e2) *e2 {
-	var t e2
+// MulAssign sets z to the E2-product of z,x returns z
+func (z *E2) MulAssign(x *E2) *E2 {
+	var t E2
 	t.Mul(z, x)
 	z.Set(&t)
 	return z
 }
 
-// Square sets z to the e2-product of x,x returns z
-func (z *e2) Square(x *e2) *e2 {
+// Square sets z to the E2-product of x,x returns z
+func (z *E2) Square(x *E2) *E2 {
 	// algo 22 https://eprint.iacr.org/2010/354.pdf
 	var c0, c2 fp.Element
 	c2.Double(&x.A1).Double(&c2).Neg(&c2).AddAssign(&x.A0)
@@ -157,8 +182,8 @@ func (z *e2) Square(x *e2) *e2 {
 	return z
 }
 
-// MulByNonResidue multiplies a e2 by (0,1)
-func (z *e2) MulByNonResidue(x *e2) *e2 {
+// MulByNonResidue multiplies a E2 by (0,1)
+func (z *E2) MulByNonResidue(x *E2) *E2 {
 	a := x.A0
 	b := x.A1 // fetching x.A1 in the function below is slower
 	z.A0.Double(&b).Double(&z.A0).Neg(&z.A0)
@@ -166,8 +191,8 @@ func (z *e2) MulByNonResidue(x *e2) *e2 {
 	return z
 }
 
-// Inverse sets z to the e2-inverse of x, returns z
-func (z *e2) Inverse(x *e2) *e2 {
+// Inverse sets z to the E2-inverse of x, returns z
+func (z *E2) Inverse(x *E2) *E2 {
 	// Algorithm 8 from https://eprint.iacr.org/2010/354.pdf
 	var t0, t1, tmp fp.Element
 	t0.Square(&x.A0)
@@ -181,8 +206,8 @@ func (z *e2) Inverse(x *e2) *e2 {
 	return z
 }
 
-// MulByElement multiplies an element in e2 by an element in fp
-func (z *e2) MulByElement(x *e2, y *fp.Element) *e2 {
+// MulByElement multiplies an element in E2 by an element in fp
+func (z *E2) MulByElement(x *E2, y *fp.Element) *E2 {
 	var yCopy fp.Element
 	yCopy.Set(y)
 	z.A0.Mul(&x.A0, &yCopy)
@@ -190,8 +215,8 @@ func (z *e2) MulByElement(x *e2, y *fp.Element) *e2 {
 	return z
 }
 
-// Conjugate conjugates an element in e2
-func (z *e2) Conjugate(x *e2) *e2 {
+// Conjugate conjugates an element in E2
+func (z *E2) Conjugate(x *E2) *E2 {
 	z.A0.Set(&x.A0)
 	z.A1.Neg(&x.A1)
 	return z
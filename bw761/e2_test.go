@@ -23,8 +23,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	genfp := GenFp()
 
 	properties.Property("[BW761] Having the receiver as operand (addition) should output the same result", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Set(a)
 			c.Add(a, b)
 			a.Add(a, b)
@@ -36,8 +36,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (sub) should output the same result", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Set(a)
 			c.Sub(a, b)
 			a.Sub(a, b)
@@ -49,8 +49,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (mul) should output the same result", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Set(a)
 			c.Mul(a, b)
 			a.Mul(a, b)
@@ -62,8 +62,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (square) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Square(a)
 			a.Square(a)
 			return a.Equal(&b)
@@ -72,8 +72,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (neg) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Neg(a)
 			a.Neg(a)
 			return a.Equal(&b)
@@ -82,8 +82,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (double) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Double(a)
 			a.Double(a)
 			return a.Equal(&b)
@@ -92,8 +92,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.MulByNonResidue(a)
 			a.MulByNonResidue(a)
 			return a.Equal(&b)
@@ -102,8 +102,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Inverse(a)
 			a.Inverse(a)
 			return a.Equal(&b)
@@ -112,8 +112,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (Conjugate) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Conjugate(a)
 			a.Conjugate(a)
 			return a.Equal(&b)
@@ -122,8 +122,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (mul by element) should output the same result", prop.ForAll(
-		func(a *e2, b fp.Element) bool {
-			var c e2
+		func(a *E2, b fp.Element) bool {
+			var c E2
 			c.MulByElement(a, &b)
 			a.MulByElement(a, &b)
 			return a.Equal(&c)
@@ -147,8 +147,8 @@ func TestE2Ops(t *testing.T) {
 	genfp := GenFp()
 
 	properties.Property("[BN256] sub & add should leave an element invariant", prop.ForAll(
-		func(a, b *e2) bool {
-			var c e2
+		func(a, b *E2) bool {
+			var c E2
 			c.Set(a)
 			c.Add(&c, b).Sub(&c, b)
 			return c.Equal(a)
@@ -158,8 +158,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] mul & inverse should leave an element invariant", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Inverse(b)
 			c.Set(a)
 			c.Mul(&c, b).Mul(&c, &d)
@@ -170,8 +170,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] inverse twice should leave an element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Inverse(a).Inverse(&b)
 			return a.Equal(&b)
 		},
@@ -179,8 +179,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] neg twice should leave an element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Neg(a).Neg(&b)
 			return a.Equal(&b)
 		},
@@ -188,8 +188,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] square and mul should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b, c e2
+		func(a *E2) bool {
+			var b, c E2
 			b.Mul(a, a)
 			c.Square(a)
 			return b.Equal(&c)
@@ -198,8 +198,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] MulByElement MulByElement inverse should leave an element invariant", prop.ForAll(
-		func(a *e2, b fp.Element) bool {
-			var c e2
+		func(a *E2, b fp.Element) bool {
+			var c E2
 			var d fp.Element
 			d.Inverse(&b)
 			c.MulByElement(a, &b).MulByElement(&c, &d)
@@ -210,8 +210,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Double and mul by 2 should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			var c fp.Element
 			c.SetUint64(2)
 			b.Double(a)
@@ -222,8 +222,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] Mulbynonres should be the same as multiplying by (0,1)", prop.ForAll(
-		func(a *e2) bool {
-			var b, c, d e2
+		func(a *E2) bool {
+			var b, c, d E2
 			b.A1.SetOne()
 			c.MulByNonResidue(a)
 			d.Mul(a, &b)
@@ -233,8 +233,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] a + pi(a), a-pi(a) should be real", prop.ForAll(
-		func(a *e2) bool {
-			var b, c, d e2
+		func(a *E2) bool {
+			var b, c, d E2
 			var e, f fp.Element
 			b.Conjugate(a)
 			c.Add(a, &b)
@@ -246,6 +246,32 @@ func TestE2Ops(t *testing.T) {
 		genA,
 	))
 
+	properties.Property("[BW761] Bytes and SetBytes should leave an element invariant", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[BW761] a - a should be zero, a + 1 should not", prop.ForAll(
+		func(a *E2) bool {
+			var b, one E2
+			one.SetOne()
+			b.Sub(a, a)
+			if !b.IsZero() {
+				return false
+			}
+			b.Add(&b, &one)
+			return !b.IsZero()
+		},
+		genA,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
@@ -253,7 +279,7 @@ func TestE2Ops(t *testing.T) {
 // benches
 
 func BenchmarkE2Add(b *testing.B) {
-	var a, c e2
+	var a, c E2
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -263,7 +289,7 @@ func BenchmarkE2Add(b *testing.B) {
 }
 
 func BenchmarkE2Sub(b *testing.B) {
-	var a, c e2
+	var a, c E2
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -273,7 +299,7 @@ func BenchmarkE2Sub(b *testing.B) {
 }
 
 func BenchmarkE2Mul(b *testing.B) {
-	var a, c e2
+	var a, c E2
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -283,7 +309,7 @@ func BenchmarkE2Mul(b *testing.B) {
 }
 
 func BenchmarkE2MulByElement(b *testing.B) {
-	var a e2
+	var a E2
 	var c fp.Element
 	c.SetRandom()
 	a.SetRandom()
@@ -294,7 +320,7 @@ func BenchmarkE2MulByElement(b *testing.B) {
 }
 
 func BenchmarkE2Square(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -303,7 +329,7 @@ func BenchmarkE2Square(b *testing.B) {
 }
 
 func BenchmarkE2Inverse(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -312,7 +338,7 @@ func BenchmarkE2Inverse(b *testing.B) {
 }
 
 func BenchmarkE2MulNonRes(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -321,7 +347,7 @@ func BenchmarkE2MulNonRes(b *testing.B) {
 }
 
 func BenchmarkE2Conjugate(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -14,37 +14,56 @@
 
 package bw761
 
-import "math/big"
+import (
+	"errors"
+	"math/big"
 
-// e6 is a degree-three finite field extension of fp2
-type e6 struct {
-	B0, B1, B2 e2
+	"github.com/consensys/gurvy/bw761/fp"
+)
+
+// SizeOfE6 is the size in bytes of an E6 element, in regular form
+const SizeOfE6 = 3 * SizeOfE2
+
+// E6 is a degree-three finite field extension of fp2
+type E6 struct {
+	B0, B1, B2 E2
 }
 
 // Equal returns true if z equals x, fasle otherwise
 // TODO can this be deleted?  Should be able to use == operator instead
-func (z *e6) Equal(x *e6) bool {
+func (z *E6) Equal(x *E6) bool {
 	return z.B0.Equal(&x.B0) && z.B1.Equal(&x.B1) && z.B2.Equal(&x.B2)
 }
 
-// SetString sets a e6 elmt from stringf
-func (z *e6) SetString(s1, s2, s3, s4, s5, s6 string) *e6 {
+// SetString sets a E6 elmt from stringf
+func (z *E6) SetString(s1, s2, s3, s4, s5, s6 string) *E6 {
 	z.B0.SetString(s1, s2)
 	z.B1.SetString(s3, s4)
 	z.B2.SetString(s5, s6)
 	return z
 }
 
-// Set Sets a e6 elmt form another e6 elmt
-func (z *e6) Set(x *e6) *e6 {
+// Set Sets a E6 elmt form another E6 elmt
+func (z *E6) Set(x *E6) *E6 {
 	z.B0 = x.B0
 	z.B1 = x.B1
 	z.B2 = x.B2
 	return z
 }
 
+// SetZero sets an E6 elmt to zero
+func (z *E6) SetZero() *E6 {
+	*z = E6{}
+	return z
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E6) IsZero() bool {
+	return z.B0.IsZero() && z.B1.IsZero() && z.B2.IsZero()
+}
+
 // SetOne sets z to 1 in Montgomery form and returns z
-func (z *e6) SetOne() *e6 {
+func (z *E6) SetOne() *E6 {
 	z.B0.A0.SetOne()
 	z.B0.A1.SetZero()
 	z.B1.A0.SetZero()
@@ -55,7 +74,7 @@ func (z *e6) SetOne() *e6 {
 }
 
 // SetRandom set z to a random elmt
-func (z *e6) SetRandom() *e6 {
+func (z *E6) SetRandom() *E6 {
 	z.B0.SetRandom()
 	z.B1.SetRandom()
 	z.B2.SetRandom()
@@ -63,7 +82,7 @@ func (z *e6) SetRandom() *e6 {
 }
 
 // ToMont converts to Mont form
-func (z *e6) ToMont() *e6 {
+func (z *E6) ToMont() *E6 {
 	z.B0.ToMont()
 	z.B1.ToMont()
 	z.B2.ToMont()
@@ -71,54 +90,81 @@ func (z *e6) ToMont() *e6 {
 }
 
 // FromMont converts from Mont form
-func (z *e6) FromMont() *e6 {
+func (z *E6) FromMont() *E6 {
 	z.B0.FromMont()
 	z.B1.FromMont()
 	z.B2.FromMont()
 	return z
 }
 
-// Add adds two elements of e6
-func (z *e6) Add(x, y *e6) *e6 {
+// Bytes returns the regular (non montgomery) value of z as a big-endian byte array
+// B2 | B1 | B0, each E2 being encoded as A1 | A0
+func (z *E6) Bytes() (res [SizeOfE6]byte) {
+	b := z.B2.Bytes()
+	copy(res[:SizeOfE2], b[:])
+	b = z.B1.Bytes()
+	copy(res[SizeOfE2:2*SizeOfE2], b[:])
+	b = z.B0.Bytes()
+	copy(res[2*SizeOfE2:], b[:])
+	return
+}
+
+// SetBytes interprets buf as the big-endian encoding B2 | B1 | B0 of an E6 element, and sets z to it
+// It returns an error if buf is not SizeOfE6 bytes long, or if a coordinate is not reduced modulo q
+func (z *E6) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfE6 {
+		return errors.New("invalid buffer size")
+	}
+	if err := z.B2.SetBytes(buf[:SizeOfE2]); err != nil {
+		return err
+	}
+	if err := z.B1.SetBytes(buf[SizeOfE2 : 2*SizeOfE2]); err != nil {
+		return err
+	}
+	return z.B0.SetBytes(buf[2*SizeOfE2:])
+}
+
+// Add adds two elements of E6
+func (z *E6) Add(x, y *E6) *E6 {
 	z.B0.Add(&x.B0, &y.B0)
 	z.B1.Add(&x.B1, &y.B1)
 	z.B2.Add(&x.B2, &y.B2)
 	return z
 }
 
-// Neg negates the e6 number
-func (z *e6) Neg(x *e6) *e6 {
+// Neg negates the E6 number
+func (z *E6) Neg(x *E6) *E6 {
 	z.B0.Neg(&x.B0)
 	z.B1.Neg(&x.B1)
 	z.B2.Neg(&x.B2)
 	return z
 }
 
-// Sub two elements of e6
-func (z *e6) Sub(x, y *e6) *e6 {
+// Sub two elements of E6
+func (z *E6) Sub(x, y *E6) *E6 {
 	z.B0.Sub(&x.B0, &y.B0)
 	z.B1.Sub(&x.B1, &y.B1)
 	z.B2.Sub(&x.B2, &y.B2)
 	return z
 }
 
-// Double doubles an element in e6
-func (z *e6) Double(x *e6) *e6 {
+// Double doubles an element in E6
+func (z *E6) Double(x *E6) *E6 {
 	z.B0.Double(&x.B0)
 	z.B1.Double(&x.B1)
 	z.B2.Double(&x.B2)
 	return z
 }
 
-// String puts e6 elmt in string form
-func (z *e6) String() string {
+// String puts E6 elmt in string form
+func (z *E6) String() string {
 	return (z.B0.String() + "+(" + z.B1.String() + ")*v+(" + z.B2.String() + ")*v**2")
 }
 
-// Mul sets z to the e6-product of x,y, returns z
-func (z *e6) Mul(x, y *e6) *e6 {
+// Mul sets z to the E6-product of x,y, returns z
+func (z *E6) Mul(x, y *E6) *E6 {
 	// Algorithm 13 from https://eprint.iacr.org/2010/354.pdf
-	var t0, t1, t2, c0, c1, c2, tmp e2
+	var t0, t1, t2, c0, c1, c2, tmp E2
 	t0.Mul(&x.B0, &y.B0)
 	t1.Mul(&x.B1, &y.B1)
 	t2.Mul(&x.B2, &y.B2)
@@ -143,15 +189,15 @@ func (z *e6) Mul(x, y *e6) *e6 {
 	return z
 }
 
-// MulAssign sets z to the e6-product of z,y, returns z
-func (z *e6) MulAssign(x *e6) *e6 {
+// MulAssign sets z to the E6-product of z,y, returns z
+func (z *E6) MulAssign(x *E6) *E6 {
 	z.Mul(z, x)
 	return z
 }
 
-// MulByE2 multiplies x by an elements of e2
-func (z *e6) MulByE2(x *e6, y *e2) *e6 {
-	var yCopy e2
+// MulByE2 multiplies x by an elements of E2
+func (z *E6) MulByE2(x *E6, y *E2) *E6 {
+	var yCopy E2
 	yCopy.Set(y)
 	z.B0.Mul(&x.B0, &yCopy)
 	z.B1.Mul(&x.B1, &yCopy)
@@ -159,11 +205,11 @@ func (z *e6) MulByE2(x *e6, y *e2) *e6 {
 	return z
 }
 
-// Square sets z to the e6-product of x,x, returns z
-func (z *e6) Square(x *e6) *e6 {
+// Square sets z to the E6-product of x,x, returns z
+func (z *E6) Square(x *E6) *E6 {
 
 	// Algorithm 16 from https://eprint.iacr.org/2010/354.pdf
-	var c4, c5, c1, c2, c3, c0 e2
+	var c4, c5, c1, c2, c3, c0 E2
 	c4.Mul(&x.B0, &x.B1).Double(&c4)
 	c5.Square(&x.B2)
 	c1.MulByNonResidue(&c5).Add(&c1, &c4)
@@ -181,10 +227,10 @@ func (z *e6) Square(x *e6) *e6 {
 }
 
 // CyclotomicSquare https://eprint.iacr.org/2009/565.pdf, 3.2
-func (z *e6) CyclotomicSquare(x *e6) *e6 {
+func (z *E6) CyclotomicSquare(x *E6) *E6 {
 
-	var res, a e6
-	var tmp e2
+	var res, a E6
+	var tmp E2
 
 	// A
 	res.B0.Square(&x.B0)
@@ -208,11 +254,11 @@ func (z *e6) CyclotomicSquare(x *e6) *e6 {
 	return z
 }
 
-// Inverse an element in e6
-func (z *e6) Inverse(x *e6) *e6 {
+// Inverse an element in E6
+func (z *E6) Inverse(x *E6) *E6 {
 	// Algorithm 17 from https://eprint.iacr.org/2010/354.pdf
 	// step 9 is wrong in the paper it's t1-t4
-	var t0, t1, t2, t3, t4, t5, t6, c0, c1, c2, d1, d2 e2
+	var t0, t1, t2, t3, t4, t5, t6, c0, c1, c2, d1, d2 E2
 	t0.Square(&x.B0)
 	t1.Square(&x.B1)
 	t2.Square(&x.B2)
@@ -235,18 +281,26 @@ func (z *e6) Inverse(x *e6) *e6 {
 	return z
 }
 
-// Exp sets z=x**e and returns it
-func (z *e6) Exp(x *e6, e big.Int) *e6 {
-	var res e6
+// Exp sets z=x**k and returns it
+// if k is negative, z is set to the inverse of x**(-k)
+func (z *E6) Exp(x *E6, k *big.Int) *E6 {
+	var base, res E6
+	e := k
+	if k.Sign() == -1 {
+		base.Inverse(x)
+		e = new(big.Int).Neg(k)
+	} else {
+		base.Set(x)
+	}
 	res.SetOne()
 	b := e.Bytes()
 	for i := range b {
 		w := b[i]
 		mask := byte(0x80)
-		for j := 0; j < 8; j++ {
+		for j := 7; j >= 0; j-- {
 			res.Square(&res)
-			if (w&mask)>>(7-j) != 0 {
-				res.Mul(&res, x)
+			if (w&mask)>>j != 0 {
+				res.Mul(&res, &base)
 			}
 			mask = mask >> 1
 		}
@@ -254,3 +308,83 @@ func (z *e6) Exp(x *e6, e big.Int) *e6 {
 	z.Set(&res)
 	return z
 }
+
+// E3 is an element of fp3 = fp[w]/(w**3+4), the cubic subfield of E6 generated by w = v**2
+// (since v**6 = u**2 = -4). It is used to represent E6 elements of the cyclotomic subgroup
+// (e.g. GT) in compressed T2 torus form (see E6.CompressTorus).
+type E3 struct {
+	A0, A1, A2 fp.Element
+}
+
+// Equal returns true if z equals x, false otherwise
+func (z *E3) Equal(x *E3) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1) && z.A2.Equal(&x.A2)
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E3) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero() && z.A2.IsZero()
+}
+
+// toE6 sets res to the embedding of z in E6: A0 + A1*v**2 + A2*v**4, with v**4 = u*v
+func (z *E3) toE6(res *E6) {
+	res.SetZero()
+	res.B0.A0.Set(&z.A0)
+	res.B2.A0.Set(&z.A1)
+	res.B1.A1.Set(&z.A2)
+}
+
+// fromE6 sets z from x, which must be in the image of toE6
+func (z *E3) fromE6(x *E6) {
+	z.A0.Set(&x.B0.A0)
+	z.A1.Set(&x.B2.A0)
+	z.A2.Set(&x.B1.A1)
+}
+
+// CompressTorus compresses z, an element of the cyclotomic subgroup (e.g. GT), to half its size
+// using the T2 torus representation over fp3: writing z = C0 + C1*v with C0, C1 in fp3,
+// z = (y + v) / (y - v) with y = (1 + C0) / C1
+// cf "Compression in finite fields and torus-based cryptography", K. Rubin and A. Silverberg.
+// C1 is zero only for z = ±1: 1 is compressed to 0, and -1 (which is not in GT) returns an error.
+func (z *E6) CompressTorus() (E3, error) {
+	// z = a0 + a1*v + a2*v**2 + b0*v**3 + b1*v**4 + b2*v**5 (B_i = a_i + b_i*u, u = v**3)
+	// C0 = a0 + a2*w + b1*w**2, C1 = a1 + b0*w + b2*w**2
+	var res E3
+	c0 := E3{A0: z.B0.A0, A1: z.B2.A0, A2: z.B1.A1}
+	c1 := E3{A0: z.B1.A0, A1: z.B0.A1, A2: z.B2.A1}
+	if c1.IsZero() {
+		var one E3
+		one.A0.SetOne()
+		if c0.Equal(&one) {
+			return res, nil
+		}
+		return res, errors.New("invalid input: element has no torus representation")
+	}
+	var num, den E6
+	var one fp.Element
+	one.SetOne()
+	c0.A0.Add(&c0.A0, &one)
+	c0.toE6(&num)
+	c1.toE6(&den)
+	den.Inverse(&den)
+	num.Mul(&num, &den)
+	res.fromE6(&num)
+	return res, nil
+}
+
+// DecompressTorus sets z to the element of the cyclotomic subgroup whose T2 torus
+// representation is y (see CompressTorus) and returns z
+// z = (y + v) / (y - v)
+func (z *E6) DecompressTorus(y *E3) *E6 {
+	if y.IsZero() {
+		return z.SetOne()
+	}
+	var num, den E6
+	y.toE6(&num)
+	den.Set(&num)
+	num.B1.A0.SetOne()
+	den.B1.A0.SetOne()
+	den.B1.A0.Neg(&den.B1.A0)
+	den.Inverse(&den)
+	return z.Mul(&num, &den)
+}
@@ -21,8 +21,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	genB := GenE6()
 
 	properties.Property("[BW761] Having the receiver as operand (addition) should output the same result", prop.ForAll(
-		func(a, b *e6) bool {
-			var c, d e6
+		func(a, b *E6) bool {
+			var c, d E6
 			d.Set(a)
 			c.Add(a, b)
 			a.Add(a, b)
@@ -34,8 +34,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (sub) should output the same result", prop.ForAll(
-		func(a, b *e6) bool {
-			var c, d e6
+		func(a, b *E6) bool {
+			var c, d E6
 			d.Set(a)
 			c.Sub(a, b)
 			a.Sub(a, b)
@@ -47,8 +47,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (mul) should output the same result", prop.ForAll(
-		func(a, b *e6) bool {
-			var c, d e6
+		func(a, b *E6) bool {
+			var c, d E6
 			d.Set(a)
 			c.Mul(a, b)
 			a.Mul(a, b)
@@ -60,8 +60,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (square) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Square(a)
 			a.Square(a)
 			return a.Equal(&b)
@@ -70,8 +70,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (neg) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Neg(a)
 			a.Neg(a)
 			return a.Equal(&b)
@@ -80,8 +80,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (double) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Double(a)
 			a.Double(a)
 			return a.Equal(&b)
@@ -90,8 +90,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Inverse(a)
 			a.Inverse(a)
 			return a.Equal(&b)
@@ -99,10 +99,10 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 		genA,
 	))
 
-	properties.Property("[BW761] Having the receiver as operand (mul by e2) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
-			var c e2
+	properties.Property("[BW761] Having the receiver as operand (mul by E2) should output the same result", prop.ForAll(
+		func(a *E6) bool {
+			var b E6
+			var c E2
 			c.SetRandom()
 			b.MulByE2(a, &c)
 			a.MulByE2(a, &c)
@@ -112,8 +112,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (cyclotomic square) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.CyclotomicSquare(a)
 			a.CyclotomicSquare(a)
 			return a.Equal(&b)
@@ -122,8 +122,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (frobenius) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Frobenius(a)
 			a.Frobenius(a)
 			return a.Equal(&b)
@@ -132,8 +132,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (frobenius square) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.FrobeniusSquare(a)
 			a.FrobeniusSquare(a)
 			return a.Equal(&b)
@@ -142,8 +142,8 @@ func TestE6ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[BW761] Having the receiver as operand (frobenius cube) should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.FrobeniusSquare(a)
 			a.FrobeniusSquare(a)
 			return a.Equal(&b)
@@ -165,8 +165,8 @@ func TestE6Ops(t *testing.T) {
 	genB := GenE6()
 
 	properties.Property("[BN256] sub & add should leave an element invariant", prop.ForAll(
-		func(a, b *e6) bool {
-			var c e6
+		func(a, b *E6) bool {
+			var c E6
 			c.Set(a)
 			c.Add(&c, b).Sub(&c, b)
 			return c.Equal(a)
@@ -176,8 +176,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] mul & inverse should leave an element invariant", prop.ForAll(
-		func(a, b *e6) bool {
-			var c, d e6
+		func(a, b *E6) bool {
+			var c, d E6
 			d.Inverse(b)
 			c.Set(a)
 			c.Mul(&c, b).Mul(&c, &d)
@@ -188,8 +188,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] inverse twice should leave an element invariant", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Inverse(a).Inverse(&b)
 			return a.Equal(&b)
 		},
@@ -197,8 +197,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] square and mul should output the same result", prop.ForAll(
-		func(a *e6) bool {
-			var b, c e6
+		func(a *E6) bool {
+			var b, c E6
 			b.Mul(a, a)
 			c.Square(a)
 			return b.Equal(&c)
@@ -207,8 +207,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] pi**6=id", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.Frobenius(a).
 				Frobenius(&b).
 				Frobenius(&b).
@@ -221,8 +221,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] (pi**2)**3=id", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.FrobeniusSquare(a).
 				FrobeniusSquare(&b).
 				FrobeniusSquare(&b)
@@ -232,8 +232,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] (pi**3)**2=id", prop.ForAll(
-		func(a *e6) bool {
-			var b e6
+		func(a *E6) bool {
+			var b E6
 			b.FrobeniusCube(a).
 				FrobeniusCube(&b)
 			return b.Equal(a)
@@ -242,8 +242,8 @@ func TestE6Ops(t *testing.T) {
 	))
 
 	properties.Property("[BN256] cyclotomic square and square should be the same in the cyclotomic subgroup", prop.ForAll(
-		func(a *e6) bool {
-			var b, c, d e6
+		func(a *E6) bool {
+			var b, c, d E6
 			b.Frobenius(a)
 			c.FrobeniusSquare(a)
 			a.Mul(a, &b).Mul(a, &c)
@@ -256,6 +256,61 @@ func TestE6Ops(t *testing.T) {
 		genA,
 	))
 
+	properties.Property("[BW761] Bytes and SetBytes should leave an element invariant", prop.ForAll(
+		func(a *E6) bool {
+			var b E6
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[BW761] CompressTorus and DecompressTorus should leave an element of the cyclotomic subgroup invariant", prop.ForAll(
+		func(a *E6) bool {
+			var b, c, d E6
+			// easy part of the final exponentiation: b is in the cyclotomic subgroup
+			b.FrobeniusCube(a)
+			c.Inverse(a)
+			b.Mul(&b, &c)
+			c.Frobenius(&b)
+			b.Mul(&b, &c)
+			y, err := b.CompressTorus()
+			if err != nil {
+				return false
+			}
+			if !d.DecompressTorus(&y).Equal(&b) {
+				return false
+			}
+			// 1 is compressed to 0, -1 has no torus representation
+			c.SetOne()
+			y, err = c.CompressTorus()
+			if err != nil || !y.IsZero() || !d.DecompressTorus(&y).Equal(&c) {
+				return false
+			}
+			c.Neg(&c)
+			_, err = c.CompressTorus()
+			return err != nil
+		},
+		genA,
+	))
+
+	properties.Property("[BW761] a - a should be zero, a + 1 should not", prop.ForAll(
+		func(a *E6) bool {
+			var b, one E6
+			one.SetOne()
+			b.Sub(a, a)
+			if !b.IsZero() {
+				return false
+			}
+			b.Add(&b, &one)
+			return !b.IsZero()
+		},
+		genA,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
 }
@@ -264,7 +319,7 @@ func TestE6Ops(t *testing.T) {
 // benches
 
 func BenchmarkE6Add(b *testing.B) {
-	var a, c e6
+	var a, c E6
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -274,7 +329,7 @@ func BenchmarkE6Add(b *testing.B) {
 }
 
 func BenchmarkE6Sub(b *testing.B) {
-	var a, c e6
+	var a, c E6
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -284,7 +339,7 @@ func BenchmarkE6Sub(b *testing.B) {
 }
 
 func BenchmarkE6Mul(b *testing.B) {
-	var a, c e6
+	var a, c E6
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -294,7 +349,7 @@ func BenchmarkE6Mul(b *testing.B) {
 }
 
 func BenchmarkE6Square(b *testing.B) {
-	var a e6
+	var a E6
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -302,8 +357,30 @@ func BenchmarkE6Square(b *testing.B) {
 	}
 }
 
+func BenchmarkE6CompressTorus(b *testing.B) {
+	var a, c E6
+	a.SetRandom()
+	c.Inverse(&a)
+	a.FrobeniusCube(&a).Mul(&a, &c)
+	c.Frobenius(&a)
+	a.Mul(&a, &c)
+	y, _ := a.CompressTorus()
+
+	b.Run("compress", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			a.CompressTorus()
+		}
+	})
+
+	b.Run("decompress", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c.DecompressTorus(&y)
+		}
+	})
+}
+
 func BenchmarkE6Inverse(b *testing.B) {
-	var a e6
+	var a E6
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -15,11 +15,16 @@
 package bw761
 
 import (
+	"errors"
+	"math/big"
+
 	"github.com/consensys/gurvy/bw761/fp"
+	"github.com/consensys/gurvy/bw761/fr"
+	"github.com/consensys/gurvy/utils"
 )
 
 // GT target group of the pairing
-type GT = e6
+type GT = E6
 
 type lineEvaluation struct {
 	r0 fp.Element
@@ -42,6 +47,15 @@ func FinalExponentiation(z *GT, _z ...*GT) GT {
 	return result
 }
 
+// FinalExponentiationCompressed computes the final expo x**((p**6 - 1)/r)
+// and returns the result in its compressed T2 torus form, half the size of a GT element (see E6.CompressTorus)
+// It is a convenience wrapper around FinalExponentiation: the exponentiation itself runs on
+// uncompressed elements and costs the same, only the result is compressed.
+func FinalExponentiationCompressed(z *GT, _z ...*GT) (E3, error) {
+	result := FinalExponentiation(z, _z...)
+	return result.CompressTorus()
+}
+
 // FinalExponentiation sets z to the final expo x**((p**6 - 1)/r), returns z
 func (z *GT) FinalExponentiation(x *GT) *GT {
 
@@ -166,39 +180,151 @@ func (z *GT) FinalExponentiation(x *GT) *GT {
 	return z
 }
 
-// MillerLoop Miller loop
-func MillerLoop(P G1Affine, Q G2Affine) *GT {
+// Pair calculates the reduced pairing for a set of points
+// ∏ᵢ e(Pᵢ, Qᵢ)
+// pairs with a point at infinity are skipped, P and Q must have the same length (the empty product is 1)
+func Pair(P []G1Affine, Q []G2Affine) (GT, error) {
+	f, err := MillerLoop(P, Q)
+	if err != nil {
+		return GT{}, err
+	}
+	return FinalExponentiation(&f), nil
+}
 
-	var result GT
+// PairingCheck calculates the reduced pairing for a set of points and returns True if the result is One
+// ∏ᵢ e(Pᵢ, Qᵢ) =? 1
+// pairs with a point at infinity are skipped, P and Q must have the same length (the empty product is 1)
+func PairingCheck(P []G1Affine, Q []G2Affine) (bool, error) {
+	f, err := Pair(P, Q)
+	if err != nil {
+		return false, err
+	}
+	var one GT
+	one.SetOne()
+	return f.Equal(&one), nil
+}
 
-	if P.IsInfinity() || Q.IsInfinity() {
-		return &result
+// MillerLoop computes the multi-Miller loop ∏ᵢ MillerLoop(Pᵢ, Qᵢ)
+// the squarings of the accumulator are shared between the pairs, pairs with a point at infinity are skipped
+func MillerLoop(P []G1Affine, Q []G2Affine) (GT, error) {
+	if len(P) != len(Q) {
+		var one GT
+		one.SetOne()
+		return one, errors.New("invalid inputs sizes")
+	}
+	q := make([]PreparedG2, len(Q))
+	for k := 0; k < len(Q); k++ {
+		q[k] = PrepareG2(&Q[k])
 	}
+	return MillerLoopPrepared(P, q)
+}
 
-	ch := make(chan struct{}, 213)
+// PreparedG2 stores the coefficients of the lines computed during the Miller loop of a G2 point.
+// They don't depend on the G1 point and can be evaluated later at any P, which saves the G2
+// arithmetic when the same Q is used in several pairings (e.g. a verifying key)
+type PreparedG2 struct {
+	lines []lineEvaluation // nil for the point at infinity
+}
 
-	var evaluations1 [69]lineEvaluation
-	var evaluations2 [144]lineEvaluation
+// PrepareG2 computes the line coefficients of the Miller loop of Q, to be used in MillerLoopPrepared
+func PrepareG2(Q *G2Affine) PreparedG2 {
+	var res PreparedG2
+	if Q.IsInfinity() {
+		return res
+	}
 
-	var xQjac, QjacSaved G2Jac
-	xQjac.FromAffine(&Q)
-	QjacSaved.FromAffine(&Q)
+	var qJac, qAcc, qDbl G2Jac
+	var l lineEvaluation
+	qJac.FromAffine(Q)
+	qAcc.Set(&qJac)
 
-	// Miller loop part 1
-	// computes f(P), div(f)=x(Q)-([x]Q)-(x-1)(O)
+	// Miller loop part 1, div(f)=x(Q)-([x]Q)-(x-1)(O)
+	for i := len(loopCounter1) - 2; i >= 0; i-- {
+
+		qDbl.Double(&qAcc).Neg(&qDbl)
+		lineCoefficients(&qAcc, &qDbl, &l) // div(f) = 2(Q)+(-2Q)-3(O)
+		res.lines = append(res.lines, l)
+		qAcc.Neg(&qDbl)
+
+		if loopCounter1[i] == 1 {
+			lineCoefficients(&qAcc, &qJac, &l) // div(f) = (Q)+(Qk)+(-Q-Qk)-3(O)
+			res.lines = append(res.lines, l)
+			qAcc.AddAssign(&qJac)
+		}
+	}
+
+	// line through [x]Q and Q, used to compute g(P), div(g)=(x+1)(Q)-([x+1]Q)-x(O)
+	lineCoefficients(&qAcc, &qJac, &l)
+	res.lines = append(res.lines, l)
+
+	// Miller loop part 2 (qAcc = [x]Q), div(f)=(x**3-x**2-x)(Q)-([x**3-x**2-x](Q)-(x**3-x**2-x-1)(O)
+	var xQ, xQNeg G2Jac
+	xQ.Set(&qAcc)
+	xQNeg.Neg(&qAcc)
+
+	for i := len(loopCounter2) - 2; i >= 0; i-- {
+
+		qDbl.Double(&qAcc).Neg(&qDbl)
+		lineCoefficients(&qAcc, &qDbl, &l) // div(f) = 2(Q)+(-2Q)-3(O)
+		res.lines = append(res.lines, l)
+		qAcc.Neg(&qDbl)
+
+		if loopCounter2[i] == 1 {
+			lineCoefficients(&qAcc, &xQ, &l) // div(f) = (Q)+(Qk)+(-Q-Qk)-3(O)
+			res.lines = append(res.lines, l)
+			qAcc.AddAssign(&xQ)
+		} else if loopCounter2[i] == -1 {
+			lineCoefficients(&qAcc, &xQNeg, &l) // div(f) = (Q)+(-Qk)+(-Q+Qk)-3(O)
+			res.lines = append(res.lines, l)
+			qAcc.AddAssign(&xQNeg)
+		}
+	}
+
+	return res
+}
+
+// MillerLoopPrepared computes the multi-Miller loop ∏ᵢ MillerLoop(Pᵢ, Qᵢ), the Qᵢ being prepared with PrepareG2
+// pairs with a point at infinity are skipped
+func MillerLoopPrepared(P []G1Affine, Q []PreparedG2) (GT, error) {
+
+	var result GT
 	result.SetOne()
-	go preCompute1(&evaluations1, &xQjac, &P, ch)
+
+	if len(P) != len(Q) {
+		return result, errors.New("invalid inputs sizes")
+	}
+
+	// filter infinity points
+	p := make([]G1Affine, 0, len(P))
+	q := make([]*PreparedG2, 0, len(Q))
+	for k := 0; k < len(P); k++ {
+		if P[k].IsInfinity() || Q[k].lines == nil {
+			continue
+		}
+		p = append(p, P[k])
+		q = append(q, &Q[k])
+	}
+	n := len(p)
+
+	var lEval lineEvaluation
 	j := 0
+
+	// Miller loop part 1
+	// computes f(P), div(f)=x(Q)-([x]Q)-(x-1)(O)
 	for i := len(loopCounter1) - 2; i >= 0; i-- {
 
 		result.Square(&result)
-		<-ch
-		result.mulAssign(&evaluations1[j])
+		for k := 0; k < n; k++ {
+			lEval.evaluate(&q[k].lines[j], &p[k])
+			result.mulAssign(&lEval)
+		}
 		j++
 
-		if loopCounter1[i] != 0 {
-			<-ch
-			result.mulAssign(&evaluations1[j])
+		if loopCounter1[i] == 1 {
+			for k := 0; k < n; k++ {
+				lEval.evaluate(&q[k].lines[j], &p[k])
+				result.mulAssign(&lEval)
+			}
 			j++
 		}
 	}
@@ -206,50 +332,57 @@ func MillerLoop(P G1Affine, Q G2Affine) *GT {
 	// store mx=g(P), mxInv=1/g(P), div(g)=x(Q)-([x]Q)-(x-1)(O), because the second Miller loop
 	// computes f(P), div(f)=(x**3-x**2-x)(Q)-([x**3-x**2-x](Q)-(x**3-x**2-x-1)(O) and
 	// f(P)=g(P)**(u**2-u-1)*h(P), div(h)=(x**2-x-1)([x]Q)-([x**2-x-1][x]Q)-(x**2-x-2)(O)
+	// with several pairs, g(P) is the product of the g(Pk)
 	var mx, mxInv, mxplusone GT
 	mx.Set(&result)
 	mxInv.Inverse(&result)
 
 	// finishes the computation of g(P), div(g)=(x+1)(Q)-([x+1]Q)-x(O) (drop the vertical line)
-	var lEval lineEvaluation
-	lineEval(&xQjac, &QjacSaved, &P, &lEval)
-	mxplusone.Set(&mx).mulAssign(&lEval)
+	mxplusone.Set(&mx)
+	for k := 0; k < n; k++ {
+		lEval.evaluate(&q[k].lines[j], &p[k])
+		mxplusone.mulAssign(&lEval)
+	}
+	j++
 
-	// Miller loop part 2 (xQjac = [x]Q)
+	// Miller loop part 2
 	// computes f(P), div(f)=(x**3-x**2-x)(Q)-([x**3-x**2-x](Q)-(x**3-x**2-x-1)(O)
-	go preCompute2(&evaluations2, &xQjac, &P, ch)
-	j = 0
 	for i := len(loopCounter2) - 2; i >= 0; i-- {
 
 		result.Square(&result)
-		<-ch
-		result.mulAssign(&evaluations2[j])
+		for k := 0; k < n; k++ {
+			lEval.evaluate(&q[k].lines[j], &p[k])
+			result.mulAssign(&lEval)
+		}
 		j++
 
-		if loopCounter2[i] == 1 {
-			<-ch
-			result.mulAssign(&evaluations2[j]).MulAssign(&mx) // accumulate g(P), div(g)=x(Q)-([x]Q)-(x-1)(O)
-			j++
-		} else if loopCounter2[i] == -1 {
-			<-ch
-			result.mulAssign(&evaluations2[j]).MulAssign(&mxInv) // accumulate g(P), div(g)=x(Q)-([x]Q)-(x-1)(O)
+		if loopCounter2[i] != 0 {
+			for k := 0; k < n; k++ {
+				lEval.evaluate(&q[k].lines[j], &p[k])
+				result.mulAssign(&lEval)
+			}
 			j++
+			// accumulate g(P), div(g)=x(Q)-([x]Q)-(x-1)(O)
+			if loopCounter2[i] == 1 {
+				result.MulAssign(&mx)
+			} else {
+				result.MulAssign(&mxInv)
+			}
 		}
 	}
 
-	close(ch)
-
 	// g(P)*(f(P)**q)
 	// div(g)=(x+1)(Q)-([x+1]Q)-x(O)
 	// div(f)=(x**3-x**2-x)(Q)-([x**3-x**2-x](Q)-(x**3-x**2-x-1)(O)
 	result.Frobenius(&result).MulAssign(&mxplusone)
 
-	return &result
+	return result, nil
 }
 
-// lineEval computes the evaluation of the line through Q, R (on the twist) at P
+// lineCoefficients computes the coefficients of the line through Q, R (on the twist),
+// to be evaluated at P with lineEvaluation.evaluate
 // Q, R are in jacobian coordinates
-func lineEval(Q, R *G2Jac, P *G1Affine, result *lineEvaluation) {
+func lineCoefficients(Q, R *G2Jac, result *lineEvaluation) {
 
 	// converts _Q and _R to projective coords
 	var _Q, _R G2Proj
@@ -267,9 +400,14 @@ func lineEval(Q, R *G2Jac, P *G1Affine, result *lineEvaluation) {
 	result.r1.Sub(&result.r1, &_Q.Z)
 	result.r0.Sub(&result.r0, &_Q.X)
 	result.r2.Sub(&result.r2, &_Q.Y)
+}
 
-	result.r1.Mul(&result.r1, &P.X)
-	result.r0.Mul(&result.r0, &P.Y)
+// evaluate sets l to the evaluation at P of the line whose coefficients are c, and returns l
+func (l *lineEvaluation) evaluate(c *lineEvaluation, P *G1Affine) *lineEvaluation {
+	l.r1.Mul(&c.r1, &P.X)
+	l.r0.Mul(&c.r0, &P.Y)
+	l.r2.Set(&c.r2)
+	return l
 }
 
 func (z *GT) mulAssign(l *lineEvaluation) *GT {
@@ -283,65 +421,95 @@ func (z *GT) mulAssign(l *lineEvaluation) *GT {
 	return z
 }
 
-// precomputes the line evaluations used during the Miller loop.
-func preCompute1(evaluations *[69]lineEvaluation, Q *G2Jac, P *G1Affine, ch chan struct{}) {
-
-	var Q1, Qbuf G2Jac
-	Q1.Set(Q)
-	Qbuf.Set(Q)
-
-	j := 0
-
-	for i := len(loopCounter1) - 2; i >= 0; i-- {
-
-		Q1.Set(Q)
-		Q.Double(&Q1).Neg(Q)
-		lineEval(&Q1, Q, P, &evaluations[j]) // f(P), div(f) = 2(Q1)+(-2Q)-3(O)
-		Q.Neg(Q)
-		ch <- struct{}{}
-		j++
+// IsInSubGroup returns true if z is in GT, the r-torsion of the cyclotomic subgroup of E6
+// z is first checked to be in the cyclotomic subgroup, z**(p**2-p+1) == 1. There, as in the optimal
+// ate pairing, z**(x+1) * Frobenius(z)**(x**3-x**2-x) == 1 is equivalent to z**r == 1, as
+// gcd(p**2-p+1, (x+1) + p*(x**3-x**2-x)) == r (cf https://eprint.iacr.org/2021/1130.pdf)
+// GT.SetBytes doesn't perform this check, it should be done for untrusted inputs.
+func (z *GT) IsInSubGroup() bool {
+	if z.IsZero() {
+		return false
+	}
 
-		if loopCounter1[i] == 1 {
-			lineEval(Q, &Qbuf, P, &evaluations[j]) // f(P), div(f) = (Q)+(Qbuf)+(-Q-Qbuf)-3(O)
-			Q.AddAssign(&Qbuf)
-			ch <- struct{}{}
-			j++
-		}
+	// cyclotomic subgroup: Frobenius**2(z) * z == Frobenius(z)
+	var a, b GT
+	a.Frobenius(z)
+	b.FrobeniusSquare(z).Mul(&b, z)
+	if !a.Equal(&b) {
+		return false
 	}
 
+	// r-torsion: z**(x+1) * Frobenius(z**(x**3-x**2-x)) == 1
+	// in the cyclotomic subgroup, the inverse is the p**3 Frobenius
+	var x1, x2, x3, inv GT
+	x1.Expt(z)
+	x2.Expt(&x1)
+	x3.Expt(&x2)
+	a.Mul(&x1, z)
+	inv.FrobeniusCube(&x2)
+	b.Mul(&x3, &inv)
+	inv.FrobeniusCube(&x1)
+	b.Mul(&b, &inv).Frobenius(&b)
+	a.Mul(&a, &b)
+
+	var one GT
+	one.SetOne()
+	return a.Equal(&one)
 }
 
-// precomputes the line evaluations used during the Miller loop.
-func preCompute2(evaluations *[144]lineEvaluation, Q *G2Jac, P *G1Affine, ch chan struct{}) {
-
-	var Q1, Qbuf, Qneg G2Jac
-	Q1.Set(Q)
-	Qbuf.Set(Q)
-	Qneg.Neg(Q)
+// ExpGLV sets z to x**k and returns z, x being in GT
+// The Frobenius acts on GT as x -> x**lambdaGT, so k is split as k1 + k2*lambdaGT with k1, k2 about half
+// the size of r (https://www.iacr.org/archive/crypto2001/21390189.pdf), and z = x**k1 * Frobenius(x)**k2
+// is computed with a shared square-and-multiply loop in the cyclotomic subgroup.
+// The result is undefined if x is not in GT, see IsInSubGroup.
+func (z *GT) ExpGLV(x *GT, k *big.Int) *GT {
+
+	var s big.Int
+	s.Mod(k, fr.Modulus())
+	if s.Sign() == 0 {
+		return z.SetOne()
+	}
 
-	j := 0
+	// table stores [x, Frobenius(x), x*Frobenius(x)], with signs matching k1, k2
+	var table [3]GT
+	table[0].Set(x)
+	table[1].Frobenius(x)
 
-	for i := len(loopCounter2) - 2; i >= 0; i-- {
+	e := utils.SplitScalar(&s, &glvBasisGT)
+	if e[0].Sign() == -1 {
+		e[0].Neg(&e[0])
+		table[0].FrobeniusCube(&table[0]) // inverse in the cyclotomic subgroup
+	}
+	if e[1].Sign() == -1 {
+		e[1].Neg(&e[1])
+		table[1].FrobeniusCube(&table[1]) // inverse in the cyclotomic subgroup
+	}
+	table[2].Mul(&table[0], &table[1])
 
-		Q1.Set(Q)
-		Q.Double(&Q1).Neg(Q)
-		lineEval(&Q1, Q, P, &evaluations[j]) // f(P), div(f) = 2(Q1)+(-2Q)-3(O)
-		Q.Neg(Q)
-		ch <- struct{}{}
-		j++
+	var res GT
+	res.SetOne()
 
-		if loopCounter2[i] == 1 {
-			lineEval(Q, &Qbuf, P, &evaluations[j]) // f(P), div(f) = (Q)+(Qbuf)+(-Q-Qbuf)-3(O)
-			Q.AddAssign(&Qbuf)
-			ch <- struct{}{}
-			j++
-		} else if loopCounter2[i] == -1 {
-			lineEval(Q, &Qneg, P, &evaluations[j]) // f(P), div(f) = (Q)+(-Qbuf)+(-Q+Qbuf)-3(O)
-			Q.AddAssign(&Qneg)
-			ch <- struct{}{}
-			j++
+	n := e[0].BitLen()
+	if e[1].BitLen() > n {
+		n = e[1].BitLen()
+	}
+	for i := n - 1; i >= 0; i-- {
+		res.CyclotomicSquare(&res)
+		b := e[0].Bit(i) | (e[1].Bit(i) << 1)
+		if b != 0 {
+			res.Mul(&res, &table[b-1])
 		}
 	}
+
+	z.Set(&res)
+	return z
+}
+
+// ExpFr sets z to x**k and returns z, x being in GT, see ExpGLV
+func (z *GT) ExpFr(x *GT, k *fr.Element) *GT {
+	var _k big.Int
+	k.ToBigIntRegular(&_k)
+	return z.ExpGLV(x, &_k)
 }
 
 // Expt set z to x^t in GT and return z
@@ -407,7 +575,7 @@ func (z *GT) MulByVMinusThree(x *GT, y *fp.Element) *GT {
 	}
 
 	// tmp = y*(-4)**-1 * u
-	var tmp e2
+	var tmp E2
 	tmp.A0.SetZero()
 	tmp.A1.Mul(y, &fourinv)
 
@@ -435,11 +603,11 @@ func (z *GT) MulByVminusTwo(x *GT, y *fp.Element) *GT {
 	}
 
 	// tmp = y*(-4)**-1 * u
-	var tmp e2
+	var tmp E2
 	tmp.A0.SetZero()
 	tmp.A1.Mul(y, &fourinv)
 
-	var a e2
+	var a E2
 	a.MulByElement(&x.B2, y)
 	z.B2.Mul(&x.B1, &tmp)
 	z.B1.Mul(&x.B0, &tmp)
@@ -467,11 +635,11 @@ func (z *GT) MulByVminusFive(x *GT, y *fp.Element) *GT {
 	}
 
 	// tmp = y*(-4)**-1 * u
-	var tmp e2
+	var tmp E2
 	tmp.A0.SetZero()
 	tmp.A1.Mul(y, &fourinv)
 
-	var a e2
+	var a E2
 	a.Mul(&x.B2, &tmp)
 	z.B2.MulByElement(&x.B1, &tmp.A1)
 	z.B1.MulByElement(&x.B0, &tmp.A1)
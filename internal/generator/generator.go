@@ -10,7 +10,10 @@ import (
 
 	"github.com/consensys/bavard"
 	goff "github.com/consensys/goff/cmd"
+	"github.com/consensys/gurvy/internal/templates/fft"
+	"github.com/consensys/gurvy/internal/templates/field"
 	"github.com/consensys/gurvy/internal/templates/fq12over6over2"
+	"github.com/consensys/gurvy/internal/templates/kzg"
 	"github.com/consensys/gurvy/internal/templates/pairing"
 	"github.com/consensys/gurvy/internal/templates/point"
 )
@@ -26,6 +29,7 @@ type CurveConfig struct {
 	CofactorCleaning bool  // flag telling if the Cofactor cleaning is available
 	CRange           []int // multiexp bucket method: generate inner methods (with const arrays) for each c
 	PMod4            int   // 3 or 1
+	FpUnusedBits     int   // number of unused bits in the most significant word of fp elements
 }
 
 type pointConfig struct {
@@ -66,6 +70,9 @@ func NewCurveConfig(name, rTorsion, fpModulus string, glv bool, cc bool) CurveCo
 	b := r.Bytes()
 	conf.PMod4 = int(b[len(b)-1] & 3)
 
+	// unused bits in the most significant word of fp elements (available for metadata when encoding points)
+	conf.FpUnusedBits = (64 - r.BitLen()%64) % 64
+
 	// default range for C values in the multiExp
 	conf.CRange = []int{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 20, 21, 22}
 	return conf
@@ -79,6 +86,23 @@ func GenerateBaseFields(conf CurveConfig) error {
 	if err := goff.GenerateFF("fp", "Element", conf.FpModulus, filepath.Join(conf.OutputDir, "fp"), false); err != nil {
 		return err
 	}
+
+	// random sampling from an io.Reader, on top of goff's generated code
+	for _, pkg := range []string{"fr", "fp"} {
+		bavardOpts := []func(*bavard.Bavard) error{
+			bavard.Apache2("ConsenSys AG", 2020),
+			bavard.Package(pkg),
+			bavard.GeneratedBy("gurvy"),
+		}
+		pathSrc := filepath.Join(conf.OutputDir, pkg, "element_random.go")
+		if err := bavard.Generate(pathSrc, []string{field.Random}, conf, bavardOpts...); err != nil {
+			return err
+		}
+		pathSrc = filepath.Join(conf.OutputDir, pkg, "element_random_test.go")
+		if err := bavard.Generate(pathSrc, []string{field.RandomTests}, conf, bavardOpts...); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -103,7 +127,7 @@ func GenerateFq12over6over2(conf CurveConfig) error {
 
 	{
 		// fq2 assembly, generated by goff
-		if err := goff.GenerateFF2(conf.CurveName, "e2", conf.FpModulus, conf.OutputDir); err != nil {
+		if err := goff.GenerateFF2(conf.CurveName, "E2", conf.FpModulus, conf.OutputDir); err != nil {
 			return err
 		}
 	}
@@ -304,6 +328,37 @@ func GeneratePoint(_conf CurveConfig, coordType, pointName string) error {
 	return nil
 }
 
+// GenerateMarshal generates points serialization code
+func GenerateMarshal(conf CurveConfig) error {
+
+	bavardOpts := []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys AG", 2020),
+		bavard.Package(conf.CurveName),
+		bavard.GeneratedBy("gurvy"),
+		bavard.Funcs(helpers()),
+	}
+
+	src := []string{
+		point.Marshal,
+	}
+
+	pathSrc := filepath.Join(conf.OutputDir, "marshal.go")
+	if err := bavard.Generate(pathSrc, src, conf, bavardOpts...); err != nil {
+		return err
+	}
+
+	src = []string{
+		point.MarshalTests,
+	}
+
+	pathSrc = filepath.Join(conf.OutputDir, "marshal_test.go")
+	if err := bavard.Generate(pathSrc, src, conf, bavardOpts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GeneratePairingTests generates elliptic curve arithmetic
 func GeneratePairingTests(conf CurveConfig) error {
 
@@ -343,3 +398,92 @@ func GenerateDoc(conf CurveConfig) error {
 
 	return nil
 }
+
+// GenerateKZG generates the KZG polynomial commitment scheme
+func GenerateKZG(conf CurveConfig) error {
+
+	bavardOpts := []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys AG", 2020),
+		bavard.Package("kzg", "provides a KZG polynomial commitment scheme over "+conf.CurveName),
+		bavard.GeneratedBy("gurvy"),
+	}
+
+	pathSrc := filepath.Join(conf.OutputDir, "kzg", "kzg.go")
+	if err := bavard.Generate(pathSrc, []string{kzg.KZG}, conf, bavardOpts...); err != nil {
+		return err
+	}
+
+	bavardOpts = []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys AG", 2020),
+		bavard.Package("kzg"),
+		bavard.GeneratedBy("gurvy"),
+	}
+
+	pathSrc = filepath.Join(conf.OutputDir, "kzg", "kzg_test.go")
+	if err := bavard.Generate(pathSrc, []string{kzg.KZGTests}, conf, bavardOpts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type fftConfig struct {
+	CurveConfig
+	MaxOrderRoot uint64 // 2-adicity of r-1
+	RootOfUnity  string // primitive 2^MaxOrderRoot-th root of unity
+	CosetShift   uint64 // smallest non-residue which is not in the 2-adic subgroup
+}
+
+// GenerateFFT generates the FFT domains over fr
+func GenerateFFT(conf CurveConfig) error {
+	r, ok := new(big.Int).SetString(conf.RTorsion, 10)
+	if !ok {
+		panic("can't set r from RTorsion")
+	}
+	rMinusOne := new(big.Int).Sub(r, big.NewInt(1))
+	s := rMinusOne.TrailingZeroBits()
+	q := new(big.Int).Rsh(rMinusOne, s)
+	halfRMinusOne := new(big.Int).Rsh(rMinusOne, 1)
+	twoAdicOrder := new(big.Int).Lsh(big.NewInt(1), s)
+
+	// a non residue x has x^q of order 2^s, it is not in the 2-adic subgroup if x^(2^s) != 1
+	var x uint64
+	for x = 2; ; x++ {
+		bx := new(big.Int).SetUint64(x)
+		if new(big.Int).Exp(bx, halfRMinusOne, r).Cmp(rMinusOne) == 0 &&
+			new(big.Int).Exp(bx, twoAdicOrder, r).Cmp(big.NewInt(1)) != 0 {
+			break
+		}
+	}
+
+	fftConf := fftConfig{
+		CurveConfig:  conf,
+		MaxOrderRoot: uint64(s),
+		RootOfUnity:  new(big.Int).Exp(new(big.Int).SetUint64(x), q, r).String(),
+		CosetShift:   x,
+	}
+
+	bavardOpts := []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys AG", 2020),
+		bavard.Package("fft", "provides in-place discrete Fourier transforms on the 2-adic subgroups of fr and their cosets"),
+		bavard.GeneratedBy("gurvy"),
+	}
+
+	pathSrc := filepath.Join(conf.OutputDir, "fr", "fft", "domain.go")
+	if err := bavard.Generate(pathSrc, []string{fft.Domain}, fftConf, bavardOpts...); err != nil {
+		return err
+	}
+
+	bavardOpts = []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys AG", 2020),
+		bavard.Package("fft"),
+		bavard.GeneratedBy("gurvy"),
+	}
+
+	pathSrc = filepath.Join(conf.OutputDir, "fr", "fft", "domain_test.go")
+	if err := bavard.Generate(pathSrc, []string{fft.DomainTests}, fftConf, bavardOpts...); err != nil {
+		return err
+	}
+
+	return nil
+}
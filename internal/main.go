@@ -46,6 +46,9 @@ func main() {
 		assertNoError(generator.GenerateBaseFields(confs[i]))
 		assertNoError(generator.GenerateMultiExpHelpers(confs[i]))
 		assertNoError(generator.GenerateDoc(confs[i]))
+		assertNoError(generator.GenerateMarshal(confs[i]))
+		assertNoError(generator.GenerateKZG(confs[i]))
+		assertNoError(generator.GenerateFFT(confs[i]))
 
 		if confs[i].CurveName != "bw761" {
 
@@ -59,7 +62,7 @@ func main() {
 			if confs[i].CurveName == "bn256" {
 				confs[i].CofactorCleaning = true
 			}
-			assertNoError(generator.GeneratePoint(confs[i], "e2", "g2"))
+			assertNoError(generator.GeneratePoint(confs[i], "E2", "g2"))
 			assertNoError(generator.GenerateFq12over6over2(confs[i]))
 			assertNoError(generator.GeneratePairingTests(confs[i]))
 
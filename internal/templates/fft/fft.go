@@ -0,0 +1,492 @@
+package fft
+
+// Domain ...
+const Domain = `
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gurvy/{{.CurveName}}/fr"
+	"github.com/consensys/gurvy/utils/parallel"
+)
+
+// maxOrderRoot is the 2-adicity of r-1: fr has roots of unity of order up to 2^maxOrderRoot
+const maxOrderRoot uint64 = {{.MaxOrderRoot}}
+
+// rootOfUnity is a primitive 2^maxOrderRoot-th root of unity of fr
+// cosetShift is not in any subgroup of order a power of 2, the cosets of the domains are cosetShift·<Generator>
+var rootOfUnity, cosetShift fr.Element
+
+func init() {
+	rootOfUnity.SetString("{{.RootOfUnity}}")
+	cosetShift.SetUint64({{.CosetShift}})
+}
+
+// Domain is the subgroup of the n-th roots of unity of fr, n a power of 2, on which polynomials of
+// less than n coefficients are evaluated (FFT) and interpolated (FFTInverse)
+type Domain struct {
+	Cardinality    uint64
+	CardinalityInv fr.Element
+	Generator      fr.Element // primitive n-th root of unity
+	GeneratorInv   fr.Element
+	CosetShift     fr.Element // coset FFTs evaluate on CosetShift·<Generator>
+	CosetShiftInv  fr.Element
+
+	// twiddles[i] = Generator^i for i < n/2, the inverse FFT uses Generator^-i = -twiddles[n/2-i]
+	twiddles []fr.Element
+}
+
+// NewDomain returns the domain of the n-th roots of unity, n being the smallest power of 2 >= m
+func NewDomain(m uint64) (*Domain, error) {
+	logN := uint64(bits.Len64(m - 1))
+	if m <= 1 {
+		logN = 0
+	}
+	if logN > maxOrderRoot {
+		return nil, errors.New("domain size is larger than the largest power of 2 dividing r-1")
+	}
+
+	d := &Domain{Cardinality: uint64(1) << logN}
+	d.CardinalityInv.SetUint64(d.Cardinality).Inverse(&d.CardinalityInv)
+
+	// Generator = rootOfUnity^(2^(maxOrderRoot - logN))
+	d.Generator = rootOfUnity
+	for i := logN; i < maxOrderRoot; i++ {
+		d.Generator.Square(&d.Generator)
+	}
+	d.GeneratorInv.Inverse(&d.Generator)
+	d.CosetShift = cosetShift
+	d.CosetShiftInv.Inverse(&cosetShift)
+
+	d.twiddles = make([]fr.Element, d.Cardinality/2)
+	parallel.Execute(len(d.twiddles), func(start, end int) {
+		var w fr.Element
+		w.Exp(d.Generator, new(big.Int).SetUint64(uint64(start)))
+		for i := start; i < end; i++ {
+			d.twiddles[i] = w
+			w.Mul(&w, &d.Generator)
+		}
+	})
+
+	return d, nil
+}
+
+// Decimation selects the FFT variant, and hence the order of the input and output
+type Decimation uint8
+
+const (
+	// DIT (decimation in time) takes a bit-reversed input and returns a natural order output
+	DIT Decimation = iota
+	// DIF (decimation in frequency) takes a natural order input and returns a bit-reversed output
+	DIF
+)
+
+// fftBlockSize is the number of elements of the blocks on which the FFT layers are run one after the other
+// (fitting in the cache), larger layers are run one at a time on the whole input, split between the cpus
+const fftBlockSize = 1 << 12
+
+// FFT evaluates in place the polynomial of coefficients a on the domain (on its coset if coset is set):
+// a[i] is set to the value at Generator^i (CosetShift·Generator^i), the input and output orders
+// depending on decimation. len(a) must be the cardinality of the domain
+func (d *Domain) FFT(a []fr.Element, decimation Decimation, coset bool) {
+	d.checkSize(a)
+
+	if coset {
+		// a[i] *= CosetShift^i, i being bit-reversed in DIT
+		if decimation == DIT {
+			BitReverse(a)
+		}
+		scalePowers(a, &d.CosetShift, nil)
+		if decimation == DIT {
+			BitReverse(a)
+		}
+	}
+
+	d.run(a, decimation, false)
+}
+
+// FFTInverse interpolates in place the polynomial which values on the domain (on its coset if coset is set)
+// are a, the inverse of FFT with the same parameters. The input and output orders depend on decimation
+func (d *Domain) FFTInverse(a []fr.Element, decimation Decimation, coset bool) {
+	d.checkSize(a)
+
+	d.run(a, decimation, true)
+
+	if !coset {
+		scalePowers(a, nil, &d.CardinalityInv)
+		return
+	}
+
+	// a[i] *= CosetShift^-i / n, i being bit-reversed in DIF
+	if decimation == DIF {
+		BitReverse(a)
+	}
+	scalePowers(a, &d.CosetShiftInv, &d.CardinalityInv)
+	if decimation == DIF {
+		BitReverse(a)
+	}
+}
+
+func (d *Domain) checkSize(a []fr.Element) {
+	if uint64(len(a)) != d.Cardinality {
+		panic("fft: len(a) is not the cardinality of the domain")
+	}
+}
+
+// run runs the butterfly layers of the FFT (or of the inverse FFT, without the scaling by 1/n)
+//
+// the layers on blocks larger than fftBlockSize are parallelized over the butterflies, the smaller ones
+// over the blocks, each block going through all its layers at once
+func (d *Domain) run(a []fr.Element, decimation Decimation, inverse bool) {
+	n := len(a)
+	if n == 1 {
+		return
+	}
+	blockSize := n
+	if blockSize > fftBlockSize {
+		blockSize = fftBlockSize
+	}
+
+	largeLayer := func(m int) {
+		parallel.Execute(n/2, func(start, end int) {
+			d.layer(a, m, n/(2*m), start, end, decimation, inverse)
+		})
+	}
+	blocks := func() {
+		process := func(start, end int) {
+			for k := start; k < end; k++ {
+				block := a[k*blockSize : (k+1)*blockSize]
+				if decimation == DIF {
+					for m := blockSize / 2; m >= 1; m >>= 1 {
+						d.layer(block, m, n/(2*m), 0, blockSize/2, decimation, inverse)
+					}
+				} else {
+					for m := 1; m < blockSize; m <<= 1 {
+						d.layer(block, m, n/(2*m), 0, blockSize/2, decimation, inverse)
+					}
+				}
+			}
+		}
+		if blockSize == n {
+			process(0, 1)
+		} else {
+			parallel.Execute(n/blockSize, process)
+		}
+	}
+
+	if decimation == DIF {
+		for m := n / 2; m >= blockSize; m >>= 1 {
+			largeLayer(m)
+		}
+		blocks()
+	} else {
+		blocks()
+		for m := blockSize; m < n; m <<= 1 {
+			largeLayer(m)
+		}
+	}
+}
+
+// layer applies the butterflies [start, end) of the layer of half-size m to a, the butterflies being numbered
+// across the blocks of size 2m. The twiddle of the j-th butterfly of a block is Generator^(j·stride)
+//
+// DIF: (u, v) -> (u + v, (u - v)·w)
+// DIT: (u, v) -> (u + w·v, u - w·v)
+// the inverse FFT uses w^-1 = -twiddles[n/2 - j·stride]
+func (d *Domain) layer(a []fr.Element, m, stride, start, end int, decimation Decimation, inverse bool) {
+	half := len(d.twiddles)
+	j := start % m
+	i := (start/m)*2*m + j
+	var t fr.Element
+	for b := start; b < end; b++ {
+		u, v := &a[i], &a[i+m]
+		idx := j * stride
+		switch {
+		case idx == 0:
+			t = *v
+			v.Sub(u, &t)
+			u.Add(u, &t)
+		case decimation == DIF && !inverse:
+			t.Sub(u, v)
+			u.Add(u, v)
+			v.Mul(&t, &d.twiddles[idx])
+		case decimation == DIF && inverse:
+			t.Sub(v, u)
+			u.Add(u, v)
+			v.Mul(&t, &d.twiddles[half-idx])
+		case !inverse:
+			t.Mul(v, &d.twiddles[idx])
+			v.Sub(u, &t)
+			u.Add(u, &t)
+		default:
+			t.Mul(v, &d.twiddles[half-idx])
+			v.Add(u, &t)
+			u.Sub(u, &t)
+		}
+
+		j++
+		i++
+		if j == m {
+			j = 0
+			i += m
+		}
+	}
+}
+
+// scalePowers sets a[i] = a[i]·c·g^i, g or c being ignored if nil
+func scalePowers(a []fr.Element, g, c *fr.Element) {
+	if g == nil && c == nil {
+		return
+	}
+	parallel.Execute(len(a), func(start, end int) {
+		var acc fr.Element
+		if g != nil {
+			acc.Exp(*g, new(big.Int).SetUint64(uint64(start)))
+		} else {
+			acc.SetOne()
+		}
+		if c != nil {
+			acc.Mul(&acc, c)
+		}
+		for i := start; i < end; i++ {
+			a[i].Mul(&a[i], &acc)
+			if g != nil {
+				acc.Mul(&acc, g)
+			}
+		}
+	})
+}
+
+// BitReverse permutes a in place, a[i] being swapped with a[bitReverse(i)]. len(a) must be a power of 2
+func BitReverse(a []fr.Element) {
+	n := uint64(len(a))
+	if n <= 1 {
+		return
+	}
+	shift := 64 - uint64(bits.TrailingZeros64(n))
+	parallel.Execute(len(a), func(start, end int) {
+		for i := uint64(start); i < uint64(end); i++ {
+			iRev := bits.Reverse64(i) >> shift
+			if i < iRev {
+				a[i], a[iRev] = a[iRev], a[i]
+			}
+		}
+	})
+}
+
+`
+
+// DomainTests ...
+const DomainTests = `
+
+import (
+	"math/big"
+	"math/bits"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gurvy/{{.CurveName}}/fr"
+)
+
+func TestRootOfUnity(t *testing.T) {
+	// rootOfUnity^(2^(maxOrderRoot-1)) == -1
+	r := rootOfUnity
+	for i := uint64(1); i < maxOrderRoot; i++ {
+		r.Square(&r)
+	}
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	if !r.Equal(&minusOne) {
+		t.Fatal("rootOfUnity is not a primitive 2^maxOrderRoot-th root of unity")
+	}
+
+	// cosetShift^(2^maxOrderRoot) != 1
+	s := cosetShift
+	for i := uint64(0); i < maxOrderRoot; i++ {
+		s.Square(&s)
+	}
+	var one fr.Element
+	one.SetOne()
+	if s.Equal(&one) {
+		t.Fatal("cosetShift is in the 2-adic subgroup")
+	}
+}
+
+func TestNewDomain(t *testing.T) {
+	for _, c := range []struct{ m, n uint64 }{
+		{0, 1}, {1, 1}, {2, 2}, {3, 4}, {1000, 1024}, {1024, 1024}, {1025, 2048},
+	} {
+		d, err := NewDomain(c.m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Cardinality != c.n {
+			t.Fatalf("NewDomain(%d) has cardinality %d, expected %d", c.m, d.Cardinality, c.n)
+		}
+
+		// Generator has order n
+		var g, one fr.Element
+		one.SetOne()
+		g.Exp(d.Generator, new(big.Int).SetUint64(c.n))
+		if !g.Equal(&one) {
+			t.Fatal("Generator^n != 1")
+		}
+		if c.n > 1 {
+			g.Exp(d.Generator, new(big.Int).SetUint64(c.n/2))
+			if g.Equal(&one) {
+				t.Fatal("Generator^(n/2) == 1")
+			}
+		}
+	}
+
+	if _, err := NewDomain(uint64(1)<<maxOrderRoot + 1); err == nil {
+		t.Fatal("NewDomain should reject sizes larger than 2^maxOrderRoot")
+	}
+}
+
+func TestFFT(t *testing.T) {
+	for _, n := range []uint64{1, 2, 8, 64, fftBlockSize, 4 * fftBlockSize} {
+		d, err := NewDomain(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := make([]fr.Element, n)
+		for i := range p {
+			p[i].SetRandom()
+		}
+
+		for _, decimation := range []Decimation{DIT, DIF} {
+			for _, coset := range []bool{false, true} {
+				name := "n=" + strconv.Itoa(int(n)) + " DIT"
+				if decimation == DIF {
+					name = "n=" + strconv.Itoa(int(n)) + " DIF"
+				}
+				if coset {
+					name += " coset"
+				}
+				t.Run(name, func(t *testing.T) {
+					a := make([]fr.Element, n)
+					copy(a, p)
+					if decimation == DIT {
+						BitReverse(a)
+					}
+					d.FFT(a, decimation, coset)
+					if decimation == DIF {
+						BitReverse(a)
+					}
+
+					// a[i] == p(x_i) for a few indices
+					for _, i := range []uint64{0, n / 2, n - 1} {
+						var x fr.Element
+						x.Exp(d.Generator, new(big.Int).SetUint64(i))
+						if coset {
+							x.Mul(&x, &d.CosetShift)
+						}
+						expected := evalPolynomial(p, &x)
+						if !a[i].Equal(&expected) {
+							t.Fatalf("wrong evaluation at index %d", i)
+						}
+					}
+
+					// FFTInverse takes the FFT output order
+					if decimation == DIF {
+						BitReverse(a)
+					}
+					inverseDecimation := DIT
+					if decimation == DIT {
+						inverseDecimation = DIF
+					}
+					d.FFTInverse(a, inverseDecimation, coset)
+					if inverseDecimation == DIF {
+						BitReverse(a)
+					}
+					for i := range a {
+						if !a[i].Equal(&p[i]) {
+							t.Fatal("FFTInverse(FFT(p)) != p")
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestFFTInverseSameDecimation(t *testing.T) {
+	const n = 2 * fftBlockSize
+	d, _ := NewDomain(n)
+	p := make([]fr.Element, n)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	// the output of FFT, bit-reversed, has the input order of FFTInverse with the same decimation,
+	// which outputs the bit-reversed input of FFT
+	for _, decimation := range []Decimation{DIT, DIF} {
+		a := make([]fr.Element, n)
+		copy(a, p)
+		d.FFT(a, decimation, true)
+		BitReverse(a)
+		d.FFTInverse(a, decimation, true)
+		BitReverse(a)
+		for i := range a {
+			if !a[i].Equal(&p[i]) {
+				t.Fatal("FFTInverse(FFT(p)) != p")
+			}
+		}
+	}
+}
+
+func TestBitReverse(t *testing.T) {
+	const n = 256
+	a := make([]fr.Element, n)
+	for i := range a {
+		a[i].SetUint64(uint64(i))
+	}
+	BitReverse(a)
+	for i := range a {
+		var expected fr.Element
+		expected.SetUint64(uint64(bits.Reverse8(uint8(i))))
+		if !a[i].Equal(&expected) {
+			t.Fatal("wrong permutation")
+		}
+	}
+}
+
+// evalPolynomial returns p(x) (Horner)
+func evalPolynomial(p []fr.Element, x *fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, x).Add(&res, &p[i])
+	}
+	return res
+}
+
+func BenchmarkFFT(b *testing.B) {
+	for _, logN := range []int{16, 20} {
+		n := uint64(1) << logN
+		d, _ := NewDomain(n)
+		a := make([]fr.Element, n)
+		for i := range a {
+			a[i].SetRandom()
+		}
+		for _, decimation := range []Decimation{DIT, DIF} {
+			name := "2^" + strconv.Itoa(logN) + " DIT"
+			if decimation == DIF {
+				name = "2^" + strconv.Itoa(logN) + " DIF"
+			}
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					d.FFT(a, decimation, false)
+				}
+			})
+		}
+		b.Run("2^"+strconv.Itoa(logN)+" coset", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				d.FFT(a, DIF, true)
+			}
+		})
+	}
+}
+
+`
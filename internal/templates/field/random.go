@@ -0,0 +1,96 @@
+package field
+
+// Random ...
+const Random = `
+
+import (
+	"encoding/binary"
+	"io"
+	"math/bits"
+)
+
+// SetRandomFrom sets z to a uniformly random element using the randomness read from r, and returns z
+//
+// Unlike SetRandom, the output is not biased: Bits bits are read from r until they encode a value
+// smaller than the modulus (rejection sampling). Using a seeded r makes the output reproducible.
+func (z *Element) SetRandomFrom(r io.Reader) (*Element, error) {
+	// the most significant limb holds topBits bits
+	const topBits = Bits - (Limbs-1)*64
+	const topMask = ^uint64(0) >> (64 - topBits)
+
+	var buf [Limbs * 8]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		for i := 0; i < Limbs; i++ {
+			z[i] = binary.LittleEndian.Uint64(buf[i*8:])
+		}
+		z[Limbs-1] &= topMask
+
+		if z.smallerThanModulus() {
+			return z, nil
+		}
+	}
+}
+
+// smallerThanModulus returns true if z < q, z being seen as an integer (not reduced)
+func (z *Element) smallerThanModulus() bool {
+	var b uint64
+	for i := 0; i < Limbs; i++ {
+		_, b = bits.Sub64(z[i], qElement[i], b)
+	}
+	return b == 1
+}
+
+`
+
+// RandomTests ...
+const RandomTests = `
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestElementSetRandomFrom(t *testing.T) {
+	var zero Element
+
+	r1 := rand.New(rand.NewSource(42))
+	r2 := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 1000; i++ {
+		var a, b, c Element
+		if _, err := a.SetRandomFrom(r1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := b.SetRandomFrom(r2); err != nil {
+			t.Fatal(err)
+		}
+		if !a.Equal(&b) {
+			t.Fatal("SetRandomFrom should be deterministic for a given source")
+		}
+		// a reduced element is left unchanged by a modular addition
+		c.Add(&a, &zero)
+		if !c.Equal(&a) {
+			t.Fatal("SetRandomFrom output should be smaller than the modulus")
+		}
+	}
+
+	var a Element
+	if _, err := a.SetRandomFrom(bytes.NewReader(make([]byte, Limbs*8-1))); err != io.ErrUnexpectedEOF {
+		t.Fatal("SetRandomFrom should forward the reader error, got", err)
+	}
+}
+
+func BenchmarkElementSetRandomFrom(b *testing.B) {
+	var x Element
+	r := rand.New(rand.NewSource(42))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.SetRandomFrom(r)
+	}
+}
+`
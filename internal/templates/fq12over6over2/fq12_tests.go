@@ -26,8 +26,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	genB := GenE12()
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (addition) should output the same result", prop.ForAll(
-		func(a, b *e12) bool {
-			var c, d e12
+		func(a, b *E12) bool {
+			var c, d E12
 			d.Set(a)
 			c.Add(a, b)
 			a.Add(a, b)
@@ -39,8 +39,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (sub) should output the same result", prop.ForAll(
-		func(a, b *e12) bool {
-			var c, d e12
+		func(a, b *E12) bool {
+			var c, d E12
 			d.Set(a)
 			c.Sub(a, b)
 			a.Sub(a, b)
@@ -52,8 +52,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (mul) should output the same result", prop.ForAll(
-		func(a, b *e12) bool {
-			var c, d e12
+		func(a, b *E12) bool {
+			var c, d E12
 			d.Set(a)
 			c.Mul(a, b)
 			a.Mul(a, b)
@@ -65,8 +65,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (square) should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.Square(a)
 			a.Square(a)
 			return a.Equal(&b)
@@ -75,8 +75,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (double) should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.Double(a)
 			a.Double(a)
 			return a.Equal(&b)
@@ -85,8 +85,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.Inverse(a)
 			a.Inverse(a)
 			return a.Equal(&b)
@@ -95,8 +95,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (Cyclotomic square) should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.CyclotomicSquare(a)
 			a.CyclotomicSquare(a)
 			return a.Equal(&b)
@@ -105,8 +105,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (Conjugate) should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.Conjugate(a)
 			a.Conjugate(a)
 			return a.Equal(&b)
@@ -115,8 +115,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (Frobenius) should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.Frobenius(a)
 			a.Frobenius(a)
 			return a.Equal(&b)
@@ -125,8 +125,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (FrobeniusSquare) should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.FrobeniusSquare(a)
 			a.FrobeniusSquare(a)
 			return a.Equal(&b)
@@ -135,8 +135,8 @@ func TestE12ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (FrobeniusCube) should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.FrobeniusCube(a)
 			a.FrobeniusCube(a)
 			return a.Equal(&b)
@@ -156,10 +156,11 @@ func TestE12Ops(t *testing.T) {
 
 	genA := GenE12()
 	genB := GenE12()
+	genE2 := GenE2()
 
 	properties.Property("[{{ toUpper .CurveName }}] sub & add should leave an element invariant", prop.ForAll(
-		func(a, b *e12) bool {
-			var c e12
+		func(a, b *E12) bool {
+			var c E12
 			c.Set(a)
 			c.Add(&c, b).Sub(&c, b)
 			return c.Equal(a)
@@ -169,8 +170,8 @@ func TestE12Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] mul & inverse should leave an element invariant", prop.ForAll(
-		func(a, b *e12) bool {
-			var c, d e12
+		func(a, b *E12) bool {
+			var c, d E12
 			d.Inverse(b)
 			c.Set(a)
 			c.Mul(&c, b).Mul(&c, &d)
@@ -181,8 +182,8 @@ func TestE12Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] inverse twice should leave an element invariant", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.Inverse(a).Inverse(&b)
 			return a.Equal(&b)
 		},
@@ -190,8 +191,8 @@ func TestE12Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] square and mul should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b, c e12
+		func(a *E12) bool {
+			var b, c E12
 			b.Mul(a, a)
 			c.Square(a)
 			return b.Equal(&c)
@@ -200,9 +201,9 @@ func TestE12Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] a + pi(a), a-pi(a) should be real", prop.ForAll(
-		func(a *e12) bool {
-			var b, c, d e12
-			var e, f, g e6
+		func(a *E12) bool {
+			var b, c, d E12
+			var e, f, g E6
 			b.Conjugate(a)
 			c.Add(a, &b)
 			d.Sub(a, &b)
@@ -214,8 +215,8 @@ func TestE12Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] pi**12=id", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.Frobenius(a).
 				Frobenius(&b).
 				Frobenius(&b).
@@ -234,8 +235,8 @@ func TestE12Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] (pi**2)**6=id", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.FrobeniusSquare(a).
 				FrobeniusSquare(&b).
 				FrobeniusSquare(&b).
@@ -248,8 +249,8 @@ func TestE12Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] (pi**3)**4=id", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.FrobeniusCube(a).
 				FrobeniusCube(&b).
 				FrobeniusCube(&b).
@@ -260,8 +261,8 @@ func TestE12Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] cyclotomic square and square should be the same in the cyclotomic subgroup", prop.ForAll(
-		func(a *e12) bool {
-			var b, c, d e12
+		func(a *E12) bool {
+			var b, c, d E12
 			b.FrobeniusCube(a).
 				FrobeniusCube(&b)
 			a.Inverse(a)
@@ -275,6 +276,87 @@ func TestE12Ops(t *testing.T) {
 		genA,
 	))
 
+	properties.Property("[{{ toUpper .CurveName }}] MulBy014 and MulBy034 should output the same result as a full multiplication by the sparse element", prop.ForAll(
+		func(a *E12, c0, c1, c2 *E2) bool {
+			var b, c, d, e, f E12
+			b.C0.B0.Set(c0)
+			b.C0.B1.Set(c1)
+			b.C1.B1.Set(c2)
+			c.Mul(a, &b)
+			d.Set(a).MulBy014(c0, c1, c2)
+			b.C0.B1.SetZero()
+			b.C1.B0.Set(c1)
+			e.Mul(a, &b)
+			f.Set(a).MulBy034(c0, c1, c2)
+			return c.Equal(&d) && e.Equal(&f)
+		},
+		genA,
+		genE2,
+		genE2,
+		genE2,
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] Bytes and SetBytes should leave an element invariant", prop.ForAll(
+		func(a *E12) bool {
+			var b E12
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] a - a should be zero, a + 1 should not", prop.ForAll(
+		func(a *E12) bool {
+			var b, one E12
+			one.SetOne()
+			b.Sub(a, a)
+			if !b.IsZero() {
+				return false
+			}
+			b.Add(&b, &one)
+			return !b.IsZero()
+		},
+		genA,
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] neg twice should leave an element invariant, a + (-a) should be zero", prop.ForAll(
+		func(a *E12) bool {
+			var b, c E12
+			b.Neg(a)
+			c.Add(a, &b)
+			b.Neg(&b)
+			return a.Equal(&b) && c.IsZero()
+		},
+		genA,
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] CompressTorus and DecompressTorus should leave an element of the cyclotomic subgroup invariant", prop.ForAll(
+		func(a *E12) bool {
+			var b, c, d E12
+			// easy part of the final exponentiation: b is in the cyclotomic subgroup
+			b.Conjugate(a)
+			c.Inverse(a)
+			b.Mul(&b, &c)
+			c.FrobeniusSquare(&b)
+			b.Mul(&b, &c)
+			y, err := b.CompressTorus()
+			if err != nil {
+				return false
+			}
+			if !d.DecompressTorus(&y).Equal(&b) {
+				return false
+			}
+			// 1 is compressed to 0
+			c.SetOne()
+			y, err = c.CompressTorus()
+			return err == nil && y.IsZero() && d.DecompressTorus(&y).Equal(&c)
+		},
+		genA,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
 }
@@ -283,7 +365,7 @@ func TestE12Ops(t *testing.T) {
 // benches
 
 func BenchmarkE12Add(b *testing.B) {
-	var a, c e12
+	var a, c E12
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -293,7 +375,7 @@ func BenchmarkE12Add(b *testing.B) {
 }
 
 func BenchmarkE12Sub(b *testing.B) {
-	var a, c e12
+	var a, c E12
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -303,7 +385,7 @@ func BenchmarkE12Sub(b *testing.B) {
 }
 
 func BenchmarkE12Mul(b *testing.B) {
-	var a, c e12
+	var a, c E12
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -312,8 +394,56 @@ func BenchmarkE12Mul(b *testing.B) {
 	}
 }
 
+func BenchmarkE12MulBy014(b *testing.B) {
+	var a E12
+	var c0, c1, c4 E2
+	a.SetRandom()
+	c0.SetRandom()
+	c1.SetRandom()
+	c4.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.MulBy014(&c0, &c1, &c4)
+	}
+}
+
+func BenchmarkE12MulBy034(b *testing.B) {
+	var a E12
+	var c0, c3, c4 E2
+	a.SetRandom()
+	c0.SetRandom()
+	c3.SetRandom()
+	c4.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.MulBy034(&c0, &c3, &c4)
+	}
+}
+
+func BenchmarkE12CompressTorus(b *testing.B) {
+	var a, c E12
+	a.SetRandom()
+	c.Inverse(&a)
+	a.Conjugate(&a).Mul(&a, &c)
+	c.FrobeniusSquare(&a)
+	a.Mul(&a, &c)
+	y, _ := a.CompressTorus()
+
+	b.Run("compress", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			a.CompressTorus()
+		}
+	})
+
+	b.Run("decompress", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c.DecompressTorus(&y)
+		}
+	})
+}
+
 func BenchmarkE12Cyclosquare(b *testing.B) {
-	var a e12
+	var a E12
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -322,7 +452,7 @@ func BenchmarkE12Cyclosquare(b *testing.B) {
 }
 
 func BenchmarkE12Square(b *testing.B) {
-	var a e12
+	var a E12
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -331,7 +461,7 @@ func BenchmarkE12Square(b *testing.B) {
 }
 
 func BenchmarkE12Inverse(b *testing.B) {
-	var a e12
+	var a E12
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -340,7 +470,7 @@ func BenchmarkE12Inverse(b *testing.B) {
 }
 
 func BenchmarkE12Conjugate(b *testing.B) {
-	var a e12
+	var a E12
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -349,7 +479,7 @@ func BenchmarkE12Conjugate(b *testing.B) {
 }
 
 func BenchmarkE12Frobenius(b *testing.B) {
-	var a e12
+	var a E12
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -358,7 +488,7 @@ func BenchmarkE12Frobenius(b *testing.B) {
 }
 
 func BenchmarkE12FrobeniusSquare(b *testing.B) {
-	var a e12
+	var a E12
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -367,7 +497,7 @@ func BenchmarkE12FrobeniusSquare(b *testing.B) {
 }
 
 func BenchmarkE12FrobeniusCube(b *testing.B) {
-	var a e12
+	var a E12
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -376,7 +506,7 @@ func BenchmarkE12FrobeniusCube(b *testing.B) {
 }
 
 func BenchmarkE12Expt(b *testing.B) {
-	var a e12
+	var a E12
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -385,7 +515,7 @@ func BenchmarkE12Expt(b *testing.B) {
 }
 
 func BenchmarkE12FinalExponentiation(b *testing.B) {
-	var a e12
+	var a E12
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -28,8 +28,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	genfp := GenFp()
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (addition) should output the same result", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Set(a)
 			c.Add(a, b)
 			a.Add(a, b)
@@ -41,8 +41,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (sub) should output the same result", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Set(a)
 			c.Sub(a, b)
 			a.Sub(a, b)
@@ -54,8 +54,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (mul) should output the same result", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Set(a)
 			c.Mul(a, b)
 			a.Mul(a, b)
@@ -67,8 +67,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (square) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Square(a)
 			a.Square(a)
 			return a.Equal(&b)
@@ -77,8 +77,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (neg) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Neg(a)
 			a.Neg(a)
 			return a.Equal(&b)
@@ -87,8 +87,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (double) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Double(a)
 			a.Double(a)
 			return a.Equal(&b)
@@ -97,8 +97,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.MulByNonResidue(a)
 			a.MulByNonResidue(a)
 			return a.Equal(&b)
@@ -107,8 +107,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (mul by non residue inverse) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.MulByNonResidueInv(a)
 			a.MulByNonResidueInv(a)
 			return a.Equal(&b)
@@ -117,8 +117,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Inverse(a)
 			a.Inverse(a)
 			return a.Equal(&b)
@@ -127,8 +127,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (Conjugate) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Conjugate(a)
 			a.Conjugate(a)
 			return a.Equal(&b)
@@ -137,8 +137,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (mul by element) should output the same result", prop.ForAll(
-		func(a *e2, b fp.Element) bool {
-			var c e2
+		func(a *E2, b fp.Element) bool {
+			var c E2
 			c.MulByElement(a, &b)
 			a.MulByElement(a, &b)
 			return a.Equal(&c)
@@ -148,8 +148,8 @@ func TestE2ReceiverIsOperand(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (Sqrt) should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b, c, d, s e2
+		func(a *E2) bool {
+			var b, c, d, s E2
 
 			s.Square(a)
 			a.Set(&s)
@@ -180,8 +180,8 @@ func TestE2Ops(t *testing.T) {
 	genfp := GenFp()
 
 	properties.Property("[{{ toUpper .CurveName }}] sub & add should leave an element invariant", prop.ForAll(
-		func(a, b *e2) bool {
-			var c e2
+		func(a, b *E2) bool {
+			var c E2
 			c.Set(a)
 			c.Add(&c, b).Sub(&c, b)
 			return c.Equal(a)
@@ -191,8 +191,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] mul & inverse should leave an element invariant", prop.ForAll(
-		func(a, b *e2) bool {
-			var c, d e2
+		func(a, b *E2) bool {
+			var c, d E2
 			d.Inverse(b)
 			c.Set(a)
 			c.Mul(&c, b).Mul(&c, &d)
@@ -203,8 +203,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] inverse twice should leave an element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Inverse(a).Inverse(&b)
 			return a.Equal(&b)
 		},
@@ -212,8 +212,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] neg twice should leave an element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Neg(a).Neg(&b)
 			return a.Equal(&b)
 		},
@@ -221,8 +221,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] square and mul should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b, c e2
+		func(a *E2) bool {
+			var b, c E2
 			b.Mul(a, a)
 			c.Square(a)
 			return b.Equal(&c)
@@ -231,8 +231,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] MulByElement MulByElement inverse should leave an element invariant", prop.ForAll(
-		func(a *e2, b fp.Element) bool {
-			var c e2
+		func(a *E2, b fp.Element) bool {
+			var c E2
 			var d fp.Element
 			d.Inverse(&b)
 			c.MulByElement(a, &b).MulByElement(&c, &d)
@@ -243,8 +243,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] Double and mul by 2 should output the same result", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			var c fp.Element
 			c.SetUint64(2)
 			b.Double(a)
@@ -255,8 +255,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] Mulbynonres mulbynonresinv should leave the element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.MulByNonResidue(a).MulByNonResidueInv(&b)
 			return a.Equal(&b)
 		},
@@ -264,8 +264,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] a + pi(a), a-pi(a) should be real", prop.ForAll(
-		func(a *e2) bool {
-			var b, c, d e2
+		func(a *E2) bool {
+			var b, c, d E2
 			var e, f fp.Element
 			b.Conjugate(a)
 			c.Add(a, &b)
@@ -278,8 +278,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] Legendre on square should output 1", prop.ForAll(
-		func(a *e2) bool {
-			var b e2
+		func(a *E2) bool {
+			var b E2
 			b.Square(a)
 			c := b.Legendre()
 			return c == 1
@@ -288,8 +288,8 @@ func TestE2Ops(t *testing.T) {
 	))
 
 	properties.Property("[{{ toUpper .CurveName }}] square(sqrt) should leave an element invariant", prop.ForAll(
-		func(a *e2) bool {
-			var b, c, d, e e2
+		func(a *E2) bool {
+			var b, c, d, e E2
 			b.Square(a)
 			c.Sqrt(&b)
 			d.Square(&c)
@@ -299,6 +299,32 @@ func TestE2Ops(t *testing.T) {
 		genA,
 	))
 
+	properties.Property("[{{ toUpper .CurveName }}] Bytes and SetBytes should leave an element invariant", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] a - a should be zero, a + 1 should not", prop.ForAll(
+		func(a *E2) bool {
+			var b, one E2
+			one.SetOne()
+			b.Sub(a, a)
+			if !b.IsZero() {
+				return false
+			}
+			b.Add(&b, &one)
+			return !b.IsZero()
+		},
+		genA,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
@@ -306,7 +332,7 @@ func TestE2Ops(t *testing.T) {
 // benches
 
 func BenchmarkE2Add(b *testing.B) {
-	var a, c e2
+	var a, c E2
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -316,7 +342,7 @@ func BenchmarkE2Add(b *testing.B) {
 }
 
 func BenchmarkE2Sub(b *testing.B) {
-	var a, c e2
+	var a, c E2
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -326,7 +352,7 @@ func BenchmarkE2Sub(b *testing.B) {
 }
 
 func BenchmarkE2Mul(b *testing.B) {
-	var a, c e2
+	var a, c E2
 	a.SetRandom()
 	c.SetRandom()
 	b.ResetTimer()
@@ -336,7 +362,7 @@ func BenchmarkE2Mul(b *testing.B) {
 }
 
 func BenchmarkE2MulByElement(b *testing.B) {
-	var a e2
+	var a E2
 	var c fp.Element
 	c.SetRandom()
 	a.SetRandom()
@@ -347,7 +373,7 @@ func BenchmarkE2MulByElement(b *testing.B) {
 }
 
 func BenchmarkE2Square(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -356,7 +382,7 @@ func BenchmarkE2Square(b *testing.B) {
 }
 
 func BenchmarkE2Inverse(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -365,7 +391,7 @@ func BenchmarkE2Inverse(b *testing.B) {
 }
 
 func BenchmarkE2MulNonRes(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -374,7 +400,7 @@ func BenchmarkE2MulNonRes(b *testing.B) {
 }
 
 func BenchmarkE2MulNonResInv(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -383,7 +409,7 @@ func BenchmarkE2MulNonResInv(b *testing.B) {
 }
 
 func BenchmarkE2Conjugate(b *testing.B) {
-	var a e2
+	var a E2
 	a.SetRandom()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,621 @@
+package kzg
+
+// KZG ...
+const KZG = `
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gurvy/{{.CurveName}}"
+	"github.com/consensys/gurvy/{{.CurveName}}/fr"
+	"github.com/consensys/gurvy/utils/parallel"
+)
+
+var (
+	ErrInvalidNbDigests      = errors.New("number of digests is not the same as the number of polynomials")
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (larger than SRS or == 0)")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+	ErrMinSRSSize            = errors.New("minimum SRS size is 2")
+)
+
+// Digest is a commitment to a polynomial, a point in G1
+type Digest = {{.CurveName}}.G1Affine
+
+// SRS is the structured reference string: the powers of a secret tau
+// in G1 ([1], [tau], ..., [tau^(n-1)]) and [1], [tau] in G2
+type SRS struct {
+	G1 []{{.CurveName}}.G1Affine
+	G2 [2]{{.CurveName}}.G2Affine
+}
+
+// OpeningProof is a proof that a committed polynomial p satisfies p(point) = ClaimedValue,
+// H being the commitment to the quotient (p - ClaimedValue) / (X - point)
+type OpeningProof struct {
+	H            {{.CurveName}}.G1Affine
+	ClaimedValue fr.Element
+}
+
+// BatchOpeningProof is a proof of the values of several committed polynomials at the same point,
+// H being the commitment to the quotient of a random linear combination of the polynomials
+type BatchOpeningProof struct {
+	H             {{.CurveName}}.G1Affine
+	ClaimedValues []fr.Element
+}
+
+// NewSRS returns a SRS of size (the maximum number of coefficients of the committed polynomials) from tau
+//
+// tau is the toxic waste of the setup: whoever knows it can forge proofs. NewSRS is meant for
+// tests, production SRS come from a multi party computation
+func NewSRS(size int, tau *big.Int) (*SRS, error) {
+	if size < 2 {
+		return nil, ErrMinSRSSize
+	}
+	var srs SRS
+
+	_, _, g1Aff, g2Aff := {{.CurveName}}.Generators()
+	var t fr.Element
+	t.SetBigInt(tau)
+
+	srs.G2[0] = g2Aff
+	var tauG2 {{.CurveName}}.G2Jac
+	tauG2.FromAffine(&g2Aff)
+	tauG2.ScalarMultiplication(&tauG2, t.ToBigIntRegular(new(big.Int)))
+	srs.G2[1].FromJacobian(&tauG2)
+
+	// tau^i, in regular form
+	powers := make([]fr.Element, size-1)
+	powers[0] = t
+	for i := 1; i < len(powers); i++ {
+		powers[i].Mul(&powers[i-1], &t)
+	}
+	parallel.Execute(len(powers), func(start, end int) {
+		for i := start; i < end; i++ {
+			powers[i].FromMont()
+		}
+	})
+
+	srs.G1 = make([]{{.CurveName}}.G1Affine, size)
+	srs.G1[0] = g1Aff
+	copy(srs.G1[1:], {{.CurveName}}.BatchScalarMultiplicationG1(&g1Aff, powers))
+
+	return &srs, nil
+}
+
+// Commit returns the commitment [p(tau)] to the polynomial p (coefficients in increasing degree order)
+func Commit(p []fr.Element, srs *SRS, opts ...*{{.CurveName}}.MultiExpOptions) (Digest, error) {
+	if len(p) == 0 || len(p) > len(srs.G1) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	// MultiExp scalars are in regular form
+	scalars := make([]fr.Element, len(p))
+	parallel.Execute(len(p), func(start, end int) {
+		for i := start; i < end; i++ {
+			scalars[i] = p[i]
+			scalars[i].FromMont()
+		}
+	})
+
+	var res {{.CurveName}}.G1Jac
+	res.MultiExp(srs.G1[:len(p)], scalars, opts...)
+
+	var digest Digest
+	digest.FromJacobian(&res)
+	return digest, nil
+}
+
+// Open returns a proof of the value of p at point
+func Open(p []fr.Element, point *fr.Element, srs *SRS) (OpeningProof, error) {
+	if len(p) == 0 || len(p) > len(srs.G1) {
+		return OpeningProof{}, ErrInvalidPolynomialSize
+	}
+
+	var res OpeningProof
+	res.ClaimedValue = eval(p, point)
+
+	h := dividePolyByXminusA(p, point)
+	if len(h) == 0 {
+		// p is constant, the quotient is 0 and its commitment the point at infinity
+		return res, nil
+	}
+
+	var err error
+	if res.H, err = Commit(h, srs); err != nil {
+		return OpeningProof{}, err
+	}
+	return res, nil
+}
+
+// Verify returns nil if proof is a valid proof of the value of the polynomial committed in digest at point
+//
+// it checks e([p(tau)] - [y] + point·[H], [1]) · e(-[H], [tau]) == 1 with y the claimed value
+func Verify(digest *Digest, proof *OpeningProof, point *fr.Element, srs *SRS) error {
+	_, _, g1Aff, _ := {{.CurveName}}.Generators()
+
+	// [p(tau) - y]
+	var folded, yG1 {{.CurveName}}.G1Jac
+	var tmp big.Int
+	yG1.FromAffine(&g1Aff)
+	yG1.ScalarMultiplication(&yG1, proof.ClaimedValue.ToBigIntRegular(&tmp))
+	folded.FromAffine(digest)
+	folded.SubAssign(&yG1)
+
+	// + point·[H]
+	var pointH {{.CurveName}}.G1Jac
+	pointH.FromAffine(&proof.H)
+	pointH.ScalarMultiplication(&pointH, point.ToBigIntRegular(&tmp))
+	folded.AddAssign(&pointH)
+
+	var foldedAff, negH {{.CurveName}}.G1Affine
+	foldedAff.FromJacobian(&folded)
+	negH.Neg(&proof.H)
+
+	ok, err := {{.CurveName}}.PairingCheck(
+		[]{{.CurveName}}.G1Affine{foldedAff, negH},
+		[]{{.CurveName}}.G2Affine{srs.G2[0], srs.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// BatchOpenSinglePoint returns a proof of the values of the polynomials at point, digests being
+// their commitments. The polynomials are combined with the powers of a challenge gamma derived
+// from the SRS, the digests, the point and the claimed values (Fiat-Shamir), and a single quotient is committed
+func BatchOpenSinglePoint(polynomials [][]fr.Element, digests []Digest, point *fr.Element, srs *SRS) (BatchOpeningProof, error) {
+	if len(polynomials) != len(digests) {
+		return BatchOpeningProof{}, ErrInvalidNbDigests
+	}
+	if len(polynomials) == 0 {
+		return BatchOpeningProof{}, ErrInvalidPolynomialSize
+	}
+	maxSize := 0
+	for i := 0; i < len(polynomials); i++ {
+		if len(polynomials[i]) == 0 || len(polynomials[i]) > len(srs.G1) {
+			return BatchOpeningProof{}, ErrInvalidPolynomialSize
+		}
+		if len(polynomials[i]) > maxSize {
+			maxSize = len(polynomials[i])
+		}
+	}
+
+	var res BatchOpeningProof
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	parallel.Execute(len(polynomials), func(start, end int) {
+		for i := start; i < end; i++ {
+			res.ClaimedValues[i] = eval(polynomials[i], point)
+		}
+	})
+
+	gamma := deriveGamma(point, digests, res.ClaimedValues, srs)
+
+	// Σ gamma^i·p_i
+	folded := make([]fr.Element, maxSize)
+	var acc fr.Element
+	acc.SetOne()
+	for i := 0; i < len(polynomials); i++ {
+		p := polynomials[i]
+		parallel.Execute(len(p), func(start, end int) {
+			var t fr.Element
+			for j := start; j < end; j++ {
+				t.Mul(&p[j], &acc)
+				folded[j].Add(&folded[j], &t)
+			}
+		})
+		acc.Mul(&acc, &gamma)
+	}
+
+	h := dividePolyByXminusA(folded, point)
+	if len(h) == 0 {
+		return res, nil
+	}
+	var err error
+	if res.H, err = Commit(h, srs); err != nil {
+		return BatchOpeningProof{}, err
+	}
+	return res, nil
+}
+
+// BatchVerifySinglePoint returns nil if proof is a valid proof of the values at point of the polynomials
+// committed in digests (see BatchOpenSinglePoint)
+func BatchVerifySinglePoint(digests []Digest, proof *BatchOpeningProof, point *fr.Element, srs *SRS) error {
+	if len(digests) != len(proof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	if len(digests) == 0 {
+		return ErrInvalidPolynomialSize
+	}
+
+	gamma := deriveGamma(point, digests, proof.ClaimedValues, srs)
+
+	// Σ gamma^i·digests[i] and Σ gamma^i·claimedValues[i]
+	var folded OpeningProof
+	folded.H = proof.H
+	gammas := make([]fr.Element, len(digests))
+	gammas[0].SetOne()
+	for i := 1; i < len(digests); i++ {
+		gammas[i].Mul(&gammas[i-1], &gamma)
+	}
+	for i := 0; i < len(digests); i++ {
+		var t fr.Element
+		t.Mul(&proof.ClaimedValues[i], &gammas[i])
+		folded.ClaimedValue.Add(&folded.ClaimedValue, &t)
+		gammas[i].FromMont()
+	}
+
+	var foldedDigest {{.CurveName}}.G1Jac
+	foldedDigest.MultiExp(digests, gammas)
+	var digest Digest
+	digest.FromJacobian(&foldedDigest)
+
+	return Verify(&digest, &folded, point, srs)
+}
+
+// BatchVerifyMultiPoints returns nil if proofs[i] is a valid proof of the value at points[i] of the polynomial
+// committed in digests[i], for all i
+//
+// the pairing checks of Verify are combined with random scalars lambda_i (read from crypto/rand) in
+// e(Σ lambda_i·([p_i(tau)] - [y_i] + points[i]·[H_i]), [1]) · e(-Σ lambda_i·[H_i], [tau]) == 1
+func BatchVerifyMultiPoints(digests []Digest, proofs []OpeningProof, points []fr.Element, srs *SRS) error {
+	n := len(digests)
+	if len(proofs) != n || len(points) != n {
+		return ErrInvalidNbDigests
+	}
+	if n == 0 {
+		return nil
+	}
+	if n == 1 {
+		return Verify(&digests[0], &proofs[0], &points[0], srs)
+	}
+
+	lambdas := make([]fr.Element, n)
+	lambdas[0].SetOne()
+	for i := 1; i < n; i++ {
+		if _, err := lambdas[i].SetRandomFrom(rand.Reader); err != nil {
+			return err
+		}
+	}
+
+	// Σ lambda_i·[H_i]
+	quotients := make([]{{.CurveName}}.G1Affine, n)
+	for i := 0; i < n; i++ {
+		quotients[i] = proofs[i].H
+	}
+
+	// Σ lambda_i·[p_i(tau)] + Σ (lambda_i·points[i])·[H_i] - (Σ lambda_i·y_i)·[1]
+	bases := make([]{{.CurveName}}.G1Affine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	copy(bases, digests)
+	copy(bases[n:], quotients)
+	_, _, bases[2*n], _ = {{.CurveName}}.Generators()
+	var sumY fr.Element
+	for i := 0; i < n; i++ {
+		var t fr.Element
+		t.Mul(&lambdas[i], &proofs[i].ClaimedValue)
+		sumY.Add(&sumY, &t)
+		scalars[n+i].Mul(&lambdas[i], &points[i])
+	}
+	scalars[2*n].Neg(&sumY)
+	copy(scalars, lambdas)
+	for i := 0; i < len(scalars); i++ {
+		scalars[i].FromMont()
+	}
+
+	var foldedH, folded {{.CurveName}}.G1Jac
+	foldedH.MultiExp(quotients, scalars[:n])
+	folded.MultiExp(bases, scalars)
+
+	var foldedAff, negFoldedH {{.CurveName}}.G1Affine
+	foldedAff.FromJacobian(&folded)
+	negFoldedH.FromJacobian(&foldedH)
+	negFoldedH.Neg(&negFoldedH)
+
+	ok, err := {{.CurveName}}.PairingCheck(
+		[]{{.CurveName}}.G1Affine{foldedAff, negFoldedH},
+		[]{{.CurveName}}.G2Affine{srs.G2[0], srs.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// eval returns p(point) (Horner)
+func eval(p []fr.Element, point *fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, point).Add(&res, &p[i])
+	}
+	return res
+}
+
+// dividePolyByXminusA returns the quotient of p by (X - a) (synthetic division), the remainder p(a) is dropped
+func dividePolyByXminusA(p []fr.Element, a *fr.Element) []fr.Element {
+	if len(p) <= 1 {
+		return nil
+	}
+	q := make([]fr.Element, len(p)-1)
+	q[len(q)-1] = p[len(p)-1]
+	for i := len(q) - 1; i > 0; i-- {
+		q[i-1].Mul(&q[i], a).Add(&q[i-1], &p[i])
+	}
+	return q
+}
+
+// deriveGamma returns the Fiat-Shamir challenge of BatchOpenSinglePoint, the hash of its public inputs
+// the SRS is bound by [tau] in G2, which the verifier uses
+func deriveGamma(point *fr.Element, digests []Digest, claimedValues []fr.Element, srs *SRS) fr.Element {
+	h := sha256.New()
+	h.Write([]byte("gamma"))
+	tau := srs.G2[1].Bytes()
+	h.Write(tau[:])
+	h.Write(point.Bytes())
+	for i := 0; i < len(digests); i++ {
+		b := digests[i].Bytes()
+		h.Write(b[:])
+	}
+	for i := 0; i < len(claimedValues); i++ {
+		h.Write(claimedValues[i].Bytes())
+	}
+	var gamma fr.Element
+	gamma.SetBytes(h.Sum(nil))
+	return gamma
+}
+
+`
+
+// KZGTests ...
+const KZGTests = `
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gurvy/{{.CurveName}}"
+	"github.com/consensys/gurvy/{{.CurveName}}/fr"
+)
+
+// testSRS is a SRS of size 64 with tau = 42, which is NOT secret
+var testSRS *SRS
+
+func init() {
+	var err error
+	testSRS, err = NewSRS(64, new(big.Int).SetInt64(42))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func randomPolynomial(size int) []fr.Element {
+	p := make([]fr.Element, size)
+	for i := 0; i < size; i++ {
+		p[i].SetRandom()
+	}
+	return p
+}
+
+func TestNewSRS(t *testing.T) {
+	if _, err := NewSRS(1, big.NewInt(42)); err != ErrMinSRSSize {
+		t.Fatal("NewSRS should reject a size smaller than 2")
+	}
+
+	// e([tau^i], [tau]) == e([tau^(i+1)], [1])
+	for i := 0; i < len(testSRS.G1)-1; i += 7 {
+		var negG1 {{.CurveName}}.G1Affine
+		negG1.Neg(&testSRS.G1[i+1])
+		ok, err := {{.CurveName}}.PairingCheck(
+			[]{{.CurveName}}.G1Affine{testSRS.G1[i], negG1},
+			[]{{.CurveName}}.G2Affine{testSRS.G2[1], testSRS.G2[0]},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("inconsistent SRS")
+		}
+	}
+}
+
+func TestCommit(t *testing.T) {
+	p := randomPolynomial(60)
+	digest, err := Commit(p, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// [p(tau)]
+	var tau fr.Element
+	tau.SetUint64(42)
+	y := eval(p, &tau)
+	var expected {{.CurveName}}.G1Jac
+	_, _, g1Aff, _ := {{.CurveName}}.Generators()
+	expected.FromAffine(&g1Aff)
+	expected.ScalarMultiplication(&expected, y.ToBigIntRegular(new(big.Int)))
+	var expectedAff {{.CurveName}}.G1Affine
+	expectedAff.FromJacobian(&expected)
+	if !digest.Equal(&expectedAff) {
+		t.Fatal("wrong commitment")
+	}
+
+	if _, err := Commit(randomPolynomial(65), testSRS); err != ErrInvalidPolynomialSize {
+		t.Fatal("Commit should reject polynomials larger than the SRS")
+	}
+	if _, err := Commit(nil, testSRS); err != ErrInvalidPolynomialSize {
+		t.Fatal("Commit should reject empty polynomials")
+	}
+}
+
+func TestDividePolyByXminusA(t *testing.T) {
+	p := randomPolynomial(30)
+	var a, x fr.Element
+	a.SetRandom()
+	x.SetRandom()
+
+	// p(x) - p(a) == q(x)·(x - a)
+	q := dividePolyByXminusA(p, &a)
+	px, pa, qx := eval(p, &x), eval(p, &a), eval(q, &x)
+	var lhs, rhs fr.Element
+	lhs.Sub(&px, &pa)
+	rhs.Sub(&x, &a).Mul(&rhs, &qx)
+	if !lhs.Equal(&rhs) {
+		t.Fatal("wrong quotient")
+	}
+}
+
+func TestOpenVerify(t *testing.T) {
+	for _, size := range []int{1, 2, 17, 64} {
+		p := randomPolynomial(size)
+		digest, err := Commit(p, testSRS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var point fr.Element
+		point.SetRandom()
+		proof, err := Open(p, &point, testSRS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := eval(p, &point)
+		if !proof.ClaimedValue.Equal(&expected) {
+			t.Fatal("wrong claimed value")
+		}
+		if err := Verify(&digest, &proof, &point, testSRS); err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+
+		// wrong claimed value
+		wrong := proof
+		wrong.ClaimedValue.Double(&wrong.ClaimedValue)
+		if err := Verify(&digest, &wrong, &point, testSRS); err != ErrVerifyOpeningProof {
+			t.Fatal("proof of a wrong value should not verify")
+		}
+
+		// wrong point
+		var other fr.Element
+		other.SetRandom()
+		if size > 1 {
+			if err := Verify(&digest, &proof, &other, testSRS); err != ErrVerifyOpeningProof {
+				t.Fatal("proof at another point should not verify")
+			}
+		}
+	}
+}
+
+func TestBatchOpenSinglePoint(t *testing.T) {
+	polynomials := [][]fr.Element{randomPolynomial(64), randomPolynomial(10), randomPolynomial(33)}
+	digests := make([]Digest, len(polynomials))
+	for i := range polynomials {
+		var err error
+		if digests[i], err = Commit(polynomials[i], testSRS); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var point fr.Element
+	point.SetRandom()
+
+	proof, err := BatchOpenSinglePoint(polynomials, digests, &point, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range polynomials {
+		expected := eval(polynomials[i], &point)
+		if !proof.ClaimedValues[i].Equal(&expected) {
+			t.Fatal("wrong claimed value")
+		}
+	}
+	if err := BatchVerifySinglePoint(digests, &proof, &point, testSRS); err != nil {
+		t.Fatal(err)
+	}
+
+	proof.ClaimedValues[1].Double(&proof.ClaimedValues[1])
+	if err := BatchVerifySinglePoint(digests, &proof, &point, testSRS); err != ErrVerifyOpeningProof {
+		t.Fatal("proof of a wrong value should not verify")
+	}
+
+	if _, err := BatchOpenSinglePoint(polynomials, digests[1:], &point, testSRS); err != ErrInvalidNbDigests {
+		t.Fatal("BatchOpenSinglePoint should reject mismatched lengths")
+	}
+
+	// the challenge is bound to the SRS
+	otherSRS, err := NewSRS(2, new(big.Int).SetInt64(43))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gamma := deriveGamma(&point, digests, proof.ClaimedValues, testSRS)
+	otherGamma := deriveGamma(&point, digests, proof.ClaimedValues, otherSRS)
+	if gamma.Equal(&otherGamma) {
+		t.Fatal("deriveGamma should depend on the SRS")
+	}
+}
+
+func TestBatchVerifyMultiPoints(t *testing.T) {
+	const n = 5
+	digests := make([]Digest, n)
+	proofs := make([]OpeningProof, n)
+	points := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		p := randomPolynomial(10 + 11*i)
+		var err error
+		if digests[i], err = Commit(p, testSRS); err != nil {
+			t.Fatal(err)
+		}
+		points[i].SetRandom()
+		if proofs[i], err = Open(p, &points[i], testSRS); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := BatchVerifyMultiPoints(digests, proofs, points, testSRS); err != nil {
+		t.Fatal(err)
+	}
+
+	proofs[3].ClaimedValue.Double(&proofs[3].ClaimedValue)
+	if err := BatchVerifyMultiPoints(digests, proofs, points, testSRS); err != ErrVerifyOpeningProof {
+		t.Fatal("a wrong proof should not verify")
+	}
+
+	if err := BatchVerifyMultiPoints(digests, proofs[1:], points, testSRS); err != ErrInvalidNbDigests {
+		t.Fatal("BatchVerifyMultiPoints should reject mismatched lengths")
+	}
+}
+
+func BenchmarkKZG(b *testing.B) {
+	const size = 1 << 12
+	srs, err := NewSRS(size, new(big.Int).SetInt64(42))
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomPolynomial(size)
+	digest, _ := Commit(p, srs)
+	var point fr.Element
+	point.SetRandom()
+	proof, _ := Open(p, &point, srs)
+
+	b.Run("Commit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Commit(p, srs)
+		}
+	})
+	b.Run("Open", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Open(p, &point, srs)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Verify(&digest, &proof, &point, srs)
+		}
+	})
+}
+
+`
@@ -4,6 +4,7 @@ package pairing
 const PairingTests = `
 
 import (
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -27,8 +28,8 @@ func TestPairing(t *testing.T) {
 	genR2 := GenFr()
 
 	properties.Property("[{{ toUpper .CurveName}}] Having the receiver as operand (final expo) should output the same result", prop.ForAll(
-		func(a *e12) bool {
-			var b e12
+		func(a *E12) bool {
+			var b E12
 			b.Set(a)
 			b.FinalExponentiation(a)
 			a.FinalExponentiation(a)
@@ -38,12 +39,12 @@ func TestPairing(t *testing.T) {
 	))
 
     properties.Property("[{{ toUpper .CurveName}}] Exponentiating FinalExpo(a) to r should output 1", prop.ForAll(
-		func(a *e12) bool {
-			var one e12
+		func(a *E12) bool {
+			var one E12
 			var e big.Int
 			e.SetString("{{ .RTorsion }}", 10)
 			one.SetOne()
-			a.FinalExponentiation(a).Exp(a, e)
+			a.FinalExponentiation(a).Exp(a, &e)
 			return a.Equal(&one)
 		},
 		genA,
@@ -74,12 +75,12 @@ func TestPairing(t *testing.T) {
 			ag1.FromJacobian(&aG1)
 			bg2.FromJacobian(&bG2)
 
-			res = FinalExponentiation(MillerLoop(g1affine, g2affine))
-			resa = FinalExponentiation(MillerLoop(ag1, g2affine))
-			resb = FinalExponentiation(MillerLoop(g1affine, bg2))
-			resab.Exp(&res, ab)
-			resa.Exp(&resa, bbigint)
-			resb.Exp(&resb, abigint)
+			res, _ = Pair([]G1Affine{g1affine}, []G2Affine{g2affine})
+			resa, _ = Pair([]G1Affine{ag1}, []G2Affine{g2affine})
+			resb, _ = Pair([]G1Affine{g1affine}, []G2Affine{bg2})
+			resab.Exp(&res, &ab)
+			resa.Exp(&resa, &bbigint)
+			resb.Exp(&resb, &abigint)
 
 			return resab.Equal(&resa) && resab.Equal(&resb) && !res.Equal(&zero)
 
@@ -88,6 +89,169 @@ func TestPairing(t *testing.T) {
 		genR2,
 	))
 
+	properties.Property("[{{ toUpper .CurveName}}] MillerLoop of pairs should be equal to the product of separated MillerLoops", prop.ForAll(
+		func(a, b fr.Element) bool {
+
+			var simpleProd, factorizedProd GT
+
+			var ag1, bg1 G1Affine
+			var ag2, bg2 G2Affine
+			var g1Jac G1Jac
+			var g2Jac G2Jac
+
+			var abigint, bbigint big.Int
+
+			a.ToBigIntRegular(&abigint)
+			b.ToBigIntRegular(&bbigint)
+
+			ag1.FromJacobian(g1Jac.ScalarMultiplication(&g1Gen, &abigint))
+			bg1.FromJacobian(g1Jac.ScalarMultiplication(&g1Gen, &bbigint))
+			ag2.FromJacobian(g2Jac.ScalarMultiplication(&g2Gen, &abigint))
+			bg2.FromJacobian(g2Jac.ScalarMultiplication(&g2Gen, &bbigint))
+
+			p0, _ := MillerLoop([]G1Affine{ag1}, []G2Affine{ag2})
+			p1, _ := MillerLoop([]G1Affine{bg1}, []G2Affine{bg2})
+			simpleProd.Mul(&p0, &p1)
+			factorizedProd, _ = MillerLoop([]G1Affine{ag1, bg1}, []G2Affine{ag2, bg2})
+
+			return simpleProd.Equal(&factorizedProd)
+		},
+		genR1,
+		genR2,
+	))
+
+	properties.Property("[{{ toUpper .CurveName}}] MillerLoopPrepared should output the same result as MillerLoop, the prepared G2 point being evaluated at different G1 points", prop.ForAll(
+		func(a, b fr.Element) bool {
+
+			var ag1, bg1 G1Affine
+			var ag2 G2Affine
+			var g1Jac G1Jac
+			var g2Jac G2Jac
+
+			var abigint, bbigint big.Int
+
+			a.ToBigIntRegular(&abigint)
+			b.ToBigIntRegular(&bbigint)
+
+			ag1.FromJacobian(g1Jac.ScalarMultiplication(&g1Gen, &abigint))
+			bg1.FromJacobian(g1Jac.ScalarMultiplication(&g1Gen, &bbigint))
+			ag2.FromJacobian(g2Jac.ScalarMultiplication(&g2Gen, &abigint))
+
+			prepared := []PreparedG2{PrepareG2(&ag2), PrepareG2(&g2GenAff)}
+
+			ml0, _ := MillerLoop([]G1Affine{ag1, bg1}, []G2Affine{ag2, g2GenAff})
+			ml1, _ := MillerLoop([]G1Affine{bg1, ag1}, []G2Affine{ag2, g2GenAff})
+			mlp0, _ := MillerLoopPrepared([]G1Affine{ag1, bg1}, prepared)
+			mlp1, _ := MillerLoopPrepared([]G1Affine{bg1, ag1}, prepared)
+
+			return ml0.Equal(&mlp0) && ml1.Equal(&mlp1)
+		},
+		genR1,
+		genR2,
+	))
+
+	properties.Property("[{{ toUpper .CurveName}}] PairingCheck of e(aP, Q) * e(-P, aQ) should output true, pairs with infinity should be skipped", prop.ForAll(
+		func(a fr.Element) bool {
+
+			var ag1, g1Neg, g1Inf G1Affine
+			var ag2, g2Inf G2Affine
+			var g1Jac G1Jac
+			var g2Jac G2Jac
+
+			var abigint big.Int
+			a.ToBigIntRegular(&abigint)
+
+			ag1.FromJacobian(g1Jac.ScalarMultiplication(&g1Gen, &abigint))
+			ag2.FromJacobian(g2Jac.ScalarMultiplication(&g2Gen, &abigint))
+			g1Neg.Neg(&g1GenAff)
+
+			ok, err := PairingCheck([]G1Affine{ag1, g1Neg, g1Inf, g1GenAff}, []G2Affine{g2GenAff, ag2, g2GenAff, g2Inf})
+			return ok && err == nil
+		},
+		genR1,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestPairingErrors(t *testing.T) {
+	if _, err := Pair([]G1Affine{g1GenAff, g1GenAff}, []G2Affine{g2GenAff}); err == nil {
+		t.Fatal("Pair should fail when the inputs have different lengths")
+	}
+	if ok, err := PairingCheck(nil, nil); !ok || err != nil {
+		t.Fatal("PairingCheck of the empty product should output true")
+	}
+	var one GT
+	one.SetOne()
+	if f, err := MillerLoopPrepared(nil, nil); err != nil || !f.Equal(&one) {
+		t.Fatal("MillerLoopPrepared of the empty product should output 1")
+	}
+	if _, err := MillerLoopPrepared([]G1Affine{g1GenAff}, []PreparedG2{}); err == nil {
+		t.Fatal("MillerLoopPrepared should fail when the inputs have different lengths")
+	}
+	if ok, _ := PairingCheck([]G1Affine{g1GenAff}, []G2Affine{g2GenAff}); ok {
+		t.Fatal("e(g1, g2) should not be 1")
+	}
+}
+
+func TestGT(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenE12()
+	genR1 := GenFr()
+
+	properties.Property("[{{ toUpper .CurveName}}] ExpGLV, ExpFr and Exp should output the same result in GT", prop.ForAll(
+		func(a *E12, s fr.Element) bool {
+			var b, c, d GT
+			var k big.Int
+			a.FinalExponentiation(a)
+			s.ToBigIntRegular(&k)
+			b.Exp(a, &k)
+			c.ExpGLV(a, &k)
+			d.ExpFr(a, &s)
+			if !b.Equal(&c) || !b.Equal(&d) {
+				return false
+			}
+			k.Neg(&k)
+			b.Exp(a, &k)
+			c.ExpGLV(a, &k)
+			return b.Equal(&c)
+		},
+		genA,
+		genR1,
+	))
+
+	properties.Property("[{{ toUpper .CurveName}}] IsInSubGroup should output true on GT, false on the cyclotomic subgroup outside GT", prop.ForAll(
+		func(a *E12) bool {
+			// easy part of the final exponentiation: b is in the cyclotomic subgroup
+			var b, c, d E12
+			b.Conjugate(a)
+			c.Inverse(a)
+			b.Mul(&b, &c)
+			c.FrobeniusSquare(&b).Mul(&c, &b)
+			d.FinalExponentiation(a)
+			return d.IsInSubGroup() && !c.IsInSubGroup() && !a.IsInSubGroup()
+		},
+		genA,
+	))
+
+	properties.Property("[{{ toUpper .CurveName}}] FinalExponentiationCompressed should output the compressed FinalExponentiation", prop.ForAll(
+		func(a *E12) bool {
+			var b GT
+			y, err := FinalExponentiationCompressed(a)
+			if err != nil {
+				return false
+			}
+			c := FinalExponentiation(a)
+			return b.DecompressTorus(&y).Equal(&c)
+		},
+		genA,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
@@ -104,13 +268,78 @@ func BenchmarkPairing(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		FinalExponentiation(MillerLoop(g1GenAff, g2GenAff))
+		Pair([]G1Affine{g1GenAff}, []G2Affine{g2GenAff})
 	}
 }
 
+func BenchmarkMultiPair(b *testing.B) {
+
+	var g1GenAff G1Affine
+	var g2GenAff G2Affine
+
+	g1GenAff.FromJacobian(&g1Gen)
+	g2GenAff.FromJacobian(&g2Gen)
+
+	n := 10
+	P := make([]G1Affine, n)
+	Q := make([]G2Affine, n)
+
+	for i := 0; i < n; i++ {
+		P[i] = g1GenAff
+		Q[i] = g2GenAff
+	}
+
+	for i := 2; i <= n; i++ {
+		b.Run(fmt.Sprintf("%d pairs", i), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				Pair(P[:i], Q[:i])
+			}
+		})
+	}
+}
+
+func BenchmarkMillerLoopPrepared(b *testing.B) {
+
+	var g1GenAff G1Affine
+	var g2GenAff G2Affine
+
+	g1GenAff.FromJacobian(&g1Gen)
+	g2GenAff.FromJacobian(&g2Gen)
+
+	prepared := []PreparedG2{PrepareG2(&g2GenAff)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MillerLoopPrepared([]G1Affine{g1GenAff}, prepared)
+	}
+}
+
+func BenchmarkExpGT(b *testing.B) {
+
+	var a GT
+	var s fr.Element
+	var k big.Int
+	a.SetRandom()
+	a.FinalExponentiation(&a)
+	s.SetRandom()
+	s.ToBigIntRegular(&k)
+
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			a.Exp(&a, &k)
+		}
+	})
+
+	b.Run("GLV", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			a.ExpGLV(&a, &k)
+		}
+	})
+}
+
 func BenchmarkFinalExponentiation(b *testing.B) {
 
-	var a e12
+	var a E12
 	a.SetRandom()
 
 	b.ResetTimer()
@@ -0,0 +1,1237 @@
+package point
+
+// Marshal ...
+const Marshal = `
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/consensys/gurvy/{{ toLower .CurveName}}/fp"
+	"github.com/consensys/gurvy/{{ toLower .CurveName}}/fr"
+	"github.com/consensys/gurvy/utils/parallel"
+)
+
+// SizeOfFp is the size in bytes of a base field element, in regular form
+const SizeOfFp = fp.Limbs * 8
+
+// SizeOf{G1,G2}Affine{Compressed,Uncompressed} is the size in bytes of the binary representation of a point
+const (
+	SizeOfG1AffineCompressed   = SizeOfFp
+	SizeOfG1AffineUncompressed = SizeOfG1AffineCompressed * 2
+	{{- if eq .CurveName "bw761"}}
+	SizeOfG2AffineCompressed   = SizeOfFp
+	{{- else}}
+	SizeOfG2AffineCompressed   = SizeOfFp * 2
+	{{- end}}
+	SizeOfG2AffineUncompressed = SizeOfG2AffineCompressed * 2
+)
+
+{{if ge .FpUnusedBits 3}}
+// To encode G1Affine and G2Affine points, we mask the most significant bits of the first byte
+// with the metadata needed for point (de)compression. We follow the BLS12-381 style encoding
+// as specified in ZCash and IETF:
+// * the most significant bit, when set, indicates that the point is in compressed form,
+// * the second-most significant bit indicates that the point is at infinity; if this bit is set,
+// the remaining bits of the encoding must be zero,
+// * the third-most significant bit is set if (and only if) the point is in compressed form, is not
+// the point at infinity and its y-coordinate is the lexicographically largest of the two associated
+// with the encoded x-coordinate.
+const (
+	mMask                 byte = 0b111 << 5
+	mUncompressed         byte = 0b000 << 5
+	mUncompressedInfinity byte = 0b010 << 5
+	mCompressedSmallest   byte = 0b100 << 5
+	mCompressedLargest    byte = 0b101 << 5
+	mCompressedInfinity   byte = 0b110 << 5
+)
+{{else}}
+// To encode G1Affine and G2Affine points, we mask the most significant bits of the first byte
+// with the metadata needed for point (de)compression. As the base field leaves only {{.FpUnusedBits}} unused
+// bits in the most significant word, we can't follow the BLS12-381 style encoding (ZCash/IETF):
+// * 0b00 indicates an uncompressed point; the point at infinity is then encoded as (0,0), which
+// is not on the curve,
+// * 0b10 indicates a compressed point, whose y-coordinate is the lexicographically smallest
+// of the two associated with the encoded x-coordinate,
+// * 0b11 indicates a compressed point, whose y-coordinate is the lexicographically largest one,
+// * 0b01 indicates the compressed point at infinity; the remaining bits of the encoding must be zero.
+const (
+	mMask               byte = 0b11 << 6
+	mUncompressed       byte = 0b00 << 6
+	mCompressedSmallest byte = 0b10 << 6
+	mCompressedLargest  byte = 0b11 << 6
+	mCompressedInfinity byte = 0b01 << 6
+)
+{{end}}
+
+// fpModulus and fpHalfModulus store q and (q-1)/2 in regular form, they are used
+// to check that decoded coordinates are reduced, and to compare y with -y
+var fpModulus, fpHalfModulus fp.Element
+
+func init() {
+	var buf [SizeOfFp]byte
+	q := fp.Modulus()
+	q.FillBytes(buf[:])
+	fpFromBytes(&fpModulus, buf[:])
+	q.Rsh(q, 1).FillBytes(buf[:])
+	fpFromBytes(&fpHalfModulus, buf[:])
+}
+
+// fpFromBytes sets z to the big-endian integer in buf (no Montgomery conversion nor reduction)
+func fpFromBytes(z *fp.Element, buf []byte) {
+	for i := 0; i < fp.Limbs; i++ {
+		z[fp.Limbs-1-i] = binary.BigEndian.Uint64(buf[i*8 : (i+1)*8])
+	}
+}
+
+// fpLess returns true if x < y, x and y being compared as integers in the same form
+func fpLess(x, y *fp.Element) bool {
+	for i := fp.Limbs - 1; i >= 0; i-- {
+		if x[i] != y[i] {
+			return x[i] < y[i]
+		}
+	}
+	return false
+}
+
+// putFp writes x in regular form, big-endian, in buf
+func putFp(buf []byte, x *fp.Element) {
+	_x := x.ToRegular()
+	for i := 0; i < fp.Limbs; i++ {
+		binary.BigEndian.PutUint64(buf[i*8:(i+1)*8], _x[fp.Limbs-1-i])
+	}
+}
+
+// readFp sets z from the big-endian regular form in buf and converts it to Montgomery form.
+// It returns an error if the encoded integer is not reduced modulo q
+func readFp(z *fp.Element, buf []byte) error {
+	fpFromBytes(z, buf)
+	if !fpLess(z, &fpModulus) {
+		return errors.New("invalid encoding: coordinate is not reduced modulo q")
+	}
+	z.ToMont()
+	return nil
+}
+
+// lexicographicallyLargest returns true if x > -x, that is if x (in regular form) is larger than (q-1)/2
+func lexicographicallyLargest(x *fp.Element) bool {
+	_x := x.ToRegular()
+	return fpLess(&fpHalfModulus, &_x)
+}
+
+{{- if ne .CurveName "bw761"}}
+
+// lexicographicallyLargest returns true if x > -x. As in ZCash, A1 is compared first
+// and A0 is used only if A1 is zero
+func (z *E2) lexicographicallyLargest() bool {
+	if z.A1.IsZero() {
+		return lexicographicallyLargest(&z.A0)
+	}
+	return lexicographicallyLargest(&z.A1)
+}
+{{- end}}
+
+// isZero returns true if all the bytes in buf are zero
+func isZero(buf []byte) bool {
+	for i := 0; i < len(buf); i++ {
+		if buf[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SizeOfFr is the size in bytes of a scalar field element, in regular form
+const SizeOfFr = fr.Limbs * 8
+
+// frModulus stores r in regular form, it is used to check that decoded scalars are reduced
+var frModulus fr.Element
+
+func init() {
+	var buf [SizeOfFr]byte
+	fr.Modulus().FillBytes(buf[:])
+	for i := 0; i < fr.Limbs; i++ {
+		frModulus[fr.Limbs-1-i] = binary.BigEndian.Uint64(buf[i*8 : (i+1)*8])
+	}
+}
+
+// putFr writes x in regular form, big-endian, in buf
+func putFr(buf []byte, x *fr.Element) {
+	_x := x.ToRegular()
+	for i := 0; i < fr.Limbs; i++ {
+		binary.BigEndian.PutUint64(buf[i*8:(i+1)*8], _x[fr.Limbs-1-i])
+	}
+}
+
+// readFr sets z from the big-endian regular form in buf and converts it to Montgomery form.
+// It returns an error if the encoded integer is not reduced modulo r
+func readFr(z *fr.Element, buf []byte) error {
+	for i := 0; i < fr.Limbs; i++ {
+		z[fr.Limbs-1-i] = binary.BigEndian.Uint64(buf[i*8 : (i+1)*8])
+	}
+	for i := fr.Limbs - 1; i >= 0; i-- {
+		if z[i] != frModulus[i] {
+			if z[i] > frModulus[i] {
+				break
+			}
+			z.ToMont()
+			return nil
+		}
+	}
+	return errors.New("invalid encoding: scalar is not reduced modulo r")
+}
+
+// isCompressed returns true if the metadata bits in msb (first byte of an encoded point)
+// indicate a compressed point
+func isCompressed(msb byte) bool {
+	mData := msb & mMask
+	{{- if ge .FpUnusedBits 3}}
+	return !(mData == mUncompressed || mData == mUncompressedInfinity)
+	{{- else}}
+	return mData != mUncompressed
+	{{- end}}
+}
+
+// Encoder writes {{.CurveName}} objects values to an output stream
+//
+// Supported types are fr.Element, fp.Element, G1Affine, G2Affine and slices of fr.Element,
+// G1Affine and G2Affine (and pointers to these types).
+// Field elements are written in regular form, big-endian; points are written compressed unless
+// the Encoder was created with the RawEncoding option. Slices are prefixed with their length,
+// as a big-endian uint32.
+type Encoder struct {
+	w   io.Writer
+	n   int64 // written bytes
+	raw bool  // raw (uncompressed) encoding of points
+}
+
+// NewEncoder returns a binary encoder supporting curve {{.CurveName}} objects in both compressed
+// (default) and uncompressed (see RawEncoding) forms
+func NewEncoder(w io.Writer, options ...func(*Encoder)) *Encoder {
+	enc := &Encoder{w: w}
+	for _, option := range options {
+		option(enc)
+	}
+	return enc
+}
+
+// RawEncoding returns an option to use in NewEncoder(...) which sets the points encoding
+// to uncompressed. This is faster to decode, at the cost of twice the size
+func RawEncoding() func(*Encoder) {
+	return func(enc *Encoder) {
+		enc.raw = true
+	}
+}
+
+// BytesWritten returns the total number of bytes written by the Encoder
+func (enc *Encoder) BytesWritten() int64 {
+	return enc.n
+}
+
+// Encode writes the binary encoding of v to the stream
+// type must be fr.Element, fp.Element, G1Affine, G2Affine, []fr.Element, []G1Affine or []G2Affine
+// (or pointers to these types)
+func (enc *Encoder) Encode(v interface{}) (err error) {
+	var written int
+	defer func() {
+		enc.n += int64(written)
+	}()
+
+	switch t := v.(type) {
+	case fr.Element:
+		return enc.Encode(&t)
+	case fp.Element:
+		return enc.Encode(&t)
+	case G1Affine:
+		return enc.Encode(&t)
+	case G2Affine:
+		return enc.Encode(&t)
+	case *fr.Element:
+		var buf [SizeOfFr]byte
+		putFr(buf[:], t)
+		written, err = enc.w.Write(buf[:])
+		return
+	case *fp.Element:
+		var buf [SizeOfFp]byte
+		putFp(buf[:], t)
+		written, err = enc.w.Write(buf[:])
+		return
+	case *G1Affine:
+		if enc.raw {
+			buf := t.RawBytes()
+			written, err = enc.w.Write(buf[:])
+		} else {
+			buf := t.Bytes()
+			written, err = enc.w.Write(buf[:])
+		}
+		return
+	case *G2Affine:
+		if enc.raw {
+			buf := t.RawBytes()
+			written, err = enc.w.Write(buf[:])
+		} else {
+			buf := t.Bytes()
+			written, err = enc.w.Write(buf[:])
+		}
+		return
+	case *[]fr.Element:
+		return enc.Encode(*t)
+	case *[]G1Affine:
+		return enc.Encode(*t)
+	case *[]G2Affine:
+		return enc.Encode(*t)
+	case []fr.Element:
+		if written, err = enc.writeLength(len(t)); err != nil {
+			return
+		}
+		buf := make([]byte, len(t)*SizeOfFr)
+		for i := 0; i < len(t); i++ {
+			putFr(buf[i*SizeOfFr:(i+1)*SizeOfFr], &t[i])
+		}
+		var n int
+		n, err = enc.w.Write(buf)
+		written += n
+		return
+	case []G1Affine:
+		if written, err = enc.writeLength(len(t)); err != nil {
+			return
+		}
+		var n int
+		if enc.raw {
+			buf := make([]byte, len(t)*SizeOfG1AffineUncompressed)
+			parallel.Execute(len(t), func(start, end int) {
+				for i := start; i < end; i++ {
+					b := t[i].RawBytes()
+					copy(buf[i*SizeOfG1AffineUncompressed:], b[:])
+				}
+			})
+			n, err = enc.w.Write(buf)
+		} else {
+			buf := make([]byte, len(t)*SizeOfG1AffineCompressed)
+			parallel.Execute(len(t), func(start, end int) {
+				for i := start; i < end; i++ {
+					b := t[i].Bytes()
+					copy(buf[i*SizeOfG1AffineCompressed:], b[:])
+				}
+			})
+			n, err = enc.w.Write(buf)
+		}
+		written += n
+		return
+	case []G2Affine:
+		if written, err = enc.writeLength(len(t)); err != nil {
+			return
+		}
+		var n int
+		if enc.raw {
+			buf := make([]byte, len(t)*SizeOfG2AffineUncompressed)
+			parallel.Execute(len(t), func(start, end int) {
+				for i := start; i < end; i++ {
+					b := t[i].RawBytes()
+					copy(buf[i*SizeOfG2AffineUncompressed:], b[:])
+				}
+			})
+			n, err = enc.w.Write(buf)
+		} else {
+			buf := make([]byte, len(t)*SizeOfG2AffineCompressed)
+			parallel.Execute(len(t), func(start, end int) {
+				for i := start; i < end; i++ {
+					b := t[i].Bytes()
+					copy(buf[i*SizeOfG2AffineCompressed:], b[:])
+				}
+			})
+			n, err = enc.w.Write(buf)
+		}
+		written += n
+		return
+	default:
+		return errors.New("{{.CurveName}} encoder: unsupported type")
+	}
+}
+
+// writeLength writes the length prefix of a slice
+func (enc *Encoder) writeLength(l int) (int, error) {
+	if uint64(l) > math.MaxUint32 {
+		return 0, errors.New("{{.CurveName}} encoder: slice is too large")
+	}
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(l))
+	return enc.w.Write(buf[:])
+}
+
+// Decoder reads {{.CurveName}} objects values from an input stream
+//
+// It reads the encoding written by an Encoder; compressed and uncompressed points are detected
+// from their metadata bits. Slices of points are decompressed (and checked) in parallel.
+// Slices are read in bounded chunks, so a corrupted length prefix can't force large allocations.
+type Decoder struct {
+	r        io.Reader
+	n        int64 // read bytes
+	noChecks bool  // skip on curve and subgroup checks of decoded points
+}
+
+// NewDecoder returns a binary decoder supporting curve {{.CurveName}} objects in both compressed
+// and uncompressed (raw) forms
+func NewDecoder(r io.Reader, options ...func(*Decoder)) *Decoder {
+	dec := &Decoder{r: r}
+	for _, option := range options {
+		option(dec)
+	}
+	return dec
+}
+
+// NoChecks returns an option to use in NewDecoder(...) to skip the subgroup checks of
+// decoded points, and the on curve checks of uncompressed points. It should only be used
+// to read trusted inputs: uncompressed points are then decoded with no other operation than
+// a conversion to Montgomery form
+func NoChecks() func(*Decoder) {
+	return func(dec *Decoder) {
+		dec.noChecks = true
+	}
+}
+
+// BytesRead returns the total number of bytes read by the Decoder
+func (dec *Decoder) BytesRead() int64 {
+	return dec.n
+}
+
+// Decode reads the binary encoding of v from the stream
+// type must be *fr.Element, *fp.Element, *G1Affine, *G2Affine, *[]fr.Element, *[]G1Affine or *[]G2Affine
+func (dec *Decoder) Decode(v interface{}) (err error) {
+	var read int
+	defer func() {
+		dec.n += int64(read)
+	}()
+
+	switch t := v.(type) {
+	case *fr.Element:
+		var buf [SizeOfFr]byte
+		if read, err = io.ReadFull(dec.r, buf[:]); err != nil {
+			return
+		}
+		return readFr(t, buf[:])
+	case *fp.Element:
+		var buf [SizeOfFp]byte
+		if read, err = io.ReadFull(dec.r, buf[:]); err != nil {
+			return
+		}
+		return readFp(t, buf[:])
+	case *G1Affine:
+		var buf [SizeOfG1AffineUncompressed]byte
+		if read, err = io.ReadFull(dec.r, buf[:SizeOfG1AffineCompressed]); err != nil {
+			return
+		}
+		if !isCompressed(buf[0]) {
+			var n int
+			n, err = io.ReadFull(dec.r, buf[SizeOfG1AffineCompressed:])
+			read += n
+			if err != nil {
+				return
+			}
+		}
+		_, err = dec.setG1(t, buf[:read])
+		return
+	case *G2Affine:
+		var buf [SizeOfG2AffineUncompressed]byte
+		if read, err = io.ReadFull(dec.r, buf[:SizeOfG2AffineCompressed]); err != nil {
+			return
+		}
+		if !isCompressed(buf[0]) {
+			var n int
+			n, err = io.ReadFull(dec.r, buf[SizeOfG2AffineCompressed:])
+			read += n
+			if err != nil {
+				return
+			}
+		}
+		_, err = dec.setG2(t, buf[:read])
+		return
+	case *[]fr.Element:
+		var l int
+		if l, read, err = dec.readLength(); err != nil {
+			return
+		}
+		var buf []byte
+		var n int
+		buf, n, err = dec.readChunked(nil, l*SizeOfFr)
+		read += n
+		if err != nil {
+			return
+		}
+		if cap(*t) < l {
+			*t = make([]fr.Element, l)
+		}
+		*t = (*t)[:l]
+		for i := 0; i < l; i++ {
+			if err = readFr(&(*t)[i], buf[i*SizeOfFr:(i+1)*SizeOfFr]); err != nil {
+				return
+			}
+		}
+		return
+	case *[]G1Affine:
+		var l int
+		if l, read, err = dec.readLength(); err != nil {
+			return
+		}
+		var buf []byte
+		var size, n int
+		buf, size, n, err = dec.readPoints(l, SizeOfG1AffineCompressed, SizeOfG1AffineUncompressed)
+		read += n
+		if err != nil {
+			return
+		}
+		if cap(*t) < l {
+			*t = make([]G1Affine, l)
+		}
+		*t = (*t)[:l]
+		err = decodeParallel(l, func(i int) (int, error) {
+			return dec.setG1(&(*t)[i], buf[i*size:(i+1)*size])
+		}, size)
+		return
+	case *[]G2Affine:
+		var l int
+		if l, read, err = dec.readLength(); err != nil {
+			return
+		}
+		var buf []byte
+		var size, n int
+		buf, size, n, err = dec.readPoints(l, SizeOfG2AffineCompressed, SizeOfG2AffineUncompressed)
+		read += n
+		if err != nil {
+			return
+		}
+		if cap(*t) < l {
+			*t = make([]G2Affine, l)
+		}
+		*t = (*t)[:l]
+		err = decodeParallel(l, func(i int) (int, error) {
+			return dec.setG2(&(*t)[i], buf[i*size:(i+1)*size])
+		}, size)
+		return
+	default:
+		return errors.New("{{.CurveName}} decoder: unsupported type, need pointer to fr.Element, fp.Element, G1Affine, G2Affine or slice of these")
+	}
+}
+
+// readLength reads the length prefix of a slice
+func (dec *Decoder) readLength() (l, read int, err error) {
+	var buf [4]byte
+	if read, err = io.ReadFull(dec.r, buf[:]); err != nil {
+		return
+	}
+	l = int(binary.BigEndian.Uint32(buf[:]))
+	return
+}
+
+// readPoints reads nbPoints encoded points from the stream. All the points must be either
+// compressed or uncompressed, the first one is used to determine the size of the encoding.
+func (dec *Decoder) readPoints(nbPoints, sizeCompressed, sizeUncompressed int) (buf []byte, size, read int, err error) {
+	if nbPoints == 0 {
+		return
+	}
+	var first [1]byte
+	if read, err = io.ReadFull(dec.r, first[:]); err != nil {
+		return
+	}
+	size = sizeUncompressed
+	if isCompressed(first[0]) {
+		size = sizeCompressed
+	}
+	var n int
+	buf, n, err = dec.readChunked(first[:], nbPoints*size-1)
+	read += n
+	return
+}
+
+// decodeChunkSize bounds the size of the buffers allocated by the Decoder ahead of the data
+// they receive: slice lengths are read from the stream, and can't be trusted to allocate
+// the whole slice upfront.
+const decodeChunkSize = 1 << 20
+
+// readChunked reads size bytes from the stream, appends them to buf and returns the result.
+// Data is read at most decodeChunkSize bytes at a time, so that the memory used grows with
+// the input actually received rather than with size.
+func (dec *Decoder) readChunked(buf []byte, size int) (res []byte, read int, err error) {
+	res = buf
+	for read < size {
+		chunk := size - read
+		if chunk > decodeChunkSize {
+			chunk = decodeChunkSize
+		}
+		offset := len(res)
+		res = append(res, make([]byte, chunk)...)
+		var n int
+		n, err = io.ReadFull(dec.r, res[offset:])
+		read += n
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (dec *Decoder) setG1(p *G1Affine, buf []byte) (int, error) {
+	if dec.noChecks {
+		return p.setBytesNoChecks(buf)
+	}
+	return p.SetBytes(buf)
+}
+
+func (dec *Decoder) setG2(p *G2Affine, buf []byte) (int, error) {
+	if dec.noChecks {
+		return p.setBytesNoChecks(buf)
+	}
+	return p.SetBytes(buf)
+}
+
+// decodeParallel calls decode(i) for i in [0, nbPoints) using all available CPUs, and returns
+// the first error encountered, if any. decode must consume exactly size bytes, so that all the
+// points in a slice share the same form.
+func decodeParallel(nbPoints int, decode func(i int) (int, error), size int) error {
+	var lock sync.Mutex
+	var err error
+	parallel.Execute(nbPoints, func(start, end int) {
+		for i := start; i < end; i++ {
+			n, _err := decode(i)
+			if _err == nil && n != size {
+				_err = errors.New("invalid encoding: compressed and uncompressed points in the same slice")
+			}
+			if _err != nil {
+				lock.Lock()
+				if err == nil {
+					err = _err
+				}
+				lock.Unlock()
+				return
+			}
+		}
+	})
+	return err
+}
+
+{{ template "marshalPoint" dict "all" . "PointName" "G1" "CoordType" "fp.Element" "CurveCoeff" "bCurveCoeff"}}
+{{- if eq .CurveName "bw761"}}
+{{ template "marshalPoint" dict "all" . "PointName" "G2" "CoordType" "fp.Element" "CurveCoeff" "bTwistCurveCoeff"}}
+{{- else}}
+{{ template "marshalPoint" dict "all" . "PointName" "G2" "CoordType" "E2" "CurveCoeff" "bTwistCurveCoeff"}}
+{{- end}}
+
+{{ define "marshalPoint" }}
+{{- $bls := ge .all.FpUnusedBits 3}}
+{{- $E2 := eq .CoordType "E2"}}
+
+// Bytes returns the compressed binary representation of p
+// (x coordinate in regular form and metadata bits in the most significant bits, see mMask)
+func (p *{{.PointName}}Affine) Bytes() (res [SizeOf{{.PointName}}AffineCompressed]byte) {
+
+	// check if p is infinity point
+	if p.X.IsZero() && p.Y.IsZero() {
+		res[0] = mCompressedInfinity
+		return
+	}
+
+	msbMask := mCompressedSmallest
+	// compressed, we need to know if Y is lexicographically bigger than -Y
+	{{- if $E2}}
+	if p.Y.lexicographicallyLargest() {
+	{{- else}}
+	if lexicographicallyLargest(&p.Y) {
+	{{- end}}
+		msbMask = mCompressedLargest
+	}
+
+	{{- if $E2}}
+	// we store X.A1 | X.A0 and mask the most significant byte with our metadata
+	putFp(res[:SizeOfFp], &p.X.A1)
+	putFp(res[SizeOfFp:], &p.X.A0)
+	{{- else}}
+	// we store X and mask the most significant byte with our metadata
+	putFp(res[:], &p.X)
+	{{- end}}
+	res[0] |= msbMask
+
+	return
+}
+
+// RawBytes returns the uncompressed binary representation of p
+// (x and y coordinates in regular form, see mMask for the metadata bits)
+func (p *{{.PointName}}Affine) RawBytes() (res [SizeOf{{.PointName}}AffineUncompressed]byte) {
+
+	// check if p is infinity point
+	if p.X.IsZero() && p.Y.IsZero() {
+		{{- if $bls}}
+		res[0] = mUncompressedInfinity
+		{{- end}}
+		return
+	}
+
+	{{- if $E2}}
+	// we store X.A1 | X.A0 | Y.A1 | Y.A0
+	putFp(res[:SizeOfFp], &p.X.A1)
+	putFp(res[SizeOfFp:2*SizeOfFp], &p.X.A0)
+	putFp(res[2*SizeOfFp:3*SizeOfFp], &p.Y.A1)
+	putFp(res[3*SizeOfFp:], &p.Y.A0)
+	{{- else}}
+	// we store X | Y
+	putFp(res[:SizeOfFp], &p.X)
+	putFp(res[SizeOfFp:], &p.Y)
+	{{- end}}
+	res[0] |= mUncompressed
+
+	return
+}
+
+// SetBytes sets p from the binary representation in buf and returns the number of consumed bytes.
+// buf must start with the output of RawBytes() or Bytes(), the metadata bits in buf[0] tell which one.
+// If buf is too short, io.ErrShortBuffer is returned.
+// It returns an error if the point is not on the curve or not in the correct subgroup, or if,
+// in compressed form, the y coordinate can't be computed (the square root doesn't exist).
+func (p *{{.PointName}}Affine) SetBytes(buf []byte) (int, error) {
+	n, err := p.setBytes(buf)
+	if err != nil {
+		return n, err
+	}
+	if !p.IsInSubGroup() {
+		return 0, errors.New("invalid point: subgroup check failed")
+	}
+	return n, nil
+}
+
+// setBytes sets p from buf, checks that the point is on the curve but not in the subgroup
+func (p *{{.PointName}}Affine) setBytes(buf []byte) (int, error) {
+	if len(buf) < SizeOf{{.PointName}}AffineCompressed {
+		return 0, io.ErrShortBuffer
+	}
+
+	// most significant byte contains metadata
+	mData := buf[0] & mMask
+
+	switch mData {
+	case mCompressedInfinity:
+		if buf[0] != mData || !isZero(buf[1:SizeOf{{.PointName}}AffineCompressed]) {
+			return 0, errors.New("invalid encoding: non zero bits in point at infinity")
+		}
+		p.X.SetZero()
+		p.Y.SetZero()
+		return SizeOf{{.PointName}}AffineCompressed, nil
+	{{- if $bls}}
+	case mUncompressedInfinity:
+		if len(buf) < SizeOf{{.PointName}}AffineUncompressed {
+			return 0, io.ErrShortBuffer
+		}
+		if buf[0] != mData || !isZero(buf[1:SizeOf{{.PointName}}AffineUncompressed]) {
+			return 0, errors.New("invalid encoding: non zero bits in point at infinity")
+		}
+		p.X.SetZero()
+		p.Y.SetZero()
+		return SizeOf{{.PointName}}AffineUncompressed, nil
+	{{- end}}
+	case mUncompressed:
+		if len(buf) < SizeOf{{.PointName}}AffineUncompressed {
+			return 0, io.ErrShortBuffer
+		}
+		{{- if $E2}}
+		// read X.A1 | X.A0 | Y.A1 | Y.A0
+		if err := readFp(&p.X.A1, buf[:SizeOfFp]); err != nil {
+			return 0, err
+		}
+		if err := readFp(&p.X.A0, buf[SizeOfFp:2*SizeOfFp]); err != nil {
+			return 0, err
+		}
+		if err := readFp(&p.Y.A1, buf[2*SizeOfFp:3*SizeOfFp]); err != nil {
+			return 0, err
+		}
+		if err := readFp(&p.Y.A0, buf[3*SizeOfFp:4*SizeOfFp]); err != nil {
+			return 0, err
+		}
+		{{- else}}
+		// read X | Y
+		if err := readFp(&p.X, buf[:SizeOfFp]); err != nil {
+			return 0, err
+		}
+		if err := readFp(&p.Y, buf[SizeOfFp:2*SizeOfFp]); err != nil {
+			return 0, err
+		}
+		{{- end}}
+		{{- if $bls}}
+		// the point at infinity must be encoded with its flag
+		if p.X.IsZero() && p.Y.IsZero() {
+			return 0, errors.New("invalid encoding: point at infinity without infinity flag")
+		}
+		{{- end}}
+		if !p.IsOnCurve() {
+			return 0, errors.New("invalid point: not on curve")
+		}
+		return SizeOf{{.PointName}}AffineUncompressed, nil
+	case mCompressedSmallest, mCompressedLargest:
+		// we copy the buffer to keep this method thread safe (we clear the metadata bits)
+		var bufX [SizeOf{{.PointName}}AffineCompressed]byte
+		copy(bufX[:], buf[:SizeOf{{.PointName}}AffineCompressed])
+		bufX[0] &= ^mMask
+		{{- if $E2}}
+		if err := readFp(&p.X.A1, bufX[:SizeOfFp]); err != nil {
+			return 0, err
+		}
+		if err := readFp(&p.X.A0, bufX[SizeOfFp:]); err != nil {
+			return 0, err
+		}
+		{{- else}}
+		if err := readFp(&p.X, bufX[:]); err != nil {
+			return 0, err
+		}
+		{{- end}}
+		if err := p.computeY(mData == mCompressedLargest); err != nil {
+			return 0, err
+		}
+		return SizeOf{{.PointName}}AffineCompressed, nil
+	default:
+		return 0, errors.New("invalid encoding: unknown metadata bits")
+	}
+}
+
+// setBytesNoChecks sets p from buf like SetBytes, but skips the subgroup check and, for
+// uncompressed points, the on curve check. It must only be used on trusted inputs
+func (p *{{.PointName}}Affine) setBytesNoChecks(buf []byte) (int, error) {
+	if len(buf) < SizeOf{{.PointName}}AffineCompressed {
+		return 0, io.ErrShortBuffer
+	}
+	if buf[0]&mMask != mUncompressed {
+		return p.setBytes(buf)
+	}
+	if len(buf) < SizeOf{{.PointName}}AffineUncompressed {
+		return 0, io.ErrShortBuffer
+	}
+	{{- if $E2}}
+	// read X.A1 | X.A0 | Y.A1 | Y.A0
+	if err := readFp(&p.X.A1, buf[:SizeOfFp]); err != nil {
+		return 0, err
+	}
+	if err := readFp(&p.X.A0, buf[SizeOfFp:2*SizeOfFp]); err != nil {
+		return 0, err
+	}
+	if err := readFp(&p.Y.A1, buf[2*SizeOfFp:3*SizeOfFp]); err != nil {
+		return 0, err
+	}
+	if err := readFp(&p.Y.A0, buf[3*SizeOfFp:4*SizeOfFp]); err != nil {
+		return 0, err
+	}
+	{{- else}}
+	// read X | Y
+	if err := readFp(&p.X, buf[:SizeOfFp]); err != nil {
+		return 0, err
+	}
+	if err := readFp(&p.Y, buf[SizeOfFp:2*SizeOfFp]); err != nil {
+		return 0, err
+	}
+	{{- end}}
+	return SizeOf{{.PointName}}AffineUncompressed, nil
+}
+
+// computeY sets p.Y from p.X, solving the curve equation. largest tells which of the
+// two square roots to select.
+func (p *{{.PointName}}Affine) computeY(largest bool) error {
+	var YSquared, Y {{.CoordType}}
+	YSquared.Square(&p.X).Mul(&YSquared, &p.X).Add(&YSquared, &{{.CurveCoeff}})
+	{{- if $E2}}
+	if YSquared.Legendre() == -1 {
+		return errors.New("invalid compressed coordinate: square root doesn't exist")
+	}
+	Y.Sqrt(&YSquared)
+	{{- else}}
+	if Y.Sqrt(&YSquared) == nil {
+		return errors.New("invalid compressed coordinate: square root doesn't exist")
+	}
+	{{- end}}
+
+	{{- if $E2}}
+	if Y.lexicographicallyLargest() != largest {
+	{{- else}}
+	if lexicographicallyLargest(&Y) != largest {
+	{{- end}}
+		Y.Neg(&Y)
+	}
+	p.Y = Y
+	return nil
+}
+{{ end }}
+`
+
+// MarshalTests ...
+const MarshalTests = `
+
+import (
+	"bytes"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/consensys/gurvy/{{ toLower .CurveName}}/fp"
+	"github.com/consensys/gurvy/{{ toLower .CurveName}}/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestPointsSerialization(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	{{template "testSerialization" dict "all" . "PointName" "G1"}}
+	{{template "testSerialization" dict "all" . "PointName" "G2"}}
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestPointsSerializationInfinity(t *testing.T) {
+	var g1Inf, g1Res G1Affine
+	var g2Inf, g2Res G2Affine
+
+	// points at infinity should round trip in both forms
+	g1Res = g1GenAff
+	g2Res = g2GenAff
+	g1b := g1Inf.Bytes()
+	if n, err := g1Res.SetBytes(g1b[:]); err != nil || n != SizeOfG1AffineCompressed || !g1Res.IsInfinity() {
+		t.Fatal("compressed G1 infinity should round trip")
+	}
+	g2b := g2Inf.Bytes()
+	if n, err := g2Res.SetBytes(g2b[:]); err != nil || n != SizeOfG2AffineCompressed || !g2Res.IsInfinity() {
+		t.Fatal("compressed G2 infinity should round trip")
+	}
+
+	g1Res = g1GenAff
+	g2Res = g2GenAff
+	g1rb := g1Inf.RawBytes()
+	if n, err := g1Res.SetBytes(g1rb[:]); err != nil || n != SizeOfG1AffineUncompressed || !g1Res.IsInfinity() {
+		t.Fatal("uncompressed G1 infinity should round trip")
+	}
+	g2rb := g2Inf.RawBytes()
+	if n, err := g2Res.SetBytes(g2rb[:]); err != nil || n != SizeOfG2AffineUncompressed || !g2Res.IsInfinity() {
+		t.Fatal("uncompressed G2 infinity should round trip")
+	}
+
+	// short buffers should be rejected
+	if _, err := g1Res.SetBytes(g1b[:SizeOfG1AffineCompressed-1]); err == nil {
+		t.Fatal("short buffer should be rejected")
+	}
+	g1rb = g1GenAff.RawBytes()
+	if _, err := g1Res.SetBytes(g1rb[:SizeOfG1AffineCompressed]); err == nil {
+		t.Fatal("short buffer should be rejected")
+	}
+}
+
+func TestEncoder(t *testing.T) {
+
+	// sample values
+	var inA fr.Element
+	var inB fp.Element
+	var inC G1Affine
+	var inD G2Affine
+	var inE, inF []G1Affine
+	var inG []G2Affine
+	var inH []fr.Element
+
+	inA.SetRandom()
+	inB.SetRandom()
+	inC = g1GenAff
+	inD = g2GenAff
+	inE = make([]G1Affine, 4)
+	inF = make([]G1Affine, 0)
+	inG = make([]G2Affine, 3)
+	inH = make([]fr.Element, 5)
+
+	var j1 G1Jac
+	var j2 G2Jac
+	j1.Set(&g1Gen)
+	j2.Set(&g2Gen)
+	for i := 1; i < len(inE); i++ {
+		j1.AddAssign(&g1Gen)
+		inE[i].FromJacobian(&j1)
+	}
+	for i := 1; i < len(inG); i++ {
+		j2.AddAssign(&g2Gen)
+		inG[i].FromJacobian(&j2)
+	}
+	for i := 0; i < len(inH); i++ {
+		inH[i].SetRandom()
+	}
+
+	for _, raw := range []bool{false, true} {
+		var buf bytes.Buffer
+
+		var enc *Encoder
+		if raw {
+			enc = NewEncoder(&buf, RawEncoding())
+		} else {
+			enc = NewEncoder(&buf)
+		}
+
+		toEncode := []interface{}{inA, &inB, &inC, inD, inE, inF, &inG, inH}
+		for _, v := range toEncode {
+			if err := enc.Encode(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if enc.BytesWritten() != int64(buf.Len()) {
+			t.Fatal("wrong number of bytes written")
+		}
+
+		for _, noChecks := range []bool{false, true} {
+			var dec *Decoder
+			if noChecks {
+				dec = NewDecoder(bytes.NewReader(buf.Bytes()), NoChecks())
+			} else {
+				dec = NewDecoder(bytes.NewReader(buf.Bytes()))
+			}
+
+			var outA fr.Element
+			var outB fp.Element
+			var outC G1Affine
+			var outD G2Affine
+			var outE, outF []G1Affine
+			var outG []G2Affine
+			var outH []fr.Element
+
+			toDecode := []interface{}{&outA, &outB, &outC, &outD, &outE, &outF, &outG, &outH}
+			for _, v := range toDecode {
+				if err := dec.Decode(v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if dec.BytesRead() != enc.BytesWritten() {
+				t.Fatal("wrong number of bytes read")
+			}
+
+			// compare values
+			if !outA.Equal(&inA) || !outB.Equal(&inB) {
+				t.Fatal("field elements didn't round trip")
+			}
+			if !outC.Equal(&inC) || !outD.Equal(&inD) {
+				t.Fatal("points didn't round trip")
+			}
+			if len(outE) != len(inE) || len(outF) != len(inF) || len(outG) != len(inG) || len(outH) != len(inH) {
+				t.Fatal("slices length didn't round trip")
+			}
+			for i := 0; i < len(inE); i++ {
+				if !outE[i].Equal(&inE[i]) {
+					t.Fatal("G1 slice didn't round trip")
+				}
+			}
+			for i := 0; i < len(inG); i++ {
+				if !outG[i].Equal(&inG[i]) {
+					t.Fatal("G2 slice didn't round trip")
+				}
+			}
+			for i := 0; i < len(inH); i++ {
+				if !outH[i].Equal(&inH[i]) {
+					t.Fatal("fr slice didn't round trip")
+				}
+			}
+		}
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.Encode([]G1Affine{g1GenAff, g1GenAff}); err != nil {
+		t.Fatal(err)
+	}
+
+	// truncated stream
+	var points []G1Affine
+	dec := NewDecoder(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	if err := dec.Decode(&points); err == nil {
+		t.Fatal("decoding a truncated stream should fail")
+	}
+
+	// unsupported type
+	var i int
+	if err := NewDecoder(bytes.NewReader(buf.Bytes())).Decode(&i); err == nil {
+		t.Fatal("decoding an unsupported type should fail")
+	}
+
+	// non reduced scalar
+	b := make([]byte, SizeOfFr)
+	for j := range b {
+		b[j] = 0xff
+	}
+	var s fr.Element
+	if err := NewDecoder(bytes.NewReader(b)).Decode(&s); err == nil {
+		t.Fatal("decoding a non reduced scalar should fail")
+	}
+}
+
+func TestDecoderLengthPrefix(t *testing.T) {
+	// a length prefix announcing a huge slice, with no data behind it, must not allocate
+	// the whole slice before failing
+	prefix := []byte{0xff, 0xff, 0xff, 0xff}
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	before := ms.TotalAlloc
+
+	var scalars []fr.Element
+	if err := NewDecoder(bytes.NewReader(prefix)).Decode(&scalars); err == nil {
+		t.Fatal("decoding a truncated fr.Element slice should fail")
+	}
+	var points1 []G1Affine
+	if err := NewDecoder(bytes.NewReader(append(prefix, 0))).Decode(&points1); err == nil {
+		t.Fatal("decoding a truncated G1Affine slice should fail")
+	}
+	var points2 []G2Affine
+	if err := NewDecoder(bytes.NewReader(append(prefix, 0))).Decode(&points2); err == nil {
+		t.Fatal("decoding a truncated G2Affine slice should fail")
+	}
+
+	runtime.ReadMemStats(&ms)
+	if allocated := ms.TotalAlloc - before; allocated > 16*decodeChunkSize {
+		t.Fatalf("decoding a truncated slice allocated %d bytes", allocated)
+	}
+
+	// slices larger than a chunk should still round trip
+	in := make([]fr.Element, 2*decodeChunkSize/SizeOfFr+1)
+	for i := 0; i < len(in); i++ {
+		in[i].SetUint64(uint64(i))
+	}
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out []fr.Element
+	if err := NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != len(in) {
+		t.Fatal("slice length didn't round trip")
+	}
+	for i := 0; i < len(in); i++ {
+		if !out[i].Equal(&in[i]) {
+			t.Fatal("fr slice didn't round trip")
+		}
+	}
+}
+
+func BenchmarkDecodeG1Slice(b *testing.B) {
+	const nbPoints = 1 << 10
+	points := make([]G1Affine, nbPoints)
+	var j G1Jac
+	j.Set(&g1Gen)
+	for i := 0; i < nbPoints; i++ {
+		j.AddAssign(&g1Gen)
+		points[i].FromJacobian(&j)
+	}
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(points); err != nil {
+		b.Fatal(err)
+	}
+	var res []G1Affine
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewDecoder(bytes.NewReader(buf.Bytes())).Decode(&res)
+	}
+}
+
+func BenchmarkG1AffineSetBytes(b *testing.B) {
+	buf := g1GenAff.Bytes()
+	var p G1Affine
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.SetBytes(buf[:])
+	}
+}
+
+func BenchmarkG2AffineSetBytes(b *testing.B) {
+	buf := g2GenAff.Bytes()
+	var p G2Affine
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.SetBytes(buf[:])
+	}
+}
+
+{{ define "testSerialization" }}
+	properties.Property("[{{ toUpper .all.CurveName }}] {{.PointName}}Affine SetBytes(RawBytes) should stay the same", prop.ForAll(
+		func(s fr.Element) bool {
+			var start, end {{.PointName}}Affine
+			var ab big.Int
+			var j {{.PointName}}Jac
+			s.ToBigIntRegular(&ab)
+			j.ScalarMultiplication(&{{ toLower .PointName}}Gen, &ab)
+			start.FromJacobian(&j)
+
+			buf := start.RawBytes()
+			n, err := end.SetBytes(buf[:])
+			if err != nil {
+				return false
+			}
+			if n != SizeOf{{.PointName}}AffineUncompressed {
+				return false
+			}
+			return start.X.Equal(&end.X) && start.Y.Equal(&end.Y)
+		},
+		genScalar,
+	))
+
+	properties.Property("[{{ toUpper .all.CurveName }}] {{.PointName}}Affine SetBytes(Bytes()) should stay the same", prop.ForAll(
+		func(s fr.Element) bool {
+			var start, end {{.PointName}}Affine
+			var ab big.Int
+			var j {{.PointName}}Jac
+			s.ToBigIntRegular(&ab)
+			j.ScalarMultiplication(&{{ toLower .PointName}}Gen, &ab)
+			start.FromJacobian(&j)
+
+			buf := start.Bytes()
+			n, err := end.SetBytes(buf[:])
+			if err != nil {
+				return false
+			}
+			if n != SizeOf{{.PointName}}AffineCompressed {
+				return false
+			}
+			return start.X.Equal(&end.X) && start.Y.Equal(&end.Y)
+		},
+		genScalar,
+	))
+
+	properties.Property("[{{ toUpper .all.CurveName }}] {{.PointName}}Affine -p should be encoded with the opposite sign bit", prop.ForAll(
+		func(s fr.Element) bool {
+			var p, pNeg {{.PointName}}Affine
+			var ab big.Int
+			var j {{.PointName}}Jac
+			s.ToBigIntRegular(&ab)
+			j.ScalarMultiplication(&{{ toLower .PointName}}Gen, &ab)
+			p.FromJacobian(&j)
+			pNeg.Neg(&p)
+
+			buf, bufNeg := p.Bytes(), pNeg.Bytes()
+			return (buf[0]&mMask) != (bufNeg[0]&mMask) && (buf[0]&^mMask) == (bufNeg[0]&^mMask)
+		},
+		genScalar,
+	))
+
+	properties.Property("[{{ toUpper .all.CurveName }}] {{.PointName}}Affine SetBytes should reject points that are not on the curve", prop.ForAll(
+		func(s fr.Element) bool {
+			var start, end {{.PointName}}Affine
+			var ab big.Int
+			var j {{.PointName}}Jac
+			s.ToBigIntRegular(&ab)
+			j.ScalarMultiplication(&{{ toLower .PointName}}Gen, &ab)
+			start.FromJacobian(&j)
+			start.Y.Double(&start.Y)
+
+			buf := start.RawBytes()
+			_, err := end.SetBytes(buf[:])
+			return err != nil
+		},
+		genScalar,
+	))
+{{ end }}
+`
@@ -8,6 +8,39 @@ const MultiExpCore = `
 // enabling to set 
 // * max number of cpus to use
 func (p *{{ toUpper .PointName }}Jac) MultiExp(points []{{ toUpper .PointName }}Affine, scalars []fr.Element, opts ...*MultiExpOptions) *{{ toUpper .PointName }}Jac {
+	var opt *MultiExpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewMultiExpOptions(runtime.NumCPU())
+	}
+
+	if _, err := p.multiExp(context.Background(), points, scalars, opt, fr.Limbs*64); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// MultiExpContext computes the same multi exponentiation as MultiExp, but returns an error
+// instead of panicking, and stops when ctx is cancelled; p is modified only if no error is returned.
+// MultiExpOptions.Progress, if set, is called as the chunks are processed
+func (p *{{ toUpper .PointName }}Jac) MultiExpContext(ctx context.Context, points []{{ toUpper .PointName }}Affine, scalars []fr.Element, opts ...*MultiExpOptions) (*{{ toUpper .PointName }}Jac, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("len(points) != len(scalars)")
+	}
+	var opt *MultiExpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewMultiExpOptions(runtime.NumCPU())
+	}
+
+	return p.multiExp(ctx, points, scalars, opt, fr.Limbs*64)
+}
+
+// multiExp computes the multi exponentiation of points by scalars (NOT in Montgomery form)
+// which are known to be smaller than 2^nbBits, allowing to skip the most significant windows
+func (p *{{ toUpper .PointName }}Jac) multiExp(ctx context.Context, points []{{ toUpper .PointName }}Affine, scalars []fr.Element, opt *MultiExpOptions, nbBits int) (*{{ toUpper .PointName }}Jac, error) {
 	// note: 
 	// each of the msmCX method is the same, except for the c constant it declares
 	// duplicating (through template generation) these methods allows to declare the buckets on the stack
@@ -34,6 +67,230 @@ func (p *{{ toUpper .PointName }}Jac) MultiExp(points []{{ toUpper .PointName }}
 	// step 3
 	// reduce the buckets weigthed sums into our result (msmReduceChunk)
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// small scalars need less windows, and scalars in {-1, 0, 1} none at all
+	scalarsBits, ternary := scalarsBitLen(scalars)
+	if ternary {
+		return p.msmTernary(ctx, points, scalars, opt)
+	}
+	if scalarsBits < nbBits {
+		nbBits = scalarsBits
+	}
+
+
+	// the window size is chosen for this call only: opt may be shared by multi exponentiations
+	// of other sizes
+	c := msmBestC{{ toUpper .PointName }}(len(points), nbBits)
+
+	// take all the cpus to ourselves
+	opt.lock.Lock()
+
+	// partition the scalars 
+	// note: we do that before the actual chunk processing, as for each c-bit window (starting from LSW)
+	// if it's larger than 2^{c-1}, we have a carry we need to propagate up to the higher window
+	scalars = partitionScalars(scalars, c)
+
+	// batch affine additions pay off when the buckets receive many points
+	batchAffine := opt.BatchAffine && len(points) >= batchAffineMinPoints
+
+	params := msmParams{
+		batchAffine: batchAffine,
+		nbBits:      nbBits,
+		done:        ctx.Done(),
+		progress:    newMsmProgress(opt.Progress),
+	}
+
+	var res {{ toUpper .PointName }}Jac
+	switch c {
+	{{range $c :=  .CRange}}
+	case {{$c}}:
+		res.msmC{{$c}}(points, scalars, opt, params)
+	{{end}}
+	default:
+		opt.lock.Unlock()
+		return nil, errors.New("unimplemented c")
+	}
+
+	// the chunks of a cancelled multi exponentiation may be incomplete
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.Set(&res)
+	return p, nil
+}
+
+// msmBestC{{ toUpper .PointName }} returns the window size of the implemented msmCX methods minimizing the
+// approximate cost (in group operations) of a multi exponentiation of nbPoints points by nbBits-bit scalars
+func msmBestC{{ toUpper .PointName }}(nbPoints, nbBits int) uint64 {
+	implementedCs := []uint64{
+		{{- range $c :=  .CRange}} {{- if and (eq $.PointName "g1") (gt $c 21)}}{{- else}} {{$c}},{{- end}}{{- end}}
+	}
+
+	// cost = nbChunks * (nbPoints + 2^{c-1}), nbChunks = ceil(nbBits/c) + 1 (carry window)
+	// this needs to be verified empirically.
+	// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
+	var bestC uint64
+	min := math.MaxFloat64
+	for _, c := range implementedCs {
+		nbChunks := (nbBits+int(c)-1)/int(c) + 1
+		cost := float64(nbChunks) * float64(nbPoints+(1<<(c-1)))
+		if cost < min {
+			min = cost
+			bestC = c
+		}
+	}
+
+	// empirical, needs to be tuned.
+	if bestC > 16 && nbPoints < 1<<23 {
+		bestC = 16
+	}
+	return bestC
+}
+
+// msmTernary sets p to the multi exponentiation of points by scalars in {-1, 0, 1} (NOT in Montgomery form),
+// that is a sum of (possibly negated) points. The sum is split in chunks processed in parallel.
+func (p *{{ toUpper .PointName }}Jac) msmTernary(ctx context.Context, points []{{ toUpper .PointName }}Affine, scalars []fr.Element, opt *MultiExpOptions) (*{{ toUpper .PointName }}Jac, error) {
+	nbPoints := len(scalars)
+	nbTasks := cap(opt.chCpus)
+	if nbPoints < msmTernaryMinPointsPerTask*nbTasks {
+		nbTasks = nbPoints/msmTernaryMinPointsPerTask + 1
+	}
+	chunkSize := (nbPoints + nbTasks - 1) / nbTasks
+
+	done := ctx.Done()
+	progress := newMsmProgress(opt.Progress)
+	chChunks := make([]chan {{ toUpper .PointName }}Jac, nbTasks)
+
+	// take all the cpus to ourselves
+	opt.lock.Lock()
+	for i := 0; i < nbTasks; i++ {
+		start, end := i*chunkSize, (i+1)*chunkSize
+		if start > nbPoints {
+			start = nbPoints
+		}
+		if end > nbPoints {
+			end = nbPoints
+		}
+		chChunks[i] = make(chan {{ toUpper .PointName }}Jac, 1)
+		if !opt.acquireCpu(done) {
+			// cancelled, the chunk is not processed
+			chChunks[i] <- {{ toLower .PointName }}Infinity
+			continue
+		}
+		go func(chRes chan<- {{ toUpper .PointName }}Jac, points []{{ toUpper .PointName }}Affine, scalars []fr.Element) {
+			chRes <- msmTernaryChunk{{ toUpper .PointName }}(points, scalars, done)
+			progress.chunkDone(nbTasks)
+			opt.chCpus <- struct{}{} // release token in the semaphore
+		}(chChunks[i], points[start:end], scalars[start:end])
+	}
+	opt.lock.Unlock()
+
+	var res {{ toUpper .PointName }}Jac
+	res.Set(&{{ toLower .PointName }}Infinity)
+	for i := 0; i < nbTasks; i++ {
+		total := <-chChunks[i]
+		res.AddAssign(&total)
+	}
+
+	// the chunks of a cancelled multi exponentiation may be incomplete
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.Set(&res)
+	return p, nil
+}
+
+// msmTernaryChunk{{ toUpper .PointName }} returns the sum of the points with scalar 1, minus the points with scalar -1
+//
+// Large inputs are accumulated in msmTernaryNbLanes affine accumulators, the point i being added to
+// the accumulator i modulo msmTernaryNbLanes: the additions of a round share one field inversion
+// (see batchAdd{{ toUpper .PointName }}Affine)
+func msmTernaryChunk{{ toUpper .PointName }}(points []{{ toUpper .PointName }}Affine, scalars []fr.Element, done <-chan struct{}) {{ toUpper .PointName }}Jac {
+	var total {{ toLower .PointName }}JacExtended
+	total.setInfinity()
+
+	if len(scalars) < batchAffineMinPoints {
+		for i := 0; i < len(scalars); i++ {
+			if i&msmCancelCheckMask == 0 && isDone(done) {
+				return {{ toLower .PointName }}Infinity
+			}
+			if scalars[i].IsZero() {
+				continue
+			}
+			if scalars[i] == frMinusOne {
+				total.mSub(&points[i])
+			} else {
+				total.mAdd(&points[i])
+			}
+		}
+		var res {{ toUpper .PointName }}Jac
+		return *res.fromJacExtended(&total)
+	}
+
+	// lanes are initialized to the infinity symbol (0, 0)
+	lanes := make([]{{ toUpper .PointName }}Affine, msmTernaryNbLanes)
+	laneIDs := make([]uint32, 0, msmTernaryNbLanes)
+	toAdd := make([]{{ toUpper .PointName }}Affine, 0, msmTernaryNbLanes)
+	scratch := make([]{{.CoordType}}, msmTernaryNbLanes)
+
+	lane := uint32(0)
+	for i := 0; i < len(scalars); i++ {
+		if i&msmCancelCheckMask == 0 && isDone(done) {
+			return {{ toLower .PointName }}Infinity
+		}
+		if scalars[i].IsZero() || (points[i].X.IsZero() && points[i].Y.IsZero()) {
+			continue
+		}
+		q := points[i]
+		if scalars[i] == frMinusOne {
+			q.Neg(&q)
+		}
+
+		acc := &lanes[lane]
+		if acc.X.IsZero() && acc.Y.IsZero() {
+			*acc = q
+		} else if acc.X.Equal(&q.X) {
+			if acc.Y.Equal(&q.Y) {
+				// doubling, this should be rare
+				var t {{ toUpper .PointName }}Jac
+				t.FromAffine(acc).DoubleAssign()
+				acc.FromJacobian(&t)
+			} else {
+				// q == -acc
+				*acc = {{ toUpper .PointName }}Affine{}
+			}
+		} else {
+			laneIDs = append(laneIDs, lane)
+			toAdd = append(toAdd, q)
+		}
+
+		// each lane appears at most once in a batch
+		lane++
+		if lane == msmTernaryNbLanes {
+			batchAdd{{ toUpper .PointName }}Affine(lanes, laneIDs, toAdd, scratch)
+			laneIDs, toAdd = laneIDs[:0], toAdd[:0]
+			lane = 0
+		}
+	}
+	batchAdd{{ toUpper .PointName }}Affine(lanes, laneIDs, toAdd, scratch)
+
+	for i := 0; i < len(lanes); i++ {
+		if !(lanes[i].X.IsZero() && lanes[i].Y.IsZero()) {
+			total.mAdd(&lanes[i])
+		}
+	}
+	var res {{ toUpper .PointName }}Jac
+	return *res.fromJacExtended(&total)
+}
+
+// MultiExpMany{{ toUpper .PointName }} computes the multi exponentiations of points by each of the scalars vectors
+// (NOT in Montgomery form), with a single pass over the points per c-bit window for all the vectors.
+// The windows are processed in parallel, within the limits of the (optional) MultiExpOptions.
+// It returns an error if one of the vectors doesn't have one scalar per point
+func MultiExpMany{{ toUpper .PointName }}(points []{{ toUpper .PointName }}Affine, scalars [][]fr.Element, opts ...*MultiExpOptions) ([]{{ toUpper .PointName }}Jac, error) {
 	var opt *MultiExpOptions
 	if len(opts) > 0 {
 		opt = opts[0]
@@ -41,58 +298,228 @@ func (p *{{ toUpper .PointName }}Jac) MultiExp(points []{{ toUpper .PointName }}
 		opt = NewMultiExpOptions(runtime.NumCPU())
 	}
 
-	if opt.c == 0 {
-		nbPoints := len(points)
-	
-		// implemented msmC methods (the c we use must be in this slice)
-		implementedCs := []uint64{
-			{{- range $c :=  .CRange}} {{- if and (eq $.PointName "g1") (gt $c 21)}}{{- else}} {{$c}},{{- end}}{{- end}}
+	nbVectors := len(scalars)
+	res := make([]{{ toUpper .PointName }}Jac, nbVectors)
+	if nbVectors == 0 {
+		return res, nil
+	}
+	nbPoints := len(points)
+	nbBits := 0
+	for v := 0; v < nbVectors; v++ {
+		if len(scalars[v]) != nbPoints {
+			return nil, fmt.Errorf("len(points) != len(scalars[%d])", v)
 		}
-	
-		// approximate cost (in group operations)
-		// cost = bits/c * (nbPoints + 2^{c-1})
-		// this needs to be verified empirically. 
-		// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
-		min := math.MaxFloat64
-		for _, c := range implementedCs {
-			cc := fr.Limbs * 64 * (nbPoints + (1 << (c-1)))
-			cost := float64(cc) / float64(c)
-			if cost < min {
-				min = cost
-				opt.c = c 
+		b, ternary := scalarsBitLen(scalars[v])
+		if ternary {
+			// scalarsBitLen leaves -1 out of the bit length of ternary scalars, but the windows
+			// see it as r-1
+			for i := 0; i < nbPoints; i++ {
+				if scalars[v][i] == frMinusOne {
+					b = fr.Bits
+					break
+				}
 			}
 		}
-
-		// empirical, needs to be tuned.
-		{{if eq .PointName "g1"}}
-		if opt.c > 16 && nbPoints < 1 << 23 {
-			opt.c = 16
-		} 
-		{{else}}
-		if opt.c > 16 && nbPoints < 1 << 23 {
-			opt.c = 16
+		if b > nbBits {
+			nbBits = b
 		}
-		{{end}}
 	}
-	
 
+	c := msmManyBestC(nbPoints, nbVectors, nbBits)
+	digits := make([][]fr.Element, nbVectors)
+	for v := 0; v < nbVectors; v++ {
+		digits[v] = partitionScalars(scalars[v], c)
+	}
+
+	nbChunks := fr.Limbs * 64 / int(c)
+	if (fr.Limbs*64)%int(c) != 0 {
+		nbChunks++
+	}
+	nbChunks = msmParams{nbBits: nbBits}.nbChunks(int(c), nbChunks)
+
+	// totals[chunk][v] is the weighted sum of the buckets of the vector v in the window chunk
+	totals := make([][]{{ toUpper .PointName }}Jac, nbChunks)
+	var wg sync.WaitGroup
+	wg.Add(nbChunks)
 
 	// take all the cpus to ourselves
 	opt.lock.Lock()
+	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+		<-opt.chCpus // wait to have a cpu before scheduling
+		go func(chunk int) {
+			totals[chunk] = msmManyProcessChunk{{ toUpper .PointName }}(uint64(chunk), c, points, digits)
+			opt.chCpus <- struct{}{} // release token in the semaphore
+			wg.Done()
+		}(chunk)
+	}
+	opt.lock.Unlock()
+	wg.Wait()
+
+	for v := 0; v < nbVectors; v++ {
+		res[v].Set(&{{ toLower .PointName }}Infinity)
+		for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+			for l := uint64(0); l < c; l++ {
+				res[v].DoubleAssign()
+			}
+			res[v].AddAssign(&totals[chunk][v])
+		}
+	}
+	return res, nil
+}
 
-	// partition the scalars 
-	// note: we do that before the actual chunk processing, as for each c-bit window (starting from LSW)
-	// if it's larger than 2^{c-1}, we have a carry we need to propagate up to the higher window
-	scalars = partitionScalars(scalars, opt.c)
+// msmManyProcessChunk{{ toUpper .PointName }} returns, for each of the vectors of digits, the weighted sum of the buckets of the window chunk
+// each block of points is read once, and added to the buckets of all the vectors
+func msmManyProcessChunk{{ toUpper .PointName }}(chunk, c uint64, points []{{ toUpper .PointName }}Affine, digits [][]fr.Element) []{{ toUpper .PointName }}Jac {
+	nbBuckets := 1 << (c - 1)
+	msbWindow := uint64(nbBuckets)
 
-	switch opt.c {
-	{{range $c :=  .CRange}}
-	case {{$c}}:
-		return p.msmC{{$c}}(points, scalars, opt)	
-	{{end}}
-	default:
-		panic("unimplemented")
+	buckets := make([]{{ toLower .PointName }}JacExtended, len(digits)*nbBuckets)
+	for i := 0; i < len(buckets); i++ {
+		buckets[i].setInfinity()
+	}
+
+	s := newChunkSelector(chunk, c)
+
+	// the points are processed in blocks which stay in cache while they are added to the buckets of each vector
+	for start := 0; start < len(points); start += msmManyBlockSize {
+		end := start + msmManyBlockSize
+		if end > len(points) {
+			end = len(points)
+		}
+		for v := 0; v < len(digits); v++ {
+			vBuckets := buckets[v*nbBuckets : (v+1)*nbBuckets]
+			vDigits := digits[v]
+			for i := start; i < end; i++ {
+				bits := (vDigits[i][s.index] & s.mask) >> s.shift
+				if s.multiWordSelect {
+					bits += (vDigits[i][s.index+1] & s.maskHigh) << s.shiftHigh
+				}
+
+				if bits == 0 {
+					continue
+				}
+
+				// if msbWindow bit is set, we need to substract
+				if bits&msbWindow == 0 {
+					vBuckets[bits-1].mAdd(&points[i])
+				} else {
+					vBuckets[bits & ^msbWindow].mSub(&points[i])
+				}
+			}
+		}
+	}
+
+	// reduce buckets into totals
+	// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+	totals := make([]{{ toUpper .PointName }}Jac, len(digits))
+	for v := 0; v < len(digits); v++ {
+		vBuckets := buckets[v*nbBuckets : (v+1)*nbBuckets]
+		var runningSum, tj {{ toUpper .PointName }}Jac
+		runningSum.Set(&{{ toLower .PointName }}Infinity)
+		totals[v].Set(&{{ toLower .PointName }}Infinity)
+		for k := len(vBuckets) - 1; k >= 0; k-- {
+			if !vBuckets[k].ZZ.IsZero() {
+				runningSum.AddAssign(tj.unsafeFromJacExtended(&vBuckets[k]))
+			}
+			totals[v].AddAssign(&runningSum)
+		}
 	}
+	return totals
+}
+
+// MultiExpReaderAt computes the multi exponentiation of the len(scalars) points read from r by scalars
+// (NOT in Montgomery form), without holding all the points in memory
+//
+// r holds the raw encoded points (see RawBytes), contiguous from offset 0: for example a file written by
+// an Encoder with the RawEncoding option, past the 4 bytes length prefix (see io.NewSectionReader), or a
+// memory-mapped file (see bytes.NewReader). The points are not checked: r must be a trusted input.
+//
+// The points are processed in segments, the next segment being read while the current one is processed;
+// the memory used for the points and the scalars (not counting the scalars argument) stays below memoryBudget bytes.
+// p is modified only if no error is returned
+func (p *{{ toUpper .PointName }}Jac) MultiExpReaderAt(ctx context.Context, r io.ReaderAt, scalars []fr.Element, memoryBudget int, opts ...*MultiExpOptions) (*{{ toUpper .PointName }}Jac, error) {
+	var opt *MultiExpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewMultiExpOptions(runtime.NumCPU())
+	}
+
+	// per point: the raw bytes read, 2 decoded points (the current segment and the next one), and a partitioned scalar
+	const pointSize = SizeOf{{ toUpper .PointName }}AffineUncompressed
+	const memoryPerPoint = 3*pointSize + SizeOfFr
+	segmentSize := memoryBudget / memoryPerPoint
+	if segmentSize < 1 {
+		return nil, errors.New("memory budget is too small")
+	}
+	nbPoints := len(scalars)
+	if segmentSize > nbPoints {
+		segmentSize = nbPoints
+	}
+	nbSegments := 0
+	if nbPoints > 0 {
+		nbSegments = (nbPoints + segmentSize - 1) / segmentSize
+	}
+
+	type segment struct {
+		points []{{ toUpper .PointName }}Affine
+		err    error
+	}
+	chSegments := make(chan segment)
+	done := make(chan struct{})
+	defer close(done)
+
+	// the segment k is decoded in segments[k%2]; it is written only after the segment k-1 is processed
+	go func() {
+		buf := make([]byte, segmentSize*pointSize)
+		var segments [2][]{{ toUpper .PointName }}Affine
+		for k := 0; k < nbSegments; k++ {
+			start := k * segmentSize
+			n := segmentSize
+			if start+n > nbPoints {
+				n = nbPoints - start
+			}
+			if segments[k%2] == nil {
+				segments[k%2] = make([]{{ toUpper .PointName }}Affine, segmentSize)
+			}
+			points := segments[k%2][:n]
+
+			read, err := r.ReadAt(buf[:n*pointSize], int64(start)*pointSize)
+			if err == io.EOF && read == n*pointSize {
+				err = nil
+			}
+			if err == nil {
+				err = decodeParallel(n, func(i int) (int, error) {
+					return points[i].setBytesNoChecks(buf[i*pointSize : (i+1)*pointSize])
+				}, pointSize)
+			}
+
+			select {
+			case chSegments <- segment{points, err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var res, partial {{ toUpper .PointName }}Jac
+	res.Set(&{{ toLower .PointName }}Infinity)
+	for k := 0; k < nbSegments; k++ {
+		seg := <-chSegments
+		if seg.err != nil {
+			return nil, seg.err
+		}
+		start := k * segmentSize
+		if _, err := partial.multiExp(ctx, seg.points, scalars[start:start+len(seg.points)], opt, fr.Limbs*64); err != nil {
+			return nil, err
+		}
+		res.AddAssign(&partial)
+	}
+	p.Set(&res)
+	return p, nil
 }
 
 // msmReduceChunk{{ toUpper .PointName }} reduces the weighted sum of the buckets into the result of the multiExp
@@ -115,31 +542,26 @@ func msmProcessChunk{{ toUpper .PointName }}(chunk uint64,
 	 buckets []{{ toLower .PointName }}JacExtended,
 	 c uint64,
 	 points []{{ toUpper .PointName }}Affine,
-	 scalars []fr.Element) {
+	 scalars []fr.Element,
+	 done <-chan struct{}) {
 
 
-	mask  := uint64((1 << c) - 1)	// low c bits are 1
 	msbWindow  := uint64(1 << (c -1)) 
 	
 	for i := 0 ; i < len(buckets); i++ {
 		buckets[i].setInfinity()
 	}
 
-	jc := uint64(chunk * c)
-	s := selector{}
-	s.index = jc / 64
-	s.shift = jc - (s.index * 64)
-	s.mask = mask << s.shift
-	s.multiWordSelect = (64 %c)!=0   && s.shift > (64-c) && s.index < (fr.Limbs - 1 )
-	if s.multiWordSelect {
-		nbBitsHigh := s.shift - uint64(64-c)
-		s.maskHigh = (1 << nbBitsHigh) - 1
-		s.shiftHigh = (c - nbBitsHigh)
-	}
+	s := newChunkSelector(chunk, c)
 
 
 	// for each scalars, get the digit corresponding to the chunk we're processing. 
 	for i := 0; i < len(scalars); i++ {
+		if i&msmCancelCheckMask == 0 && isDone(done) {
+			chRes <- {{ toLower .PointName }}Infinity
+			close(chRes)
+			return
+		}
 		bits := (scalars[i][s.index] & s.mask) >> s.shift
 		if s.multiWordSelect {
 			bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
@@ -179,34 +601,280 @@ func msmProcessChunk{{ toUpper .PointName }}(chunk uint64,
 } 
 
 
+// msmProcessChunk{{ toUpper .PointName }}BatchAffine does the same as msmProcessChunk{{ toUpper .PointName }}, with buckets in affine coordinates
+//
+// Additions into the buckets are batched: the slopes of up to batchSize additions share a single
+// field inversion (Montgomery's trick). A bucket appears at most once in a batch; points for a
+// bucket already in the current batch are queued, and scheduled once when the batch is executed.
+// The queue holds at most batchSize points: when it is full, or when the bucket of a queued point
+// is in the batch again, the point is added to a second set of buckets in extended Jacobian
+// coordinates instead. This bounds the work per point when the digits are skewed (e.g. all the
+// scalars are equal), where most points target the same few buckets.
+func msmProcessChunk{{ toUpper .PointName }}BatchAffine(chunk uint64,
+	chRes chan<- {{ toUpper .PointName }}Jac,
+	nbBuckets int,
+	c uint64,
+	points []{{ toUpper .PointName }}Affine,
+	scalars []fr.Element,
+	done <-chan struct{}) {
+
+	if nbBuckets < batchAffineMinBuckets {
+		// batches would be too small to amortize the inversion (this happens for the last, smaller, window)
+		buckets := make([]{{ toLower .PointName }}JacExtended, nbBuckets)
+		msmProcessChunk{{ toUpper .PointName }}(chunk, chRes, buckets, c, points, scalars, done)
+		return
+	}
+
+	msbWindow := uint64(1 << (c - 1))
+	s := newChunkSelector(chunk, c)
+
+	// buckets are initialized to the infinity symbol (0, 0)
+	buckets := make([]{{ toUpper .PointName }}Affine, nbBuckets)
+
+	batchSize := nbBuckets / batchAffineBucketsPerAdd
+	if batchSize > batchAffineMaxSize {
+		batchSize = batchAffineMaxSize
+	}
+
+	inBatch := make([]bool, nbBuckets)
+	bucketIDs := make([]uint32, 0, batchSize)
+	toAdd := make([]{{ toUpper .PointName }}Affine, 0, batchSize)
+	scratch := make([]{{.CoordType}}, batchSize)
+
+	type queuedAdd struct {
+		bucketID uint32
+		point    {{ toUpper .PointName }}Affine
+	}
+	queue := make([]queuedAdd, 0, batchSize)
+
+	// overflow buckets, in extended Jacobian coordinates, receive the conflicting points that don't
+	// fit in the queue, and the doublings
+	var overflow []{{ toLower .PointName }}JacExtended
+	addOverflow := func(id uint32, q *{{ toUpper .PointName }}Affine) {
+		if overflow == nil {
+			overflow = make([]{{ toLower .PointName }}JacExtended, nbBuckets)
+			for i := 0; i < len(overflow); i++ {
+				overflow[i].setInfinity()
+			}
+		}
+		overflow[id].mAdd(q)
+	}
+
+	executeBatch := func() {
+		batchAdd{{ toUpper .PointName }}Affine(buckets, bucketIDs, toAdd, scratch)
+		for _, id := range bucketIDs {
+			inBatch[id] = false
+		}
+		bucketIDs = bucketIDs[:0]
+		toAdd = toAdd[:0]
+	}
+
+	// scheduleAdd adds q to bucket id, or schedules it in the current batch.
+	// the bucket must not be in the current batch
+	scheduleAdd := func(id uint32, q *{{ toUpper .PointName }}Affine) {
+		bucket := &buckets[id]
+		if bucket.X.IsZero() && bucket.Y.IsZero() {
+			*bucket = *q
+			return
+		}
+		if bucket.X.Equal(&q.X) {
+			if bucket.Y.Equal(&q.Y) {
+				// doubling, which the affine batch doesn't handle
+				addOverflow(id, q)
+			} else {
+				// q == -bucket
+				*bucket = {{ toUpper .PointName }}Affine{}
+			}
+			return
+		}
+		inBatch[id] = true
+		bucketIDs = append(bucketIDs, id)
+		toAdd = append(toAdd, *q)
+		if len(bucketIDs) == batchSize {
+			executeBatch()
+		}
+	}
+
+	// processQueue empties the queue once a batch was executed: the queued additions are scheduled,
+	// or sent to the overflow buckets if their bucket is in the current batch again
+	processQueue := func() {
+		for i := 0; i < len(queue); i++ {
+			if inBatch[queue[i].bucketID] {
+				addOverflow(queue[i].bucketID, &queue[i].point)
+				continue
+			}
+			scheduleAdd(queue[i].bucketID, &queue[i].point)
+		}
+		queue = queue[:0]
+	}
+
+	// for each scalars, get the digit corresponding to the chunk we're processing.
+	for i := 0; i < len(scalars); i++ {
+		if i&msmCancelCheckMask == 0 && isDone(done) {
+			chRes <- {{ toLower .PointName }}Infinity
+			close(chRes)
+			return
+		}
+		bits := (scalars[i][s.index] & s.mask) >> s.shift
+		if s.multiWordSelect {
+			bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
+		}
+
+		if bits == 0 || (points[i].X.IsZero() && points[i].Y.IsZero()) {
+			continue
+		}
+
+		var id uint32
+		q := points[i]
+		// if msbWindow bit is set, we need to substract
+		if bits&msbWindow == 0 {
+			id = uint32(bits - 1)
+		} else {
+			id = uint32(bits & ^msbWindow)
+			q.Neg(&q)
+		}
+
+		if inBatch[id] {
+			if len(queue) == cap(queue) {
+				addOverflow(id, &q)
+			} else {
+				queue = append(queue, queuedAdd{id, q})
+			}
+			continue
+		}
+		scheduleAdd(id, &q)
+		if len(bucketIDs) == 0 && len(queue) != 0 {
+			// a batch was just executed
+			processQueue()
+		}
+	}
+
+	// flush the batches and the queue: processQueue empties the queue, so this runs at most twice
+	for len(bucketIDs) != 0 || len(queue) != 0 {
+		executeBatch()
+		processQueue()
+	}
+
+	// reduce buckets into total
+	// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+
+	var runningSum, tj, total {{ toUpper .PointName }}Jac
+	runningSum.Set(&{{ toLower .PointName }}Infinity)
+	total.Set(&{{ toLower .PointName }}Infinity)
+	for k := len(buckets) - 1; k >= 0; k-- {
+		runningSum.AddMixed(&buckets[k])
+		if overflow != nil && !overflow[k].ZZ.IsZero() {
+			runningSum.AddAssign(tj.unsafeFromJacExtended(&overflow[k]))
+		}
+		total.AddAssign(&runningSum)
+	}
+
+	chRes <- total
+	close(chRes)
+}
+
+// batchAdd{{ toUpper .PointName }}Affine sets buckets[ids[i]] += points[i] for all i, with a single field inversion
+//
+// the buckets must be distinct, and the additions must not be doublings or involve the infinity
+// scratch must be at least len(ids) long
+func batchAdd{{ toUpper .PointName }}Affine(buckets []{{ toUpper .PointName }}Affine, ids []uint32, points []{{ toUpper .PointName }}Affine, scratch []{{.CoordType}}) {
+	if len(ids) == 0 {
+		return
+	}
+
+	// scratch[i] = prod_{j<i} (points[j].X - buckets[ids[j]].X)
+	var acc, d {{.CoordType}}
+	acc.SetOne()
+	for i := 0; i < len(ids); i++ {
+		scratch[i] = acc
+		d.Sub(&points[i].X, &buckets[ids[i]].X)
+		acc.Mul(&acc, &d)
+	}
+
+	acc.Inverse(&acc)
+
+	var lambda, t {{.CoordType}}
+	for i := len(ids) - 1; i >= 0; i-- {
+		r := &buckets[ids[i]]
+
+		// acc = 1 / prod_{j<=i} d_j so 1 / d_i = acc * scratch[i]
+		d.Sub(&points[i].X, &r.X)
+		lambda.Mul(&acc, &scratch[i])
+		acc.Mul(&acc, &d)
+
+		// lambda = (y2 - y1) / (x2 - x1)
+		t.Sub(&points[i].Y, &r.Y)
+		lambda.Mul(&lambda, &t)
+
+		// x3 = lambda**2 - x1 - x2
+		t.Square(&lambda).
+			Sub(&t, &r.X).
+			Sub(&t, &points[i].X)
+
+		// y3 = lambda * (x1 - x3) - y1
+		d.Sub(&r.X, &t)
+		d.Mul(&lambda, &d)
+		r.Y.Sub(&d, &r.Y)
+		r.X = t
+	}
+}
+
 {{range $c :=  .CRange}}
 
-func (p *{{ toUpper $.PointName }}Jac) msmC{{$c}}(points []{{ toUpper $.PointName }}Affine, scalars []fr.Element, opt *MultiExpOptions) *{{ toUpper $.PointName }}Jac {
+func (p *{{ toUpper $.PointName }}Jac) msmC{{$c}}(points []{{ toUpper $.PointName }}Affine, scalars []fr.Element, opt *MultiExpOptions, params msmParams) *{{ toUpper $.PointName }}Jac {
 	{{- $cDividesBits := divides $c $.RBitLen}}
 	const c  = {{$c}} 							// scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) {{if not $cDividesBits }} + 1 {{end}} // number of c-bit radixes in a scalar
 	// for each chunk, spawn a go routine that'll loop through all the scalars
 	var chChunks [nbChunks]chan {{ toUpper $.PointName }}Jac
+
+	// chunks above nbChunksUsed only have zero digits
+	nbChunksUsed := params.nbChunks(c, nbChunks)
+	for chunk := nbChunksUsed; chunk < nbChunks; chunk++ {
+		chChunks[chunk] = make(chan {{ toUpper $.PointName }}Jac, 1)
+		chChunks[chunk] <- {{ toLower $.PointName }}Infinity
+	}
+	start := nbChunksUsed - 1
+
 	{{- if not $cDividesBits }}
 	// c doesn't divide {{$.RBitLen}}, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
-	chChunks[nbChunks-1] = make(chan {{ toUpper $.PointName }}Jac, 1)
-	<-opt.chCpus  // wait to have a cpu before scheduling 
-	go func(j uint64, chRes chan {{ toUpper $.PointName }}Jac, points []{{ toUpper $.PointName }}Affine, scalars []fr.Element) {
-		var buckets [1<<(lastC-1)]{{ toLower $.PointName }}JacExtended
-		msmProcessChunk{{ toUpper $.PointName }}(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
-
-	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
-	{{- else}}
-	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+	if nbChunksUsed == nbChunks {
+		chChunks[nbChunks-1] = make(chan {{ toUpper $.PointName }}Jac, 1)
+		if opt.acquireCpu(params.done) {
+			go func(j uint64, chRes chan {{ toUpper $.PointName }}Jac, points []{{ toUpper $.PointName }}Affine, scalars []fr.Element) {
+				if params.batchAffine {
+					msmProcessChunk{{ toUpper $.PointName }}BatchAffine(j, chRes, 1<<(lastC-1), c, points, scalars, params.done)
+				} else {
+					var buckets [1<<(lastC-1)]{{ toLower $.PointName }}JacExtended
+					msmProcessChunk{{ toUpper $.PointName }}(j, chRes, buckets[:], c, points, scalars, params.done)
+				}
+				params.progress.chunkDone(nbChunksUsed)
+				opt.chCpus <- struct{}{} // release token in the semaphore
+			}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+		} else {
+			// cancelled, the chunk is not processed
+			chChunks[nbChunks-1] <- {{ toLower $.PointName }}Infinity
+		}
+		start--
+	}
 	{{- end}}
+
+	for chunk := start; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan {{ toUpper $.PointName }}Jac, 1)
-		<-opt.chCpus  // wait to have a cpu before scheduling 
+		if !opt.acquireCpu(params.done) {
+			// cancelled, the chunk is not processed
+			chChunks[chunk] <- {{ toLower $.PointName }}Infinity
+			continue
+		}
 		go func(j uint64, chRes chan {{ toUpper $.PointName }}Jac, points []{{ toUpper $.PointName }}Affine, scalars []fr.Element) {
-			var buckets [1<<(c-1)]{{ toLower $.PointName }}JacExtended
-			msmProcessChunk{{ toUpper $.PointName }}(j, chRes,  buckets[:], c, points, scalars)
+			if params.batchAffine {
+				msmProcessChunk{{ toUpper $.PointName }}BatchAffine(j, chRes, 1<<(c-1), c, points, scalars, params.done)
+			} else {
+				var buckets [1<<(c-1)]{{ toLower $.PointName }}JacExtended
+				msmProcessChunk{{ toUpper $.PointName }}(j, chRes,  buckets[:], c, points, scalars, params.done)
+			}
+			params.progress.chunkDone(nbChunksUsed)
 			opt.chCpus <- struct{}{} // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
@@ -424,6 +1092,145 @@ func (p *{{ toLower .PointName }}JacExtended) double(q *{{ toUpper .PointName }}
 	return p
 }
 
+
+// PrecomputedBases{{ toUpper .PointName }} stores, for a fixed set of bases P_i, the shifted multiples 2^{k*shift}·P_i
+// for k < factor and shift = ceil(fr.Bits / factor)
+//
+// a multi-exponentiation over the P_i then reduces to a multi-exponentiation over factor times
+// more points, with shift-bit scalars: it needs factor times less windows and doublings
+type PrecomputedBases{{ toUpper .PointName }} struct {
+	factor int
+	points []{{ toUpper .PointName }}Affine // points[k*n+i] = 2^{k*shift}·P_i
+}
+
+// NewPrecomputedBases{{ toUpper .PointName }} returns the precomputed multiples of points, for a precomputation
+// factor in [1, fr.Bits]; memory usage is factor times the size of points
+func NewPrecomputedBases{{ toUpper .PointName }}(points []{{ toUpper .PointName }}Affine, factor int) (*PrecomputedBases{{ toUpper .PointName }}, error) {
+	if factor < 1 || factor > fr.Bits {
+		return nil, errors.New("invalid precomputation factor")
+	}
+	n := len(points)
+	shift := (fr.Bits + factor - 1) / factor
+
+	jac := make([]{{ toUpper .PointName }}Jac, n*factor)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			var acc {{ toUpper .PointName }}Jac
+			acc.FromAffine(&points[i])
+			jac[i] = acc
+			for k := 1; k < factor; k++ {
+				for j := 0; j < shift; j++ {
+					acc.DoubleAssign()
+				}
+				jac[k*n+i] = acc
+			}
+		}
+	})
+
+	res := &PrecomputedBases{{ toUpper .PointName }}{factor: factor, points: make([]{{ toUpper .PointName }}Affine, n*factor)}
+	{{- if eq .PointName "g1"}}
+	BatchJacobianToAffine{{ toUpper .PointName }}(jac, res.points)
+	{{- else}}
+	parallel.Execute(len(jac), func(start, end int) {
+		for i := start; i < end; i++ {
+			res.points[i].FromJacobian(&jac[i])
+		}
+	})
+	{{- end}}
+	return res, nil
+}
+
+// Len returns the number of bases P_i
+func (pb *PrecomputedBases{{ toUpper .PointName }}) Len() int {
+	return len(pb.points) / pb.factor
+}
+
+// Factor returns the precomputation factor
+func (pb *PrecomputedBases{{ toUpper .PointName }}) Factor() int {
+	return pb.factor
+}
+
+// MultiExpPrecomputed computes Σ scalars[i]·P_i over the precomputed bases P_i
+// the scalars are NOT in Montgomery form; it returns an error if there isn't one scalar per base.
+// p is modified only if no error is returned
+func (p *{{ toUpper .PointName }}Jac) MultiExpPrecomputed(bases *PrecomputedBases{{ toUpper .PointName }}, scalars []fr.Element, opts ...*MultiExpOptions) (*{{ toUpper .PointName }}Jac, error) {
+	if bases == nil || bases.factor < 1 {
+		return nil, errors.New("invalid precomputed bases")
+	}
+	n := bases.Len()
+	if len(scalars) != n {
+		return nil, errors.New("number of scalars must match the number of precomputed bases")
+	}
+	var opt *MultiExpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewMultiExpOptions(runtime.NumCPU())
+	}
+
+	// split each scalar in factor shift-bit scalars, the k-th one multiplying 2^{k*shift}·P_i
+	shift := (fr.Bits + bases.factor - 1) / bases.factor
+	digits := make([]fr.Element, len(bases.points))
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			for k := 0; k < bases.factor; k++ {
+				digits[k*n+i] = scalarBits(&scalars[i], k*shift, shift)
+			}
+		}
+	})
+
+	return p.multiExp(context.Background(), bases.points, digits, opt, shift)
+}
+
+// WriteTo writes the precomputed bases to w: the factor as a big-endian uint32, followed
+// by the compressed points (see Encoder)
+func (pb *PrecomputedBases{{ toUpper .PointName }}) WriteTo(w io.Writer) (int64, error) {
+	return pb.writeTo(w)
+}
+
+// WriteRawTo writes the precomputed bases to w like WriteTo, with uncompressed points (see RawEncoding).
+// The encoding is twice as large, but ReadFrom doesn't need to decompress the points: it is much
+// faster to read
+func (pb *PrecomputedBases{{ toUpper .PointName }}) WriteRawTo(w io.Writer) (int64, error) {
+	return pb.writeTo(w, RawEncoding())
+}
+
+func (pb *PrecomputedBases{{ toUpper .PointName }}) writeTo(w io.Writer, options ...func(*Encoder)) (int64, error) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(pb.factor))
+	n, err := w.Write(buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	enc := NewEncoder(w, options...)
+	err = enc.Encode(pb.points)
+	return int64(n) + enc.BytesWritten(), err
+}
+
+// ReadFrom reads precomputed bases written by WriteTo or WriteRawTo
+// the points are not checked (see NoChecks): r must be a trusted input
+func (pb *PrecomputedBases{{ toUpper .PointName }}) ReadFrom(r io.Reader) (int64, error) {
+	var buf [4]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	factor := int(binary.BigEndian.Uint32(buf[:]))
+	if factor < 1 || factor > fr.Bits {
+		return int64(n), errors.New("invalid encoding: invalid precomputation factor")
+	}
+	var points []{{ toUpper .PointName }}Affine
+	dec := NewDecoder(r, NoChecks())
+	err = dec.Decode(&points)
+	if err != nil {
+		return int64(n) + dec.BytesRead(), err
+	}
+	if len(points)%factor != 0 {
+		return int64(n) + dec.BytesRead(), errors.New("invalid encoding: number of points is not a multiple of the precomputation factor")
+	}
+	pb.factor, pb.points = factor, points
+	return int64(n) + dec.BytesRead(), nil
+}
 `
 
 // MultiExpHelpers is common to both points (only one is generated per package)
@@ -435,11 +1242,26 @@ import (
 
 // MultiExpOptions enables users to set optional parameters to the multiexp
 type MultiExpOptions struct {
-	c uint64
 	chCpus chan struct{} // semaphore to limit number of cpus iterating through points and scalrs at the same time
 	lock sync.Mutex 
+
+	// BatchAffine enables bucket accumulation with batched affine additions (one field inversion
+	// per batch, cf Montgomery's trick) instead of extended Jacobian additions. It is faster for
+	// large inputs, and ignored below batchAffineMinPoints points.
+	BatchAffine bool
+
+	// Progress, if set, is called each time a chunk (c-bit window) of a multiExp is processed, with
+	// the number of processed chunks and the total number of chunks. Calls are serialized.
+	Progress func(done, total int)
 }
 
+const (
+	batchAffineMinPoints = 1 << 14 // below, MultiExp falls back to extended Jacobian buckets
+	batchAffineMinBuckets = 1 << 10 // windows with less buckets fall back to extended Jacobian buckets
+	batchAffineBucketsPerAdd = 8 // batch size is nbBuckets / batchAffineBucketsPerAdd, which limits conflicts
+	batchAffineMaxSize = 1 << 10 // maximum number of additions in a batch
+)
+
 // NewMultiExpOptions returns a new multiExp options to be used with MultiExp
 // this option can be shared between different MultiExp calls and will ensure only numCpus are used
 // through a semaphore
@@ -466,6 +1288,178 @@ type selector struct {
 	shiftHigh uint64		// same than shift, for index+1
 }
 
+// msmParams are the parameters of a call to the msmCX methods
+type msmParams struct {
+	batchAffine bool // accumulate the buckets with batch affine additions
+	nbBits      int  // the scalars (before partitionScalars) are smaller than 2^nbBits
+	done        <-chan struct{} // closed when the multiExp is cancelled, nil if it can't be
+	progress    *msmProgress    // nil if progress is not reported
+}
+
+// msmCancelCheckMask sets how often the msmProcessChunk functions check for cancellation (every 4096 scalars)
+const msmCancelCheckMask = (1 << 12) - 1
+
+// msmTernaryMinPointsPerTask is the minimum number of points summed by a go routine in msmTernary
+const msmTernaryMinPointsPerTask = 1 << 10
+
+// msmTernaryNbLanes is the number of accumulators of the batch affine sums in msmTernary
+const msmTernaryNbLanes = 1 << 9
+
+// frMinusOne is -1 (NOT in Montgomery form)
+var frMinusOne fr.Element
+
+func init() {
+	frMinusOne.SetOne().Neg(&frMinusOne).FromMont()
+}
+
+// scalarsBitLen returns the bit length of the largest scalar (NOT in Montgomery form),
+// and true if all the scalars are in {-1, 0, 1}
+func scalarsBitLen(scalars []fr.Element) (nbBits int, ternary bool) {
+	var or fr.Element
+	hasMinusOne := false
+	for i := 0; i < len(scalars); i++ {
+		if scalars[i] == frMinusOne {
+			hasMinusOne = true
+			continue
+		}
+		for j := 0; j < fr.Limbs; j++ {
+			or[j] |= scalars[i][j]
+		}
+	}
+	ternary = or[0] <= 1
+	for j := fr.Limbs - 1; j >= 0; j-- {
+		if or[j] != 0 {
+			nbBits = j*64 + bits.Len64(or[j])
+			ternary = ternary && j == 0
+			break
+		}
+	}
+	if hasMinusOne && !ternary {
+		nbBits = fr.Bits
+	}
+	return
+}
+
+// msmManyBlockSize is the number of points read from memory once for all the vectors in MultiExpMany
+const msmManyBlockSize = 1 << 10
+
+// msmManyMaxBuckets bounds the number of buckets (for all the vectors) of a window in MultiExpMany
+const msmManyMaxBuckets = 1 << 17
+
+// msmManyBestC returns the window size minimizing the approximate cost (in group operations)
+// of MultiExpMany, with less than msmManyMaxBuckets buckets per window
+func msmManyBestC(nbPoints, nbVectors, nbBits int) uint64 {
+	var bestC uint64 = 4
+	min := math.MaxFloat64
+	for c := 4; c <= 16; c++ {
+		if c > 4 && nbVectors<<(c-1) > msmManyMaxBuckets {
+			break
+		}
+		nbChunks := (nbBits+c-1)/c + 1
+		cost := float64(nbChunks) * float64(nbVectors) * float64(nbPoints+(1<<(c-1)))
+		if cost < min {
+			min = cost
+			bestC = uint64(c)
+		}
+	}
+	return bestC
+}
+
+// acquireCpu waits for a token of the cpu semaphore, and returns false without taking one if done
+// is closed first: a cancelled multiExp doesn't wait for the tokens held by other calls
+func (opt *MultiExpOptions) acquireCpu(done <-chan struct{}) bool {
+	if isDone(done) {
+		return false
+	}
+	select {
+	case <-opt.chCpus:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// isDone returns true if done is closed; a nil channel is never closed
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// msmProgress counts the processed chunks of a multiExp, and reports them to MultiExpOptions.Progress
+type msmProgress struct {
+	lock     sync.Mutex
+	done     int
+	callback func(done, total int)
+}
+
+func newMsmProgress(callback func(done, total int)) *msmProgress {
+	if callback == nil {
+		return nil
+	}
+	return &msmProgress{callback: callback}
+}
+
+// chunkDone reports that one more of the total chunks is processed
+func (pr *msmProgress) chunkDone(total int) {
+	if pr == nil {
+		return
+	}
+	pr.lock.Lock()
+	pr.done++
+	pr.callback(pr.done, total)
+	pr.lock.Unlock()
+}
+
+// nbChunks returns the number of c-bit windows holding non-zero digits, at most max
+func (params msmParams) nbChunks(c, max int) int {
+	// partitionScalars may propagate a carry in the window above the last bit
+	n := (params.nbBits+c-1)/c + 1
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// newChunkSelector returns the selector of the c-bit window at index chunk
+func newChunkSelector(chunk, c uint64) selector {
+	mask := uint64((1 << c) - 1) // low c bits are 1
+	jc := uint64(chunk * c)
+	s := selector{}
+	s.index = jc / 64
+	s.shift = jc - (s.index * 64)
+	s.mask = mask << s.shift
+	s.multiWordSelect = (64 %c)!=0   && s.shift > (64-c) && s.index < (fr.Limbs - 1 )
+	if s.multiWordSelect {
+		nbBitsHigh := s.shift - uint64(64-c)
+		s.maskHigh = (1 << nbBitsHigh) - 1
+		s.shiftHigh = (c - nbBitsHigh)
+	}
+	return s
+}
+
+// scalarBits returns the n bits of s starting at bit from, as a regular form fr.Element
+// n must be at most fr.Bits
+func scalarBits(s *fr.Element, from, n int) (res fr.Element) {
+	for j := 0; j*64 < n; j++ {
+		pos := from + j*64
+		w, off := pos/64, uint(pos%64)
+		if w < fr.Limbs {
+			res[j] = s[w] >> off
+		}
+		if off != 0 && w+1 < fr.Limbs {
+			res[j] |= s[w+1] << (64 - off)
+		}
+	}
+	if r := n % 64; r != 0 {
+		res[(n-1)/64] &= (uint64(1) << uint(r)) - 1
+	}
+	return
+}
+
 // partitionScalars  compute, for each scalars over c-bit wide windows, nbChunk digits
 // if the digit is larger than 2^{c-1}, then, we borrow 2^c from the next window and substract
 // 2^{c} to the current digit, making it negative.
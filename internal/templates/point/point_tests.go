@@ -4,15 +4,21 @@ package point
 const PointTests = `
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"math/big"
 	"math/bits"
+	"math/rand"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/consensys/gurvy/{{ toLower .CurveName}}/fp"
 	"github.com/consensys/gurvy/{{ toLower .CurveName}}/fr"
 	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
 )
 
@@ -47,8 +53,8 @@ import (
 		res.ZZZ.Mul(&p.ZZZ, &fff)
 		return res
 	}
-{{- else if eq .CoordType "e2" }}
-	func fuzzJacobian{{ toUpper .PointName}}(p *{{ toUpper .PointName}}Jac, f *e2) {{ toUpper .PointName}}Jac {
+{{- else if eq .CoordType "E2" }}
+	func fuzzJacobian{{ toUpper .PointName}}(p *{{ toUpper .PointName}}Jac, f *E2) {{ toUpper .PointName}}Jac {
 		var res {{ toUpper .PointName}}Jac
 		res.X.Mul(&p.X, f).Mul(&res.X, f)
 		res.Y.Mul(&p.Y, f).Mul(&res.Y, f).Mul(&res.Y, f)
@@ -56,7 +62,7 @@ import (
 		return res
 	}
 
-	func fuzzProjective{{ toUpper .PointName}}(p *{{ toUpper .PointName}}Proj, f *e2) {{ toUpper .PointName}}Proj {
+	func fuzzProjective{{ toUpper .PointName}}(p *{{ toUpper .PointName}}Proj, f *E2) {{ toUpper .PointName}}Proj {
 		var res {{ toUpper .PointName}}Proj
 		res.X.Mul(&p.X, f)
 		res.Y.Mul(&p.Y, f)
@@ -64,7 +70,7 @@ import (
 		return res
 	}
 
-	func fuzzExtendedJacobian{{ toUpper .PointName}}(p *{{ toLower .PointName }}JacExtended, f *e2) {{ toLower .PointName }}JacExtended {
+	func fuzzExtendedJacobian{{ toUpper .PointName}}(p *{{ toLower .PointName }}JacExtended, f *E2) {{ toLower .PointName }}JacExtended {
 		var res {{ toLower .PointName }}JacExtended
 		var ff, fff {{ .CoordType}}
 		ff.Square(f)
@@ -88,21 +94,21 @@ func Test{{ toUpper .PointName}}IsOnCurve(t *testing.T) {
 	properties := gopter.NewProperties(parameters)
 	{{- if eq .CoordType "fp.Element" }}
 		genFuzz1 := GenFp()
-	{{- else if eq .CoordType "e2" }}
+	{{- else if eq .CoordType "E2" }}
 		genFuzz1 := GenE2()
 	{{- end}}
 	properties.Property("[{{ toUpper .CurveName }}] {{ toLower .PointName}}Gen (affine) should be on the curve", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			var op1, op2 {{ toUpper .PointName}}Affine
 			op1.FromJacobian(&{{ toLower .PointName}}Gen)
 			op2.FromJacobian(&{{ toLower .PointName}}Gen)
 			{{- if eq .CoordType "fp.Element" }}
 				op2.Y.Mul(&op2.Y, &a)
-			{{- else if eq .CoordType "e2" }}
+			{{- else if eq .CoordType "E2" }}
 			op2.Y.Mul(&op2.Y, a)
 			{{- end}}
 			return op1.IsOnCurve() && !op2.IsOnCurve()
@@ -113,8 +119,8 @@ func Test{{ toUpper .PointName}}IsOnCurve(t *testing.T) {
 	properties.Property("[{{ toUpper .CurveName }}] {{ toLower .PointName}}Gen (Jacobian) should be on the curve", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			var op1, op2, op3 {{ toUpper .PointName}}Jac
 			op1.Set(&{{ toLower .PointName}}Gen)
@@ -123,7 +129,7 @@ func Test{{ toUpper .PointName}}IsOnCurve(t *testing.T) {
 			op2 = fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName}}Gen, a)
 			{{- if eq .CoordType "fp.Element" }}
 				op3.Y.Mul(&op3.Y, &a)
-			{{- else if eq .CoordType "e2" }}
+			{{- else if eq .CoordType "E2" }}
 				op3.Y.Mul(&op3.Y, a)
 			{{- end}}
 			return op1.IsOnCurve() && op2.IsOnCurve() && !op3.IsOnCurve()
@@ -134,8 +140,8 @@ func Test{{ toUpper .PointName}}IsOnCurve(t *testing.T) {
 	properties.Property("[{{ toUpper .CurveName }}] {{ toLower .PointName}}Gen (projective) should be on the curve", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			var op1, op2, op3 {{ toUpper .PointName}}Proj
 			op1.FromJacobian(&{{ toLower .PointName}}Gen)
@@ -145,7 +151,7 @@ func Test{{ toUpper .PointName}}IsOnCurve(t *testing.T) {
 			op2 = fuzzProjective{{ toUpper .PointName}}(&op1, a)
 			{{- if eq .CoordType "fp.Element" }}
 				op3.Y.Mul(&op3.Y, &a)
-			{{- else if eq .CoordType "e2" }}
+			{{- else if eq .CoordType "E2" }}
 				op3.Y.Mul(&op3.Y, a)
 			{{- end}}
 			return op1.IsOnCurve() && op2.IsOnCurve() && !op3.IsOnCurve()
@@ -165,7 +171,7 @@ func Test{{ toUpper .PointName}}Conversions(t *testing.T) {
 	{{- if eq .CoordType "fp.Element" }}
 		genFuzz1 := GenFp()
 		genFuzz2 := GenFp()
-	{{- else if eq .CoordType "e2" }}
+	{{- else if eq .CoordType "E2" }}
 		genFuzz1 := GenE2()
 		genFuzz2 := GenE2()
 	{{- end}}
@@ -173,8 +179,8 @@ func Test{{ toUpper .PointName}}Conversions(t *testing.T) {
 	properties.Property("[{{ toUpper .CurveName }}] Affine representation should be independent of the Jacobian representative", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			g := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, a)
 			var op1 {{ toUpper .PointName}}Affine
@@ -187,8 +193,8 @@ func Test{{ toUpper .PointName}}Conversions(t *testing.T) {
 	properties.Property("[{{ toUpper .CurveName }}] Affine representation should be independent of a Extended Jacobian representative", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			var g {{ toLower .PointName }}JacExtended
 			g.X.Set(&{{ toLower .PointName }}Gen.X)
@@ -207,8 +213,8 @@ func Test{{ toUpper .PointName}}Conversions(t *testing.T) {
 	properties.Property("[{{ toUpper .CurveName }}] Projective representation should be independent of a Jacobian representative", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 
 			g := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, a)
@@ -227,8 +233,8 @@ func Test{{ toUpper .PointName}}Conversions(t *testing.T) {
 	properties.Property("[{{ toUpper .CurveName }}] Jacobian representation should be the same as the affine representative", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			var g {{ toUpper .PointName}}Jac
 			var op1 {{ toUpper .PointName}}Affine
@@ -286,8 +292,8 @@ func Test{{ toUpper .PointName}}Conversions(t *testing.T) {
 	properties.Property("[{{ toUpper .CurveName }}] [Jacobian] Two representatives of the same class should be equal", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a, b {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a, b *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a, b *E2) bool {
 		{{- end}}
 			op1 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, a)
 			op2 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, b)
@@ -309,7 +315,7 @@ parameters := gopter.DefaultTestParameters()
 	{{- if eq .CoordType "fp.Element" }}
 		genFuzz1 := GenFp()
 		genFuzz2 := GenFp()
-	{{- else if eq .CoordType "e2" }}
+	{{- else if eq .CoordType "E2" }}
 		genFuzz1 := GenE2()
 		genFuzz2 := GenE2()
 	{{- end}}
@@ -319,8 +325,8 @@ parameters := gopter.DefaultTestParameters()
 	properties.Property("[{{ toUpper .CurveName }}] [Jacobian] Add should call double when having adding the same point", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a, b {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a, b *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a, b *E2) bool {
 		{{- end}}
 			fop1 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, a)
 			fop2 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, b)
@@ -336,8 +342,8 @@ parameters := gopter.DefaultTestParameters()
 	properties.Property("[{{ toUpper .CurveName }}] [Jacobian] Adding the opposite of a point to itself should output inf", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a, b {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a, b *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a, b *E2) bool {
 		{{- end}}
 			fop1 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, a)
 			fop2 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, b)
@@ -352,8 +358,8 @@ parameters := gopter.DefaultTestParameters()
 	properties.Property("[{{ toUpper .CurveName }}] [Jacobian] Adding the inf to a point should not modify the point", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			fop1 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, a)
 			fop1.AddAssign(&{{ toLower .PointName }}Infinity)
@@ -368,8 +374,8 @@ parameters := gopter.DefaultTestParameters()
 	properties.Property("[{{ toUpper .CurveName }}] [Jacobian Extended] mAdd (-G) should equal mSub(G)", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			fop1 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, a)
 			var p1,p1Neg {{ toUpper .PointName}}Affine
@@ -391,8 +397,8 @@ parameters := gopter.DefaultTestParameters()
 	properties.Property("[{{ toUpper .CurveName }}] [Jacobian Extended] double (-G) should equal doubleNeg(G)", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			fop1 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, a)
 			var p1,p1Neg {{ toUpper .PointName}}Affine
@@ -414,8 +420,8 @@ parameters := gopter.DefaultTestParameters()
 	properties.Property("[{{ toUpper .CurveName }}] [Jacobian] Addmix the negation to itself should output 0", prop.ForAll(
 		{{- if eq .CoordType "fp.Element" }}
 			func(a {{ .CoordType}}) bool {
-		{{- else if eq .CoordType "e2" }}
-			func(a *e2) bool {
+		{{- else if eq .CoordType "E2" }}
+			func(a *E2) bool {
 		{{- end}}
 			fop1 := fuzzJacobian{{ toUpper .PointName}}(&{{ toLower .PointName }}Gen, a)
 			fop1.Neg(&fop1)
@@ -450,7 +456,7 @@ parameters := gopter.DefaultTestParameters()
 		genScalar,
 	))
 
-	{{ if eq .CoordType "e2" }}
+	{{ if eq .CoordType "E2" }}
 		properties.Property("[{{ toUpper .CurveName }}] psi should map points from E' to itself", prop.ForAll(
 			func() bool {
 				var a {{ toUpper .PointName }}Jac
@@ -499,6 +505,52 @@ parameters := gopter.DefaultTestParameters()
         ))
     {{end}}
 
+	properties.Property("[{{ toUpper .CurveName }}] constant time scalar multiplication should output the same result as ScalarMultiplication", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var r big.Int
+			var sNeg fr.Element
+			var op1, op2, op3, op4 {{ toUpper .PointName}}Jac
+			s.ToBigIntRegular(&r)
+			op1.ScalarMultiplication(&{{ toLower .PointName}}Gen, &r)
+			op2.ScalarMultiplicationCT(&{{ toLower .PointName}}Gen, &s)
+			sNeg.Neg(&s)
+			op3.Neg(&op1)
+			op4.ScalarMultiplicationCT(&{{ toLower .PointName}}Gen, &sNeg)
+			return op1.Equal(&op2) && op3.Equal(&op4)
+
+		},
+		genScalar,
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] constant time scalar multiplication should handle small scalars, r-1 and the point at infinity", prop.ForAll(
+		func() bool {
+
+			var s fr.Element
+			var r big.Int
+			var op1, op2 {{ toUpper .PointName}}Jac
+			for _, v := range []uint64{0, 1, 2, 3, 16, 17} {
+				s.SetUint64(v)
+				r.SetUint64(v)
+				op1.ScalarMultiplication(&{{ toLower .PointName}}Gen, &r)
+				op2.ScalarMultiplicationCT(&{{ toLower .PointName}}Gen, &s)
+				if !op1.Equal(&op2) {
+					return false
+				}
+			}
+			s.SetOne().Neg(&s)
+			op1.Neg(&{{ toLower .PointName}}Gen)
+			op2.ScalarMultiplicationCT(&{{ toLower .PointName}}Gen, &s)
+			if !op1.Equal(&op2) {
+				return false
+			}
+			s.SetUint64(5)
+			op2.ScalarMultiplicationCT(&{{ toLower .PointName}}Infinity, &s)
+			return op2.Z.IsZero()
+
+		},
+	))
+
 	// note : this test is here as we expect to have a different multiExp than the above bucket method
 	// for small number of points
 	properties.Property("[{{ toUpper .CurveName }}] Multi exponentation (<50points) should be consistant with sum of square", prop.ForAll(
@@ -565,6 +617,42 @@ func Test{{ toUpper .PointName}}MultiExp(t *testing.T) {
 	scalar.Mul(&scalar, new(big.Int).SetInt64(2*nbSamples+1))
 	scalar.Div(&scalar, new(big.Int).SetInt64(6))
 
+	properties.Property("[{{ toUpper $.CurveName }}] Multi exponentation with batch affine buckets should handle repeated, opposite and infinity points", prop.ForAll(
+		func(mixer fr.Element) bool {
+
+			// few distinct points, so that buckets receive equal and opposite points
+			const nbPoints = 1000
+			points := make([]{{ toUpper $.PointName}}Affine, nbPoints)
+			scalars := make([]fr.Element, nbPoints)
+			for i := 0; i < nbPoints; i++ {
+				switch i % 4 {
+				case 0:
+					points[i] = samplePoints[0]
+				case 1:
+					points[i].Neg(&samplePoints[0])
+				case 2:
+					points[i] = samplePoints[i%7]
+				case 3:
+					// infinity
+				}
+				scalars[i].SetUint64(uint64(i % 13)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			var result, expected {{ toUpper $.PointName}}Jac
+			opt := NewMultiExpOptions(runtime.NumCPU())
+			scalars = partitionScalars(scalars, 16)
+			opt.lock.Lock()
+			result.msmC16(points, scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
+			opt.lock.Lock()
+			expected.msmC16(points, scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			return result.Equal(&expected)
+		},
+		genScalar,
+	))
+
 	{{range $c :=  .CRange}}
 	
 	{{if gt $c 15}}
@@ -589,7 +677,11 @@ func Test{{ toUpper .PointName}}MultiExp(t *testing.T) {
 			opt := NewMultiExpOptions(runtime.NumCPU())
 			opt.lock.Lock()
 			scalars := partitionScalars(sampleScalars[:], {{$c}})
-			result.msmC{{$c}}(samplePoints[:], scalars, opt)
+			result.msmC{{$c}}(samplePoints[:], scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+
+			var resultBatchAffine {{ toUpper $.PointName}}Jac
+			opt.lock.Lock()
+			resultBatchAffine.msmC{{$c}}(samplePoints[:], scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
 	
 	
 			// compute expected result with double and add
@@ -597,7 +689,7 @@ func Test{{ toUpper .PointName}}MultiExp(t *testing.T) {
 			finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
 			expected.ScalarMultiplication(&{{ toLower $.PointName }}Gen, &finalScalar)
 	
-			return result.Equal(&expected)
+			return result.Equal(&expected) && resultBatchAffine.Equal(&expected)
 		},
 		genScalar,
 	))
@@ -610,6 +702,539 @@ func Test{{ toUpper .PointName}}MultiExp(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func Test{{ toUpper .PointName}}MultiExpSmallScalars(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 5
+
+	properties := gopter.NewProperties(parameters)
+
+	// enough points for the batch affine sums of msmTernary
+	const nbSamples = batchAffineMinPoints + 100
+
+	samplePoints := make([]{{ toUpper .PointName}}Affine, nbSamples)
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 0; i < nbSamples; i++ {
+		// repeated points, so that the accumulators see doublings and opposite points
+		if i%100 == 0 {
+			g.Set(&{{ toLower .PointName}}Gen)
+		}
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+	}
+
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne).FromMont()
+
+	properties.Property("[{{ toUpper .CurveName }}] Multi exponentation with scalars in {-1, 0, 1} should output the sum of the points", prop.ForAll(
+		func(seed int64, signed bool) bool {
+			rnd := rand.New(rand.NewSource(seed))
+			scalars := make([]fr.Element, nbSamples)
+			var expected {{ toUpper .PointName}}Jac
+			expected.Set(&{{ toLower .PointName}}Infinity)
+			for i := 0; i < nbSamples; i++ {
+				var q {{ toUpper .PointName}}Jac
+				q.FromAffine(&samplePoints[i])
+				switch rnd.Intn(3) {
+				case 1:
+					scalars[i].SetOne().FromMont()
+					expected.AddAssign(&q)
+				case 2:
+					if signed {
+						scalars[i] = minusOne
+						expected.SubAssign(&q)
+					}
+				}
+			}
+			if nbBits, ternary := scalarsBitLen(scalars); !ternary || (!signed && nbBits > 1) {
+				return false
+			}
+
+			var result, resultSmall {{ toUpper .PointName}}Jac
+			result.MultiExp(samplePoints, scalars)
+			resultSmall.MultiExp(samplePoints[:1000], scalars[:1000])
+
+			expectedSmall := msmNaive{{ toUpper .PointName}}(samplePoints[:1000], scalars[:1000])
+			return result.Equal(&expected) && resultSmall.Equal(&expectedSmall)
+		},
+		gen.Int64(),
+		gen.Bool(),
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] Multi exponentation with 32-bit scalars should output the naive multi exponentiation", prop.ForAll(
+		func(seed int64) bool {
+			const nbPoints = 200
+			rnd := rand.New(rand.NewSource(seed))
+			scalars := make([]fr.Element, nbPoints)
+			for i := 0; i < nbPoints; i++ {
+				scalars[i].SetUint64(uint64(rnd.Uint32())).FromMont()
+			}
+			if nbBits, ternary := scalarsBitLen(scalars); ternary || nbBits > 32 {
+				return false
+			}
+
+			var result {{ toUpper .PointName}}Jac
+			result.MultiExp(samplePoints[:nbPoints], scalars)
+			expected := msmNaive{{ toUpper .PointName}}(samplePoints[:nbPoints], scalars)
+			return result.Equal(&expected)
+		},
+		gen.Int64(),
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] scalarsBitLen should not consider -1 as a small scalar with larger scalars", prop.ForAll(
+		func(a uint64) bool {
+			scalars := []fr.Element{minusOne, {}}
+			scalars[1].SetUint64(a | 2).FromMont()
+			nbBits, ternary := scalarsBitLen(scalars)
+			return !ternary && nbBits == fr.Bits
+		},
+		gen.UInt64(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func Test{{ toUpper .PointName}}MultiExpBestC(t *testing.T) {
+	// small scalars need less windows, which makes larger windows cheaper
+	const nbPoints = 1 << 16
+	small, large := msmBestC{{ toUpper .PointName}}(nbPoints, 8), msmBestC{{ toUpper .PointName}}(nbPoints, fr.Bits)
+	if small >= large {
+		t.Fatalf("8-bit scalars should use smaller windows than %d-bit scalars, got c=%d and c=%d", fr.Bits, small, large)
+	}
+	if c := msmBestC{{ toUpper .PointName}}(1<<24, fr.Bits); c < large {
+		t.Fatal("more points should not use smaller windows")
+	}
+}
+
+// msmNaive{{ toUpper .PointName}} returns Σ scalars[i]·points[i] with one scalar multiplication per point
+func msmNaive{{ toUpper .PointName}}(points []{{ toUpper .PointName}}Affine, scalars []fr.Element) {{ toUpper .PointName}}Jac {
+	var res, q {{ toUpper .PointName}}Jac
+	res.Set(&{{ toLower .PointName}}Infinity)
+	for i := 0; i < len(scalars); i++ {
+		var s big.Int
+		scalars[i].ToBigInt(&s)
+		q.ScalarMultiplication(new({{ toUpper .PointName}}Jac).FromAffine(&points[i]), &s)
+		res.AddAssign(&q)
+	}
+	return res
+}
+
+func Test{{ toUpper .PointName}}MultiExpBatchAffineSkewed(t *testing.T) {
+	// most of the points target the same few buckets: the conflicts must neither be requeued
+	// indefinitely nor make the batch affine accumulation slower than the jacobian one
+	const nbPoints = batchAffineMinPoints
+	points := make([]{{ toUpper .PointName}}Affine, nbPoints)
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 0; i < nbPoints; i++ {
+		points[i].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+	}
+
+	var mixer fr.Element
+	mixer.SetRandom()
+	constant := make([]fr.Element, nbPoints)
+	repeated := make([]fr.Element, nbPoints)
+	for i := 0; i < nbPoints; i++ {
+		constant[i] = mixer
+		repeated[i].SetUint64(uint64(i%3 + 1)).Mul(&repeated[i], &mixer)
+	}
+
+	for name, scalars := range map[string][]fr.Element{"constant": constant, "repeated": repeated} {
+		for i := 0; i < nbPoints; i++ {
+			scalars[i].FromMont()
+		}
+		scalars = partitionScalars(scalars, 16)
+		opt := NewMultiExpOptions(runtime.NumCPU())
+
+		var result, expected {{ toUpper .PointName}}Jac
+		start := time.Now()
+		opt.lock.Lock()
+		expected.msmC16(points, scalars, opt, msmParams{nbBits: fr.Limbs * 64})
+		tJac := time.Since(start)
+
+		start = time.Now()
+		opt.lock.Lock()
+		result.msmC16(points, scalars, opt, msmParams{batchAffine: true, nbBits: fr.Limbs * 64})
+		tBatchAffine := time.Since(start)
+
+		if !result.Equal(&expected) {
+			t.Fatalf("%s scalars: batch affine and jacobian multi exponentiations differ", name)
+		}
+		if tBatchAffine > 10*tJac {
+			t.Fatalf("%s scalars: batch affine multi exponentiation took %s, jacobian %s", name, tBatchAffine, tJac)
+		}
+	}
+}
+
+func TestMultiExpMany{{ toUpper .PointName}}(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 5
+
+	properties := gopter.NewProperties(parameters)
+
+	const nbSamples = 500
+
+	samplePoints := make([]{{ toUpper .PointName}}Affine, nbSamples)
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+	}
+
+	properties.Property("[{{ toUpper .CurveName }}] MultiExpMany should output the same results as MultiExp on each vector", prop.ForAll(
+		func(seed int64) bool {
+			rnd := rand.New(rand.NewSource(seed))
+			scalars := make([][]fr.Element, 4)
+			for v := 0; v < len(scalars); v++ {
+				scalars[v] = make([]fr.Element, nbSamples)
+				for i := 0; i < nbSamples; i++ {
+					switch v {
+					case 0:
+						scalars[v][i].SetRandom().FromMont()
+					case 1:
+						// small scalars
+						scalars[v][i].SetUint64(uint64(rnd.Uint32())).FromMont()
+					case 2:
+						// zeros
+					case 3:
+						scalars[v][i].SetUint64(uint64(i)).Neg(&scalars[v][i]).FromMont()
+					}
+				}
+			}
+
+			results, err := MultiExpMany{{ toUpper .PointName}}(samplePoints, scalars, NewMultiExpOptions(runtime.NumCPU()))
+			if err != nil || len(results) != len(scalars) {
+				return false
+			}
+			for v := 0; v < len(scalars); v++ {
+				var expected {{ toUpper .PointName}}Jac
+				expected.MultiExp(samplePoints, scalars[v])
+				if !results[v].Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		gen.Int64(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	if results, err := MultiExpMany{{ toUpper .PointName}}(samplePoints, nil); err != nil || len(results) != 0 {
+		t.Fatal("MultiExpMany with no vector should output no result")
+	}
+
+	// ternary vectors with -1
+	var minusOne, one fr.Element
+	minusOne.SetOne().Neg(&minusOne).FromMont()
+	one.SetOne().FromMont()
+	ternary := [][]fr.Element{
+		[]fr.Element{minusOne, one, one},
+		[]fr.Element{one, minusOne, minusOne},
+		[]fr.Element{one, one, one},
+	}
+	results, err := MultiExpMany{{ toUpper .PointName}}(samplePoints[:3], ternary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 0; v < len(ternary); v++ {
+		var expected {{ toUpper .PointName}}Jac
+		expected.MultiExp(samplePoints[:3], ternary[v])
+		if !results[v].Equal(&expected) {
+			t.Fatal("MultiExpMany should output the same results as MultiExp on scalars in {-1, 0, 1}")
+		}
+	}
+
+	scalars := [][]fr.Element{make([]fr.Element, nbSamples), make([]fr.Element, nbSamples-1)}
+	if _, err := MultiExpMany{{ toUpper .PointName}}(samplePoints, scalars); err == nil {
+		t.Fatal("MultiExpMany should fail when a vector doesn't have one scalar per point")
+	}
+}
+
+func Test{{ toUpper .PointName}}MultiExpReaderAt(t *testing.T) {
+
+	const nbSamples = 500
+
+	samplePoints := make([]{{ toUpper .PointName}}Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 0; i < nbSamples; i++ {
+		if i%50 != 7 {
+			// and a few points at infinity
+			samplePoints[i].FromJacobian(&g)
+		}
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+		sampleScalars[i].SetRandom().FromMont()
+	}
+
+	var expected {{ toUpper .PointName}}Jac
+	expected.MultiExp(samplePoints, sampleScalars)
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, RawEncoding())
+	if err := enc.Encode(samplePoints); err != nil {
+		t.Fatal(err)
+	}
+	// skip the length prefix of the slice
+	r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 4, int64(buf.Len()-4))
+
+	const memoryPerPoint = 3*SizeOf{{ toUpper .PointName}}AffineUncompressed + SizeOfFr
+	for _, segmentSize := range []int{1, 77, 250, nbSamples, 2 * nbSamples} {
+		var result {{ toUpper .PointName}}Jac
+		if _, err := result.MultiExpReaderAt(context.Background(), r, sampleScalars, segmentSize*memoryPerPoint); err != nil {
+			t.Fatal(err)
+		}
+		if !result.Equal(&expected) {
+			t.Fatalf("MultiExpReaderAt with segments of %d points and MultiExp results differ", segmentSize)
+		}
+	}
+
+	var result {{ toUpper .PointName}}Jac
+	if _, err := result.MultiExpReaderAt(context.Background(), r, sampleScalars, memoryPerPoint-1); err == nil {
+		t.Fatal("MultiExpReaderAt should fail when a point doesn't fit in the memory budget")
+	}
+
+	truncated := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 4, int64(buf.Len()-5))
+	if _, err := result.MultiExpReaderAt(context.Background(), truncated, sampleScalars, 77*memoryPerPoint); err == nil {
+		t.Fatal("MultiExpReaderAt should fail on a truncated input")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := result.MultiExpReaderAt(ctx, r, sampleScalars, 77*memoryPerPoint); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func Test{{ toUpper .PointName}}MultiExpContext(t *testing.T) {
+
+	const nbSamples = 500
+
+	var samplePoints [nbSamples]{{ toUpper .PointName}}Affine
+	var sampleScalars [nbSamples]fr.Element
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 1; i <= nbSamples; i++ {
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			MulAssign(&mixer).
+			FromMont()
+	}
+
+	var expected {{ toUpper .PointName}}Jac
+	expected.MultiExp(samplePoints[:], sampleScalars[:])
+
+	// progress is reported once per chunk, and the result matches MultiExp
+	var result {{ toUpper .PointName}}Jac
+	var nbCalls, lastDone, lastTotal int
+	opt := NewMultiExpOptions(runtime.NumCPU())
+	opt.Progress = func(done, total int) {
+		nbCalls++
+		lastDone, lastTotal = done, total
+	}
+	if _, err := result.MultiExpContext(context.Background(), samplePoints[:], sampleScalars[:], opt); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(&expected) {
+		t.Fatal("MultiExpContext and MultiExp results differ")
+	}
+	if nbCalls == 0 || nbCalls != lastDone || lastDone != lastTotal {
+		t.Fatalf("unexpected progress: %d calls, last reported %d/%d", nbCalls, lastDone, lastTotal)
+	}
+
+	// mismatched lengths
+	if _, err := result.MultiExpContext(context.Background(), samplePoints[:], sampleScalars[:nbSamples-1]); err == nil {
+		t.Fatal("MultiExpContext should fail on mismatched lengths")
+	}
+
+	// cancelled before the start
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result.Set(&{{ toLower .PointName}}Gen)
+	if _, err := result.MultiExpContext(ctx, samplePoints[:], sampleScalars[:]); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !result.Equal(&{{ toLower .PointName}}Gen) {
+		t.Fatal("a cancelled MultiExpContext should not modify its receiver")
+	}
+
+	// cancelled after the first chunk, with chunks processed one at a time
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	opt = NewMultiExpOptions(1)
+	opt.Progress = func(done, total int) {
+		cancel()
+	}
+	if _, err := result.MultiExpContext(ctx, samplePoints[:], sampleScalars[:], opt); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// the semaphore is released by cancelled multi exponentiations
+	opt.Progress = nil
+	if _, err := result.MultiExpContext(context.Background(), samplePoints[:], sampleScalars[:], opt); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(&expected) {
+		t.Fatal("MultiExpContext and MultiExp results differ")
+	}
+
+	// cancelled while the tokens of the shared options are held by another call
+	var ternaryScalars [nbSamples]fr.Element
+	for i := 0; i < nbSamples; i += 2 {
+		ternaryScalars[i].SetOne().FromMont()
+	}
+	for _, scalars := range [][]fr.Element{sampleScalars[:], ternaryScalars[:]} {
+		<-opt.chCpus
+		ctx, cancel := context.WithCancel(context.Background())
+		chErr := make(chan error, 1)
+		go func(scalars []fr.Element) {
+			var r {{ toUpper .PointName}}Jac
+			_, err := r.MultiExpContext(ctx, samplePoints[:], scalars, opt)
+			chErr <- err
+		}(scalars)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		select {
+		case err := <-chErr:
+			if err != context.Canceled {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("a cancelled MultiExpContext should not wait for the cpu tokens")
+		}
+		opt.chCpus <- struct{}{}
+	}
+}
+
+func Test{{ toUpper .PointName}}PrecomputedBases(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	const nbSamples = 50
+
+	var samplePoints [nbSamples]{{ toUpper .PointName}}Affine
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 1; i <= nbSamples; i++ {
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+	}
+
+	properties.Property("[{{ toUpper .CurveName }}] MultiExpPrecomputed should output the same result as MultiExp", prop.ForAll(
+		func(mixer fr.Element) bool {
+			var sampleScalars [nbSamples]fr.Element
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			var expected {{ toUpper .PointName}}Jac
+			expected.MultiExp(samplePoints[:], sampleScalars[:])
+
+			for _, factor := range []int{1, 2, 4, 7} {
+				bases, err := NewPrecomputedBases{{ toUpper .PointName}}(samplePoints[:], factor)
+				if err != nil {
+					return false
+				}
+				var result {{ toUpper .PointName}}Jac
+				if _, err := result.MultiExpPrecomputed(bases, sampleScalars[:]); err != nil || !result.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] PrecomputedBases should be unchanged by WriteTo (or WriteRawTo) then ReadFrom", prop.ForAll(
+		func(mixer fr.Element, raw bool) bool {
+			var sampleScalars [nbSamples]fr.Element
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			const factor = 3
+			bases, err := NewPrecomputedBases{{ toUpper .PointName}}(samplePoints[:], factor)
+			if err != nil {
+				return false
+			}
+			var buf bytes.Buffer
+			var written int64
+			pointSize := SizeOf{{ toUpper .PointName}}AffineCompressed
+			if raw {
+				written, err = bases.WriteRawTo(&buf)
+				pointSize = SizeOf{{ toUpper .PointName}}AffineUncompressed
+			} else {
+				written, err = bases.WriteTo(&buf)
+			}
+			// factor, slice length and points
+			if err != nil || written != int64(buf.Len()) || written != int64(4+4+factor*nbSamples*pointSize) {
+				return false
+			}
+			var decoded PrecomputedBases{{ toUpper .PointName}}
+			read, err := decoded.ReadFrom(&buf)
+			if err != nil || read != written || decoded.Factor() != factor || decoded.Len() != nbSamples {
+				return false
+			}
+
+			var result, expected {{ toUpper .PointName}}Jac
+			if _, err := result.MultiExpPrecomputed(&decoded, sampleScalars[:]); err != nil {
+				return false
+			}
+			if _, err := expected.MultiExpPrecomputed(bases, sampleScalars[:]); err != nil {
+				return false
+			}
+			return result.Equal(&expected)
+		},
+		genScalar,
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	if _, err := NewPrecomputedBases{{ toUpper .PointName}}(samplePoints[:], 0); err == nil {
+		t.Fatal("NewPrecomputedBases should reject a factor of 0")
+	}
+	var invalid bytes.Buffer
+	invalid.Write([]byte{0, 0, 0, 0})
+	var decoded PrecomputedBases{{ toUpper .PointName}}
+	if _, err := decoded.ReadFrom(&invalid); err == nil {
+		t.Fatal("ReadFrom should reject a factor of 0")
+	}
+
+	bases, err := NewPrecomputedBases{{ toUpper .PointName}}(samplePoints[:], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result {{ toUpper .PointName}}Jac
+	result.Set(&{{ toLower .PointName}}Gen)
+	if _, err := result.MultiExpPrecomputed(bases, make([]fr.Element, nbSamples-1)); err == nil {
+		t.Fatal("MultiExpPrecomputed should fail when there isn't one scalar per base")
+	}
+	if _, err := result.MultiExpPrecomputed(&PrecomputedBases{{ toUpper .PointName}}{}, nil); err == nil {
+		t.Fatal("MultiExpPrecomputed should reject uninitialized bases")
+	}
+	if !result.Equal(&{{ toLower .PointName}}Gen) {
+		t.Fatal("a failed MultiExpPrecomputed should not modify its receiver")
+	}
+}
+
 {{if .CofactorCleaning }}
 func Test{{ toUpper .PointName}}CofactorCleaning(t *testing.T) {
 
@@ -620,42 +1245,79 @@ func Test{{ toUpper .PointName}}CofactorCleaning(t *testing.T) {
 
 	properties.Property("[{{ toUpper .CurveName }}] Clearing the cofactor of a random point should set it in the r-torsion", prop.ForAll(
 		func() bool {
-			var a, x, b {{ .CoordType }}
-			a.SetRandom()
-			{{if eq .CoordType "fp.Element" }}
-				{{if eq .PointName "g2" }}
-					x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
-				{{else}}
-					x.Square(&a).Mul(&x, &a).Add(&x, &bCurveCoeff)
-				{{end}}	
-				for x.Legendre() != 1 {
-					a.SetRandom()
-					{{if eq .PointName "g2" }}
-						x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
-					{{else}}
-						x.Square(&a).Mul(&x, &a).Add(&x, &bCurveCoeff)
-					{{end}}
-				}
-			{{else if eq .CoordType "e2" }}
-				x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
-				for x.Legendre() != 1 {
-					a.SetRandom()
-					x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
-				}
-			{{end}}
-			b.Sqrt(&x)
-			var point, pointCleared, infinity {{ toUpper .PointName}}Jac
-			point.X.Set(&a)
-			point.Y.Set(&b)
-			point.Z.SetOne()
+			var pointCleared, infinity {{ toUpper .PointName}}Jac
+			point := randomOnCurve{{ toUpper .PointName}}()
 			pointCleared.ClearCofactor(&point)
 			infinity.Set(&{{ toLower .PointName}}Infinity)
-			return point.IsOnCurve() && pointCleared.IsInSubGroup() && !pointCleared.Equal(&infinity)
+			return point.IsOnCurve() && !point.IsInSubGroup() && pointCleared.IsInSubGroup() && !pointCleared.Equal(&infinity) && infinity.IsInSubGroup()
 		},
 	))
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
 }
+
+func Test{{ toUpper .PointName}}IsInSubGroup(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	// inSubGroup is the definition of the membership test
+	inSubGroup := func(p *{{ toUpper .PointName}}Jac) bool {
+		var rp {{ toUpper .PointName}}Jac
+		rp.mulWindowed(p, fr.Modulus())
+		return rp.Z.IsZero()
+	}
+
+	properties.Property("[{{ toUpper .CurveName }}] IsInSubGroup should match [r]p == 0 on points with a non trivial cofactor component", prop.ForAll(
+		func() bool {
+			// t = [r]p is a non zero point of the cofactor torsion, q is in the r-torsion
+			var q, t, qt {{ toUpper .PointName}}Jac
+			p := randomOnCurve{{ toUpper .PointName}}()
+			q.ClearCofactor(&p)
+			t.mulWindowed(&p, fr.Modulus())
+			qt.Set(&q).AddAssign(&t)
+			if t.Z.IsZero() {
+				return false
+			}
+			for _, a := range []*{{ toUpper .PointName}}Jac{&p, &q, &t, &qt} {
+				if a.IsInSubGroup() != inSubGroup(a) {
+					return false
+				}
+			}
+			return q.IsInSubGroup() && !t.IsInSubGroup() && !qt.IsInSubGroup()
+		},
+	))
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+}
+
+// randomOnCurve{{ toUpper .PointName}} returns a random point on the curve, which is not in the r-torsion
+// with overwhelming probability
+func randomOnCurve{{ toUpper .PointName}}() {{ toUpper .PointName}}Jac {
+	var a, x, b {{ .CoordType }}
+	a.SetRandom()
+	{{- if eq .PointName "g2" }}
+		x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
+	{{- else}}
+		x.Square(&a).Mul(&x, &a).Add(&x, &bCurveCoeff)
+	{{- end}}
+	for x.Legendre() != 1 {
+		a.SetRandom()
+		{{- if eq .PointName "g2" }}
+			x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
+		{{- else}}
+			x.Square(&a).Mul(&x, &a).Add(&x, &bCurveCoeff)
+		{{- end}}
+	}
+	b.Sqrt(&x)
+	var point {{ toUpper .PointName}}Jac
+	point.X.Set(&a)
+	point.Y.Set(&b)
+	point.Z.SetOne()
+	return point
+}
 {{end}}
 
 func Test{{ toUpper .PointName}}BatchScalarMultiplication(t *testing.T) {
@@ -705,6 +1367,134 @@ func Test{{ toUpper .PointName}}BatchScalarMultiplication(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func Test{{ toUpper .PointName}}FixedBaseTable(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	const nbSamples = 100
+
+	properties.Property("[{{ toUpper .CurveName }}] FixedBaseTable.Mul should output the same result as ScalarMultiplication", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var r big.Int
+			var expected {{ toUpper .PointName}}Jac
+			s.ToBigIntRegular(&r)
+			expected.ScalarMultiplication(&{{ toLower .PointName}}Gen, &r)
+			res := {{ toUpper .PointName}}GeneratorTable().Mul(&r)
+
+			var a {{ toUpper .PointName}}Affine
+			a.Neg(&{{ toLower .PointName}}GenAff)
+			table := NewFixedBaseTable{{ toUpper .PointName}}(&a)
+			res2 := table.Mul(&r)
+			res2.Neg(&res2)
+
+			return res.Equal(&expected) && res2.Equal(&expected)
+		},
+		genScalar,
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] FixedBaseTable.MulBatch should be consistant with BatchScalarMultiplication", prop.ForAll(
+		func(mixer fr.Element) bool {
+			// mixer ensures that all the words of a fpElement are set
+			var sampleScalars [nbSamples]fr.Element
+
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+			sampleScalars[0].SetZero()
+
+			result := {{ toUpper .PointName}}GeneratorTable().MulBatch(sampleScalars[:])
+			expected := BatchScalarMultiplication{{ toUpper .PointName}}(&{{ toLower .PointName}}GenAff, sampleScalars[:])
+
+			if len(result) != len(expected) {
+				return false
+			}
+			for i := 0; i < len(result); i++ {
+				if !result[i].Equal(&expected[i]) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.Property("[{{ toUpper .CurveName }}] FixedBaseTable of infinity should output infinity", prop.ForAll(
+		func(s fr.Element) bool {
+			var r big.Int
+			var inf {{ toUpper .PointName}}Affine
+			s.ToBigIntRegular(&r)
+			res := NewFixedBaseTable{{ toUpper .PointName}}(&inf).Mul(&r)
+			return res.Z.IsZero()
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// the top window of r-1 leaves room for a carry, which partitionScalars would drop
+	var rMinusOne big.Int
+	rMinusOne.Sub(fr.Modulus(), big.NewInt(1))
+	if top := new(big.Int).Rsh(&rMinusOne, fr.Limbs*64-fixedBaseWindow); top.Int64() >= (1<<(fixedBaseWindow-1))-1 {
+		t.Fatal("the top window of the fixed base tables may carry")
+	}
+
+	// the largest scalars, r-1 and r-2
+	for _, k := range []int64{1, 2} {
+		var s big.Int
+		s.Sub(fr.Modulus(), big.NewInt(k))
+		var expected {{ toUpper .PointName}}Jac
+		expected.ScalarMultiplication(&{{ toLower .PointName}}Gen, &s)
+		res := {{ toUpper .PointName}}GeneratorTable().Mul(&s)
+		if !res.Equal(&expected) {
+			t.Fatalf("FixedBaseTable.Mul(r-%d) should output the same result as ScalarMultiplication", k)
+		}
+
+		var sFr fr.Element
+		sFr.SetBigInt(&s).FromMont()
+		batch := {{ toUpper .PointName}}GeneratorTable().MulBatch([]fr.Element{sFr})
+		var expectedAff {{ toUpper .PointName}}Affine
+		expectedAff.FromJacobian(&expected)
+		if !batch[0].Equal(&expectedAff) {
+			t.Fatalf("FixedBaseTable.MulBatch(r-%d) should output the same result as ScalarMultiplication", k)
+		}
+	}
+}
+
+func TestRandom{{ toUpper .PointName}}(t *testing.T) {
+
+	r1 := rand.New(rand.NewSource(42))
+	r2 := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 10; i++ {
+		p1, err := Random{{ toUpper .PointName}}(r1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2, err := Random{{ toUpper .PointName}}(r2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p1.Equal(&p2) {
+			t.Fatal("Random{{ toUpper .PointName}} should be deterministic for a given source")
+		}
+		if p1.IsInfinity() || !p1.IsInSubGroup() {
+			t.Fatal("Random{{ toUpper .PointName}} should output a point in the r-torsion")
+		}
+	}
+
+	if _, err := Random{{ toUpper .PointName}}(bytes.NewReader(nil)); err != io.EOF {
+		t.Fatal("Random{{ toUpper .PointName}} should forward the reader error, got", err)
+	}
+}
+
 // ------------------------------------------------------------
 // benches
 
@@ -736,6 +1526,48 @@ func Benchmark{{ toUpper .PointName}}BatchScalarMul(b *testing.B) {
 	}
 }
 
+func Benchmark{{ toUpper .PointName}}FixedBaseTable(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 10
+
+	var sampleScalars [nbSamples]fr.Element
+
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+	}
+
+	var scalar big.Int
+	sampleScalars[nbSamples-1].ToBigInt(&scalar)
+
+	b.Run("build", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			_ = NewFixedBaseTable{{ toUpper .PointName}}(&{{ toLower .PointName}}GenAff)
+		}
+	})
+
+	table := {{ toUpper .PointName}}GeneratorTable()
+
+	b.Run("Mul", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			_ = table.Mul(&scalar)
+		}
+	})
+
+	b.Run(fmt.Sprintf("MulBatch %d points", nbSamples), func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			_ = table.MulBatch(sampleScalars[:])
+		}
+	})
+}
+
 func Benchmark{{ toUpper .PointName}}ScalarMul(b *testing.B) {
 
 	var scalar big.Int
@@ -762,6 +1594,16 @@ func Benchmark{{ toUpper .PointName}}ScalarMul(b *testing.B) {
 	})
     {{end}}
 
+	var ct {{ toUpper .PointName}}Jac
+	var scalarFr fr.Element
+	scalarFr.SetBigInt(&scalar)
+	b.Run("constant time", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			ct.ScalarMultiplicationCT(&{{ toLower .PointName}}Gen, &scalarFr)
+		}
+	})
+
 }
 
 
@@ -775,6 +1617,15 @@ func Benchmark{{ toUpper .PointName }}CofactorClearing(b *testing.B) {
 }
 {{end}}
 
+func Benchmark{{ toUpper .PointName }}IsInSubGroup(b *testing.B) {
+	var a {{ toUpper .PointName }}Jac
+	a.Set(&{{ toLower .PointName }}Gen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.IsInSubGroup()
+	}
+}
+
 func Benchmark{{ toUpper .PointName}}Add(b *testing.B) {
 	var a {{ toUpper .PointName}}Jac
 	a.Double(&{{ toLower .PointName}}Gen)
@@ -852,4 +1703,211 @@ func Benchmark{{ toUpper .PointName}}MultiExp{{ toUpper .PointName}}(b *testing.
 	}
 }
 
+
+func Benchmark{{ toUpper .PointName}}MultiExpBatchAffine(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const pow = 16
+	const nbSamples = 1 << pow
+
+	samplePoints := make([]{{ toUpper .PointName}}Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+
+	// distinct points, as batch affine additions are slow for doublings
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+	}
+
+	var testPoint {{ toUpper .PointName}}Jac
+
+	for i := 12; i <= pow; i += 2 {
+		using := 1 << i
+
+		b.Run(fmt.Sprintf("%d points extended Jacobian", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using])
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d points batch affine", using), func(b *testing.B) {
+			opt := NewMultiExpOptions(runtime.NumCPU())
+			opt.BatchAffine = true
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using], opt)
+			}
+		})
+	}
+}
+
+func Benchmark{{ toUpper .PointName}}MultiExpPrecomputed(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 14
+
+	samplePoints := make([]{{ toUpper .PointName}}Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+	}
+
+	var testPoint {{ toUpper .PointName}}Jac
+
+	b.Run("no precomputation", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			testPoint.MultiExp(samplePoints, sampleScalars)
+		}
+	})
+
+	for _, factor := range []int{2, 4, 8} {
+		bases, _ := NewPrecomputedBases{{ toUpper .PointName}}(samplePoints, factor)
+		b.Run(fmt.Sprintf("factor %d", factor), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpPrecomputed(bases, sampleScalars)
+			}
+		})
+	}
+}
+
+func Benchmark{{ toUpper .PointName}}MultiExpSmallScalars(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]{{ toUpper .PointName}}Affine, nbSamples)
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+	}
+
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne).FromMont()
+
+	rnd := rand.New(rand.NewSource(0))
+	for _, nbBits := range []int{1, 0, 32, 64, fr.Bits} {
+		scalars := make([]fr.Element, nbSamples)
+		name := fmt.Sprintf("%d bits", nbBits)
+		for i := 0; i < nbSamples; i++ {
+			switch nbBits {
+			case 0:
+				// {-1, 0, 1}
+				name = "ternary"
+				if r := rnd.Intn(3); r == 2 {
+					scalars[i] = minusOne
+				} else {
+					scalars[i].SetUint64(uint64(r)).FromMont()
+				}
+			case fr.Bits:
+				scalars[i].SetRandom().FromMont()
+			default:
+				scalars[i].SetUint64(rnd.Uint64() >> (64 - nbBits)).FromMont()
+			}
+		}
+
+		var testPoint {{ toUpper .PointName}}Jac
+		b.Run(name, func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints, scalars)
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpMany{{ toUpper .PointName}}(b *testing.B) {
+	const nbSamples = 1 << 16
+	const nbVectors = 4
+
+	samplePoints := make([]{{ toUpper .PointName}}Affine, nbSamples)
+	scalars := make([][]fr.Element, nbVectors)
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+	}
+	for v := 0; v < nbVectors; v++ {
+		scalars[v] = make([]fr.Element, nbSamples)
+		for i := 0; i < nbSamples; i++ {
+			scalars[v][i].SetRandom().FromMont()
+		}
+	}
+
+	b.Run(fmt.Sprintf("%d x MultiExp", nbVectors), func(b *testing.B) {
+		var testPoint {{ toUpper .PointName}}Jac
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			for v := 0; v < nbVectors; v++ {
+				testPoint.MultiExp(samplePoints, scalars[v])
+			}
+		}
+	})
+
+	b.Run("MultiExpMany", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			MultiExpMany{{ toUpper .PointName}}(samplePoints, scalars)
+		}
+	})
+}
+
+func Benchmark{{ toUpper .PointName}}MultiExpReaderAt(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]{{ toUpper .PointName}}Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	var g {{ toUpper .PointName}}Jac
+	g.Set(&{{ toLower .PointName}}Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&{{ toLower .PointName}}Gen)
+		sampleScalars[i].SetRandom().FromMont()
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, RawEncoding())
+	if err := enc.Encode(samplePoints); err != nil {
+		b.Fatal(err)
+	}
+	r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 4, int64(buf.Len()-4))
+
+	var testPoint {{ toUpper .PointName}}Jac
+	b.Run("in memory", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			testPoint.MultiExp(samplePoints, sampleScalars)
+		}
+	})
+
+	const memoryPerPoint = 3*SizeOf{{ toUpper .PointName}}AffineUncompressed + SizeOfFr
+	for _, nbSegments := range []int{4, 16} {
+		b.Run(fmt.Sprintf("%d segments", nbSegments), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpReaderAt(context.Background(), r, sampleScalars, nbSamples/nbSegments*memoryPerPoint)
+			}
+		})
+	}
+}
+
 `
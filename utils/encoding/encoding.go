@@ -19,11 +19,17 @@ import (
 	"errors"
 )
 
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#section-5
+// ExpandMsgXmd expands msg to a slice of lenInBytes bytes, using SHA-256 and the domain separation tag dst
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.3.1
 // https://tools.ietf.org/html/rfc8017#section-4.1 (I2OSP/O2ISP)
-func expandMsgXmd(msg, dst []byte, lenInBytes int) ([]byte, error) {
+//
+// lenInBytes must be in [0, 255*32]
+func ExpandMsgXmd(msg, dst []byte, lenInBytes int) ([]byte, error) {
 
 	h := sha256.New()
+	if lenInBytes < 0 || lenInBytes > 65535 {
+		return nil, errors.New("Invalid lenInBytes")
+	}
 	ell := (lenInBytes + h.Size() - 1) / h.Size() // ceil(len_in_bytes / b_in_bytes)
 	if ell > 255 {
 		return nil, errors.New("Invalid lenInBytes")
@@ -35,7 +41,7 @@ func expandMsgXmd(msg, dst []byte, lenInBytes int) ([]byte, error) {
 
 	// Z_pad = I2OSP(0, r_in_bytes)
 	// l_i_b_str = I2OSP(len_in_bytes, 2)
-	// DST_prime = I2OSP(len(DST), 1) || DST
+	// DST_prime = DST || I2OSP(len(DST), 1)
 	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
 	h.Reset()
 	h.Write(make([]byte, h.BlockSize()))
@@ -53,8 +59,9 @@ func expandMsgXmd(msg, dst []byte, lenInBytes int) ([]byte, error) {
 	h.Write([]byte{sizeDomain})
 	b1 := h.Sum(nil)
 
+	// the last block is truncated if lenInBytes is not a multiple of h.Size()
 	res := make([]byte, lenInBytes)
-	copy(res[:h.Size()], b1)
+	copy(res, b1)
 
 	for i := 2; i <= ell; i++ {
 		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
@@ -68,7 +75,7 @@ func expandMsgXmd(msg, dst []byte, lenInBytes int) ([]byte, error) {
 		h.Write(dst)
 		h.Write([]byte{sizeDomain})
 		b1 = h.Sum(nil)
-		copy(res[h.Size()*(i-1):h.Size()*i], b1)
+		copy(res[h.Size()*(i-1):], b1)
 	}
 	return res, nil
 }
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"math/big"
+	"math/bits"
 )
 
 //-------------------------------------------------------
@@ -53,6 +54,15 @@ func NafDecomposition(a *big.Int, result []int8) int {
 type Lattice struct {
 	V1, V2 [2]big.Int
 	Det    big.Int
+
+	// SplitBitLen bounds the bit length of the absolute values output by SplitScalarCT
+	SplitBitLen int
+
+	// fixed-width constants used by SplitScalarCT, on nbWords 64-bit words
+	nbWords int
+	g       [2][]uint64    // |round(2**(64*nbWords) * (V2[1], -V1[1]) / Det)|
+	gNeg    [2]bool        // signs of the above
+	v       [2][2][]uint64 // V1, V2 mod 2**(64*nbWords)
 }
 
 // PrecomputeLattice res such that res.V1, res.V2
@@ -123,6 +133,74 @@ func PrecomputeLattice(r, lambda *big.Int, res *Lattice) {
 	tmp[0].Mul(&res.V1[1], &res.V2[0])
 	res.Det.Mul(&res.V1[0], &res.V2[1]).Sub(&res.Det, &tmp[0])
 
+	precomputeSplitCT(r, res)
+}
+
+// maxSplitWords bounds the size of the scalars handled by SplitScalarCT
+const maxSplitWords = 8
+
+// precomputeSplitCT sets the constants used by SplitScalarCT
+//
+// The rounded quotients of SplitScalar are approximated by c = (s * g) >> (64*nbWords), with
+// g = round(2**(64*nbWords) * b / Det): since s < 2**(64*nbWords), c is within 1 of round(s*b/Det),
+// and the output (s, 0) - c1*V1 - c2*V2 is a combination of V1, V2 with coefficients less than 3/2.
+func precomputeSplitCT(r *big.Int, l *Lattice) {
+	l.nbWords = (r.BitLen() + 63) / 64
+	if l.nbWords > maxSplitWords {
+		panic("utils: modulus is too large for SplitScalarCT")
+	}
+	m := 64 * l.nbWords
+
+	var b [2]big.Int
+	b[0].Set(&l.V2[1])
+	b[1].Neg(&l.V1[1])
+	for i := 0; i < 2; i++ {
+		var g big.Int
+		g.Lsh(&b[i], uint(m))
+		rounding(&g, &l.Det, &g)
+		l.gNeg[i] = g.Sign() == -1
+		g.Abs(&g)
+		l.g[i] = toWords(&g, l.nbWords)
+	}
+
+	var mod big.Int
+	mod.Lsh(big.NewInt(1), uint(m))
+	for i, v := range [2]*[2]big.Int{&l.V1, &l.V2} {
+		for j := 0; j < 2; j++ {
+			var t big.Int
+			t.Mod(&v[j], &mod)
+			l.v[i][j] = toWords(&t, l.nbWords)
+		}
+	}
+
+	// |k_j| < 3/2 * (|V1[j]| + |V2[j]|)
+	l.SplitBitLen = 0
+	for j := 0; j < 2; j++ {
+		var bound, t big.Int
+		bound.Abs(&l.V1[j])
+		t.Abs(&l.V2[j])
+		bound.Add(&bound, &t).Mul(&bound, big.NewInt(3)).Rsh(&bound, 1).Add(&bound, big.NewInt(1))
+		if bound.BitLen() > l.SplitBitLen {
+			l.SplitBitLen = bound.BitLen()
+		}
+	}
+	if l.SplitBitLen >= m {
+		panic("utils: lattice is too large for SplitScalarCT")
+	}
+}
+
+// toWords returns the nbWords little endian 64-bit words of the non negative integer x
+func toWords(x *big.Int, nbWords int) []uint64 {
+	res := make([]uint64, nbWords)
+	var t big.Int
+	t.Set(x)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := 0; i < nbWords; i++ {
+		var w big.Int
+		res[i] = w.And(&t, mask).Uint64()
+		t.Rsh(&t, 64)
+	}
+	return res
 }
 
 // SplitScalar outputs u,v such that u+vlambda=s[r].
@@ -144,6 +222,89 @@ func SplitScalar(s *big.Int, l *Lattice) [2]big.Int {
 	return v
 }
 
+// SplitScalarCT is a fixed-width variant of SplitScalar, whose running time and memory
+// accesses don't depend on s.
+//
+// s is given as little endian 64-bit words, with s < 2**(64*len(s)), and len(s) must be the
+// number of words of the modulus l was computed with. SplitScalarCT writes |u|, |v| in k1, k2
+// (of the same length as s) such that u+vlambda=s[r], and returns their signs as 0 (positive)
+// or 1 (negative). |u| and |v| are less than 2**l.SplitBitLen. The outputs may differ from the
+// ones of SplitScalar by a lattice vector.
+func SplitScalarCT(k1, k2, s []uint64, l *Lattice) (neg1, neg2 uint64) {
+	n := l.nbWords
+	if len(s) != n || len(k1) != n || len(k2) != n {
+		panic("utils: wrong number of words in SplitScalarCT")
+	}
+
+	// c_i = (s * g_i) >> 64n, negated when g_i < 0 (the signs are public)
+	var c [2][maxSplitWords]uint64
+	var prod [2 * maxSplitWords]uint64
+	for i := 0; i < 2; i++ {
+		mulWords(prod[:2*n], s, l.g[i])
+		copy(c[i][:n], prod[n:2*n])
+		if l.gNeg[i] {
+			negWords(c[i][:n], 1)
+		}
+	}
+
+	// (k1, k2) = (s, 0) - c_1 * V1 - c_2 * V2, mod 2**64n
+	copy(k1, s)
+	for j := range k2 {
+		k2[j] = 0
+	}
+	for i := 0; i < 2; i++ {
+		mulWords(prod[:2*n], c[i][:n], l.v[i][0])
+		subWords(k1, prod[:n])
+		mulWords(prod[:2*n], c[i][:n], l.v[i][1])
+		subWords(k2, prod[:n])
+	}
+
+	// |k| < 2**(64n-1): the sign is the most significant bit
+	neg1 = k1[n-1] >> 63
+	neg2 = k2[n-1] >> 63
+	negWords(k1, neg1)
+	negWords(k2, neg2)
+	return
+}
+
+// mulWords sets res (of length 2*len(a)) to a*b, with len(a) == len(b)
+func mulWords(res, a, b []uint64) {
+	for i := range res {
+		res[i] = 0
+	}
+	for i := 0; i < len(a); i++ {
+		var carry uint64
+		for j := 0; j < len(b); j++ {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var c uint64
+			lo, c = bits.Add64(lo, res[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			res[i+j] = lo
+			carry = hi
+		}
+		res[i+len(b)] = carry
+	}
+}
+
+// subWords sets a to a-b mod 2**(64*len(a))
+func subWords(a, b []uint64) {
+	var borrow uint64
+	for i := range a {
+		a[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+}
+
+// negWords sets a to -a mod 2**(64*len(a)) if c == 1 and leaves it unchanged if c == 0
+func negWords(a []uint64, c uint64) {
+	mask := -c
+	carry := c
+	for i := range a {
+		a[i], carry = bits.Add64(a[i]^mask, 0, carry)
+	}
+}
+
 // sets res to the closest integer from n/d
 func rounding(n, d, res *big.Int) {
 	var dshift, r, one big.Int
@@ -57,3 +57,54 @@ func BenchmarkSplitting256(b *testing.B) {
 	}
 
 }
+
+func TestSplittingCT(t *testing.T) {
+
+	var lambda, r big.Int
+	var l Lattice
+
+	r.SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	lambda.SetString("4407920970296243842393367215006156084916469457145843978461", 10)
+
+	PrecomputeLattice(&r, &lambda, &l)
+
+	var one big.Int
+	one.SetUint64(1)
+	scalars := []big.Int{{}, one}
+	scalars = append(scalars, *new(big.Int).Sub(&r, &one), lambda)
+	var s big.Int
+	s.SetString("183927522224640574525727508854836440041603434369820418657580", 10)
+	for i := 0; i < 20; i++ {
+		scalars = append(scalars, *new(big.Int).Set(&s))
+		s.Mul(&s, &s).Add(&s, &one).Mod(&s, &r)
+	}
+
+	for _, s := range scalars {
+		var k1, k2 [4]uint64
+		sWords := toWords(&s, 4)
+		neg1, neg2 := SplitScalarCT(k1[:], k2[:], sWords, &l)
+
+		var v [2]big.Int
+		for i, k := range [2][4]uint64{k1, k2} {
+			for j := 3; j >= 0; j-- {
+				v[i].Lsh(&v[i], 64).Or(&v[i], new(big.Int).SetUint64(k[j]))
+			}
+			if v[i].BitLen() > l.SplitBitLen {
+				t.Fatal("split scalar is larger than SplitBitLen")
+			}
+		}
+		if neg1 == 1 {
+			v[0].Neg(&v[0])
+		}
+		if neg2 == 1 {
+			v[1].Neg(&v[1])
+		}
+
+		var _s big.Int
+		_s.Mul(&v[1], &lambda).Add(&_s, &v[0]).Sub(&_s, &s)
+		_s.Mod(&_s, &r)
+		if _s.Sign() != 0 {
+			t.Fatal("Error split scalar")
+		}
+	}
+}